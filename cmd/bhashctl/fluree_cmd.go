@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/flureelocal"
+	"github.com/hashgraph/bhash/internal/tools"
+)
+
+type flureeDatasetClient interface {
+	ListDatasets(ctx context.Context, ownerHandle string) ([]fluree.DatasetDescriptor, error)
+	DescribeDataset(ctx context.Context, ownerHandle, datasetName string) (*fluree.DatasetDescriptor, error)
+	DeleteDataset(ctx context.Context, ownerHandle, datasetName string) error
+}
+
+var (
+	flureeDatasetClientFactory = func(cfg fluree.Config) flureeDatasetClient {
+		return fluree.NewClient(cfg, nil)
+	}
+	confirmInput io.Reader = os.Stdin
+)
+
+func runFlureeLoad(args []string) {
+	fs := flag.NewFlagSet("fluree load", flag.ExitOnError)
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	ledger := fs.String("ledger", "", "Ledger identifier")
+	modules := newStringSliceFlag()
+	fs.Var(modules, "module", "Ontology module under ontology/src, e.g. token or alignment/aiao (may be repeated; default all)")
+	withExamples := fs.Bool("with-examples", false, "Also load the example graphs for each module")
+	maxNodes := fs.Int("max-nodes", fluree.DefaultBatchMaxNodes, "Maximum JSON-LD nodes per transaction")
+	maxBytes := fs.Int("max-bytes", fluree.DefaultBatchMaxBytes, "Maximum encoded insert size per transaction")
+	dryRun := fs.Bool("dry-run", false, "Convert and split the graphs without transacting them")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(*ledger) == "" {
+		fmt.Fprintln(errorWriter, "ledger is required")
+		os.Exit(1)
+	}
+
+	var client tools.Transactor
+	if !*dryRun {
+		client = flureeClientFactory(mustFlureeConfig(*apiToken, *tenant, *baseURL))
+	}
+	cfg := loadConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	result, err := tools.LoadOntology(ctx, cfg, client, tools.LoadOptions{
+		Ledger:       strings.TrimSpace(*ledger),
+		Modules:      modules.Values(),
+		WithExamples: *withExamples,
+		Limits:       fluree.BatchLimits{MaxNodes: *maxNodes, MaxBytes: *maxBytes},
+		DryRun:       *dryRun,
+		Progress:     errorWriter,
+	})
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(result)
+}
+
+func runFlureeListDatasets(args []string) {
+	fs := flag.NewFlagSet("fluree list-datasets", flag.ExitOnError)
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	owner := fs.String("owner", "", "Owner handle whose datasets are listed (defaults to the tenant handle)")
+	filter := datasetFilterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+
+	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
+	datasetFilter := filter.mustParse()
+	warnExperimentalDatasetRoute("list-datasets")
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	datasets, err := flureeDatasetClientFactory(cfg).ListDatasets(ctx, ownerOrTenant(*owner, cfg))
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(fluree.FilterDatasets(datasets, datasetFilter))
+}
+
+func runFlureeDescribeDataset(args []string) {
+	fs := flag.NewFlagSet("fluree describe-dataset", flag.ExitOnError)
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	owner := fs.String("owner", "", "Owner handle responsible for the dataset (defaults to the tenant handle)")
+	datasetName := fs.String("dataset-name", "", "Dataset name")
+	raw := fs.Bool("raw", false, "Print the response body Fluree returned instead of the parsed descriptor")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+
+	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
+	if *datasetName == "" {
+		fmt.Fprintln(errorWriter, "dataset-name is required")
+		os.Exit(1)
+	}
+	warnExperimentalDatasetRoute("describe-dataset")
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	dataset, err := flureeDatasetClientFactory(cfg).DescribeDataset(ctx, ownerOrTenant(*owner, cfg), *datasetName)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printResponse(dataset, dataset.Raw, *raw)
+}
+
+func runFlureeDeleteDataset(args []string) {
+	fs := flag.NewFlagSet("fluree delete-dataset", flag.ExitOnError)
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	owner := fs.String("owner", "", "Owner handle responsible for the datasets (defaults to the tenant handle)")
+	datasetName := fs.String("dataset-name", "", "Delete a single dataset by name")
+	filter := datasetFilterFlags(fs)
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt")
+	dryRun := fs.Bool("dry-run", false, "List the datasets that would be deleted without deleting them")
+	experimental := fs.Bool("experimental", false, "Allow deleting through the undocumented delete-dataset route on a non-local Fluree endpoint")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+
+	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
+	datasetFilter := filter.mustParse()
+	if (*datasetName == "") == datasetFilter.IsZero() {
+		fmt.Fprintln(errorWriter, "specify either --dataset-name or at least one of --tag, --name-prefix, --older-than")
+		os.Exit(1)
+	}
+
+	// The delete route is guessed, so a permanent delete against anything but
+	// the local stand-in needs an explicit opt-in.
+	if !cfg.IsLoopback() {
+		if !*experimental && !*dryRun {
+			fmt.Fprintf(errorWriter, "refusing to delete datasets on %s: the delete-dataset route is experimental and unverified against Fluree Cloud; point FLUREE_BASE_URL at fluree serve-local or pass --experimental\n", cfg.BaseURL)
+			os.Exit(1)
+		}
+		warnExperimentalDatasetRoute("delete-dataset")
+	}
+	client := flureeDatasetClientFactory(cfg)
+	ownerHandle := ownerOrTenant(*owner, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	var names []string
+	if *datasetName != "" {
+		names = []string{*datasetName}
+	} else {
+		datasets, err := client.ListDatasets(ctx, ownerHandle)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		for _, dataset := range fluree.FilterDatasets(datasets, datasetFilter) {
+			names = append(names, dataset.Name)
+		}
+	}
+
+	result := map[string]any{"owner": ownerHandle, "matched": names, "deleted": []string{}}
+	if len(names) == 0 || *dryRun {
+		result["dryRun"] = *dryRun
+		printJSON(result)
+		return
+	}
+	if !*yes && !confirmDelete(ownerHandle, names) {
+		fmt.Fprintln(errorWriter, "aborted; no datasets deleted")
+		os.Exit(1)
+	}
+
+	deleted := make([]string, 0, len(names))
+	for _, name := range names {
+		if err := client.DeleteDataset(ctx, ownerHandle, name); err != nil {
+			result["deleted"] = deleted
+			printJSON(result)
+			fmt.Fprintf(errorWriter, "delete %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		deleted = append(deleted, name)
+	}
+	result["deleted"] = deleted
+	printJSON(result)
+}
+
+type datasetFilterFlagValues struct {
+	tag        *string
+	namePrefix *string
+	olderThan  *string
+}
+
+func datasetFilterFlags(fs *flag.FlagSet) datasetFilterFlagValues {
+	return datasetFilterFlagValues{
+		tag:        fs.String("tag", "", "Only match datasets carrying this tag"),
+		namePrefix: fs.String("name-prefix", "", "Only match datasets whose name starts with this prefix"),
+		olderThan:  fs.String("older-than", "", "Only match datasets created more than this long ago (e.g. 36h, 7d)"),
+	}
+}
+
+func (v datasetFilterFlagValues) mustParse() fluree.DatasetFilter {
+	age, err := fluree.ParseAge(*v.olderThan)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	return fluree.DatasetFilter{Tag: *v.tag, NamePrefix: *v.namePrefix, OlderThan: age}
+}
+
+func ownerOrTenant(owner string, cfg fluree.Config) string {
+	if owner != "" {
+		return owner
+	}
+	return cfg.TenantHandle
+}
+
+// warnExperimentalDatasetRoute notes that the dataset management routes are
+// not documented by Fluree Cloud.
+func warnExperimentalDatasetRoute(command string) {
+	fmt.Fprintf(errorWriter, "warning: fluree %s is experimental; its Fluree Cloud route is undocumented and unverified\n", command)
+}
+
+// confirmDelete lists the datasets about to be removed and asks the operator
+// to type "yes".
+func confirmDelete(owner string, names []string) bool {
+	fmt.Fprintf(errorWriter, "The following %d dataset(s) owned by %s will be deleted:\n", len(names), owner)
+	for _, name := range names {
+		fmt.Fprintf(errorWriter, "  %s\n", name)
+	}
+	fmt.Fprint(errorWriter, "Type 'yes' to continue: ")
+	answer, err := bufio.NewReader(confirmInput).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	return strings.TrimSpace(strings.ToLower(answer)) == "yes"
+}
+
+func runFlureeServeLocal(args []string) {
+	fs := flag.NewFlagSet("fluree serve-local", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8090", "Address to listen on")
+	dataDir := fs.String("data-dir", "", "Directory for persisted ledgers (default in-memory)")
+	token := fs.String("token", "", "Bearer token required from clients, or a secret reference (default no authentication)")
+	quiet := fs.Bool("quiet", false, "Do not log requests")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+
+	requiredToken := strings.TrimSpace(*token)
+	if requiredToken != "" {
+		resolved, err := secretResolver.Resolve(context.Background(), requiredToken)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		requiredToken = resolved
+	}
+	opts := flureelocal.Options{DataDir: strings.TrimSpace(*dataDir), Token: requiredToken}
+	if !*quiet {
+		opts.Log = errorWriter
+	}
+	handler, err := flureelocal.New(opts)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+
+	baseURL := "http://" + listener.Addr().String()
+	fmt.Fprintf(errorWriter, "Local Fluree server listening on %s (%d ledgers loaded)\n", baseURL, len(handler.Ledgers()))
+	fmt.Fprintf(errorWriter, "Point bhashctl at it with FLUREE_BASE_URL=%s; any FLUREE_HANDLE is accepted.\n", baseURL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// readResumeMarker loads the marker written by an earlier fluree transact
+// run. A missing file means there is nothing to resume.
+func readResumeMarker(path string) (*fluree.ResumeMarker, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read resume marker: %w", err)
+	}
+	var marker fluree.ResumeMarker
+	if err := json.Unmarshal(data, &marker); err != nil {
+		return nil, fmt.Errorf("decode resume marker %s: %w", path, err)
+	}
+	return &marker, nil
+}
+
+// writeResumeMarker replaces the marker file so an interrupted write never
+// leaves a truncated marker behind.
+func writeResumeMarker(path string, marker fluree.ResumeMarker) error {
+	data, err := json.MarshalIndent(marker, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write resume marker: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write resume marker: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/flureelocal"
+)
+
+type fakeDatasetClient struct {
+	datasets []fluree.DatasetDescriptor
+	deleted  []string
+}
+
+func (f *fakeDatasetClient) ListDatasets(context.Context, string) ([]fluree.DatasetDescriptor, error) {
+	return f.datasets, nil
+}
+
+func (f *fakeDatasetClient) DescribeDataset(_ context.Context, owner, name string) (*fluree.DatasetDescriptor, error) {
+	raw := json.RawMessage(fmt.Sprintf(`{"dataset":{"name":%q,"owner":%q},"quota":7}`, name, owner))
+	return &fluree.DatasetDescriptor{Name: name, Owner: owner, Raw: raw}, nil
+}
+
+func (f *fakeDatasetClient) DeleteDataset(_ context.Context, _ string, name string) error {
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func TestRunFlureeDeleteDatasetConfirmsBulkDelete(t *testing.T) {
+	t.Setenv("FLUREE_API_TOKEN", "token")
+	t.Setenv("FLUREE_HANDLE", "tenant")
+
+	client := &fakeDatasetClient{datasets: []fluree.DatasetDescriptor{
+		{Name: "hedera-topics-1"},
+		{Name: "hedera-topics-2"},
+		{Name: "core"},
+	}}
+	originalFactory := flureeDatasetClientFactory
+	defer func() { flureeDatasetClientFactory = originalFactory }()
+	flureeDatasetClientFactory = func(fluree.Config) flureeDatasetClient { return client }
+
+	originalInput := confirmInput
+	defer func() { confirmInput = originalInput }()
+	confirmInput = strings.NewReader("yes\n")
+
+	buf := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	originalWriter, originalError := outputWriter, errorWriter
+	outputWriter, errorWriter = buf, stderr
+	defer func() { outputWriter, errorWriter = originalWriter, originalError }()
+
+	runFlureeDeleteDataset([]string{"--name-prefix", "hedera-topics-", "--experimental"})
+
+	if !reflect.DeepEqual(client.deleted, []string{"hedera-topics-1", "hedera-topics-2"}) {
+		t.Fatalf("unexpected deletes: %v", client.deleted)
+	}
+	if !strings.Contains(stderr.String(), "2 dataset(s) owned by tenant") {
+		t.Fatalf("expected confirmation prompt, got %q", stderr.String())
+	}
+	var output map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(output["deleted"].([]any)) != 2 {
+		t.Fatalf("unexpected output: %v", output)
+	}
+
+	if !strings.Contains(stderr.String(), "experimental") {
+		t.Fatalf("expected a remote delete to warn that the route is experimental, got %q", stderr.String())
+	}
+
+	t.Setenv("FLUREE_BASE_URL", "http://127.0.0.1:8090")
+	client.deleted = nil
+	buf.Reset()
+	stderr.Reset()
+	confirmInput = strings.NewReader("yes\n")
+	runFlureeDeleteDataset([]string{"--dataset-name", "core"})
+	if !reflect.DeepEqual(client.deleted, []string{"core"}) {
+		t.Fatalf("unexpected single delete: %v", client.deleted)
+	}
+	if !strings.Contains(stderr.String(), "1 dataset(s) owned by tenant") || strings.Contains(stderr.String(), "experimental") {
+		t.Fatalf("expected a local single delete to confirm without the experimental warning, got %q", stderr.String())
+	}
+}
+
+func TestRunFlureeDescribeDatasetPrintsRawOnlyWhenAsked(t *testing.T) {
+	t.Setenv("FLUREE_API_TOKEN", "token")
+	t.Setenv("FLUREE_HANDLE", "tenant")
+
+	originalFactory := flureeDatasetClientFactory
+	defer func() { flureeDatasetClientFactory = originalFactory }()
+	flureeDatasetClientFactory = func(fluree.Config) flureeDatasetClient { return &fakeDatasetClient{} }
+
+	buf := &bytes.Buffer{}
+	originalWriter, originalError := outputWriter, errorWriter
+	outputWriter, errorWriter = buf, &bytes.Buffer{}
+	defer func() { outputWriter, errorWriter = originalWriter, originalError }()
+
+	runFlureeDescribeDataset([]string{"--dataset-name", "core"})
+	var parsed map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if want := map[string]any{"name": "core", "owner": "tenant"}; !reflect.DeepEqual(parsed, want) {
+		t.Fatalf("expected only the typed fields, got %v", parsed)
+	}
+
+	buf.Reset()
+	runFlureeDescribeDataset([]string{"--dataset-name", "core", "--raw"})
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("decode raw output: %v", err)
+	}
+	if raw["quota"] != float64(7) || raw["name"] != nil {
+		t.Fatalf("expected the response body Fluree returned, got %v", raw)
+	}
+}
+
+func TestRunFlureeTransactStreamsBatchesWithResumeMarker(t *testing.T) {
+	server, err := flureelocal.New(flureelocal.Options{})
+	if err != nil {
+		t.Fatalf("flureelocal.New returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	t.Setenv("FLUREE_API_TOKEN", "token")
+	t.Setenv("FLUREE_HANDLE", "tenant")
+	t.Setenv("FLUREE_BASE_URL", httpServer.URL)
+
+	dir := t.TempDir()
+	insertPath := filepath.Join(dir, "nodes.ndjson")
+	var ndjson strings.Builder
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&ndjson, "{\"@id\":\"https://example.org/%d\",\"https://example.org/n\":%d}\n", i, i)
+	}
+	if err := os.WriteFile(insertPath, []byte(ndjson.String()), 0o644); err != nil {
+		t.Fatalf("write insert file: %v", err)
+	}
+	markerPath := filepath.Join(dir, "marker.json")
+
+	run := func() (fluree.StreamResult, string) {
+		buf := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		originalWriter, originalError := outputWriter, errorWriter
+		outputWriter, errorWriter = buf, stderr
+		defer func() { outputWriter, errorWriter = originalWriter, originalError }()
+
+		runFlureeTransact([]string{"--ledger", "tenant/stream", "--insert", insertPath, "--max-nodes", "2", "--resume", markerPath})
+		var result fluree.StreamResult
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("decode output %q: %v", buf.String(), err)
+		}
+		return result, stderr.String()
+	}
+
+	result, progress := run()
+	if len(result.Batches) != 3 || result.Marker.Nodes != 5 || result.Batches[2].Receipt.T != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if !strings.Contains(progress, "batch 3: nodes 4-4") {
+		t.Fatalf("expected per-batch progress, got %q", progress)
+	}
+	marker, err := readResumeMarker(markerPath)
+	if err != nil || marker == nil || marker.Nodes != 5 || marker.Batches != 3 {
+		t.Fatalf("unexpected marker %+v (err %v)", marker, err)
+	}
+
+	result, progress = run()
+	if len(result.Batches) != 0 || result.Skipped != 5 {
+		t.Fatalf("expected rerun to skip committed nodes, got %+v", result)
+	}
+	if !strings.Contains(progress, "skipped 5 nodes") {
+		t.Fatalf("expected skip notice, got %q", progress)
+	}
+}
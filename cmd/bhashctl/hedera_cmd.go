@@ -17,7 +17,7 @@ type hederaNetworkFactoryFunc func(bhedera.Config, bool) (bhedera.Network, func(
 type flureeClientFactoryFunc func(fluree.Config) flureeTransactor
 
 type flureeTransactor interface {
-	Transact(context.Context, fluree.TransactionRequest) (any, error)
+	Transact(context.Context, fluree.TransactionRequest) (*fluree.TransactionReceipt, error)
 }
 
 var (
@@ -48,6 +48,8 @@ func runHedera(args []string) {
 	switch args[0] {
 	case "bootstrap":
 		runHederaBootstrap(args[1:])
+	case "reconcile":
+		runHederaReconcile(args[1:])
 	default:
 		hederaUsage()
 		os.Exit(1)
@@ -55,7 +57,7 @@ func runHedera(args []string) {
 }
 
 func hederaUsage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s hedera <bootstrap> [options]\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(errorWriter, "Usage: %s hedera <bootstrap|reconcile> [options]\n", filepath.Base(os.Args[0]))
 }
 
 func runHederaBootstrap(args []string) {
@@ -64,26 +66,29 @@ func runHederaBootstrap(args []string) {
 	ledger := fs.String("ledger", "", "Fluree ledger identifier (owner/dataset)")
 	simulate := fs.Bool("simulate", true, "Use the deterministic mock Hedera network")
 	commit := fs.Bool("commit", false, "Submit the generated transaction to Fluree")
+	upsert := fs.Bool("upsert", false, "Replace the exported properties of existing subjects instead of only inserting (one transaction per artefact)")
+	sign := fs.Bool("sign", false, "Sign the exported JSON-LD with the operator key (requires operator credentials)")
+	evidenceOut := fs.String("evidence-out", "", "Write the exported JSON-LD to this path, and its signature to <path>.sig.json with --sign")
 	networkOverride := fs.String("network", "", "Hedera network (overrides $HEDERA_NETWORK)")
 	operatorID := fs.String("operator-id", "", "Hedera operator account ID")
-	operatorKey := fs.String("operator-key", "", "Hedera operator private key")
+	operatorKey := fs.String("operator-key", "", "Hedera operator private key or secret reference (file:, env:, cmd:, vault:)")
 	mirrorURL := fs.String("mirror-url", "", "Hedera mirror network URL")
-	apiToken := fs.String("api-token", "", "Fluree API token (defaults to $FLUREE_API_TOKEN)")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
 	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
 	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 
 	if *specPath == "" {
-		fmt.Fprintln(os.Stderr, "spec is required")
+		fmt.Fprintln(errorWriter, "spec is required")
 		os.Exit(1)
 	}
 
 	spec, err := bhedera.LoadBootstrapSpec(*specPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 
@@ -92,7 +97,7 @@ func runHederaBootstrap(args []string) {
 		ledgerID = strings.TrimSpace(spec.Ledger)
 	}
 	if ledgerID == "" {
-		fmt.Fprintln(os.Stderr, "ledger is required")
+		fmt.Fprintln(errorWriter, "ledger is required")
 		os.Exit(1)
 	}
 
@@ -101,7 +106,7 @@ func runHederaBootstrap(args []string) {
 		return value, ok
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 	cfg = cfg.WithOverrides(*networkOverride, *operatorID, *operatorKey, *mirrorURL)
@@ -109,17 +114,26 @@ func runHederaBootstrap(args []string) {
 		cfg.Network = spec.Network
 	}
 	if err := cfg.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	cfg, err = cfg.ResolveSecrets(context.Background(), secretResolver)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 	if !*simulate && !cfg.HasOperator() {
-		fmt.Fprintln(os.Stderr, "operator credentials are required when simulate=false")
+		fmt.Fprintln(errorWriter, "operator credentials are required when simulate=false")
+		os.Exit(1)
+	}
+	if *sign && !cfg.HasOperator() {
+		fmt.Fprintln(errorWriter, "operator credentials are required for --sign")
 		os.Exit(1)
 	}
 
 	network, closer, err := hederaNetworkFactory(cfg, *simulate)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 	if closer != nil {
@@ -131,18 +145,47 @@ func runHederaBootstrap(args []string) {
 	defer cancel()
 	result, err := bootstrapper.Execute(ctx, spec)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
-	transaction := result.Transaction(ledgerID)
+	transactions := []fluree.TransactionRequest{result.Transaction(ledgerID)}
+	if *upsert {
+		transactions = result.UpsertTransactions(ledgerID)
+	}
 
 	output := map[string]any{
-		"network":     result.Network,
-		"ledger":      ledgerID,
-		"accounts":    result.Accounts,
-		"topics":      result.Topics,
-		"tokens":      result.Tokens,
-		"transaction": transaction,
+		"network":  result.Network,
+		"ledger":   ledgerID,
+		"accounts": result.Accounts,
+		"topics":   result.Topics,
+		"tokens":   result.Tokens,
+	}
+	if *upsert {
+		output["transactions"] = transactions
+	} else {
+		output["transaction"] = transactions[0]
+	}
+
+	evidence := bhedera.EvidenceDocument(transactions)
+	var signature *bhedera.DatasetSignature
+	if *sign {
+		signature, err = bhedera.SignDataset(evidence, cfg.OperatorAccountID, cfg.OperatorPrivateKey, signatureNow())
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		output["signature"] = signature
+	}
+	if *evidenceOut != "" {
+		sigPath, err := writeEvidence(*evidenceOut, evidence, signature)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		output["evidence"] = *evidenceOut
+		if sigPath != "" {
+			output["evidenceSignature"] = sigPath
+		}
 	}
 
 	if *commit {
@@ -150,13 +193,100 @@ func runHederaBootstrap(args []string) {
 		client := flureeClientFactory(cfg)
 		respCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 		defer cancel()
-		resp, err := client.Transact(respCtx, transaction)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			os.Exit(1)
+		responses := make([]*fluree.TransactionReceipt, 0, len(transactions))
+		for _, transaction := range transactions {
+			resp, err := client.Transact(respCtx, transaction)
+			if err != nil {
+				fmt.Fprintf(errorWriter, "%v\n", err)
+				os.Exit(1)
+			}
+			responses = append(responses, resp)
+		}
+		if *upsert {
+			output["flureeResponses"] = responses
+		} else {
+			output["flureeResponse"] = responses[0]
 		}
-		output["flureeResponse"] = resp
 	}
 
 	printJSON(output)
 }
+
+func runHederaReconcile(args []string) {
+	fs := flag.NewFlagSet("hedera reconcile", flag.ExitOnError)
+	ledger := fs.String("ledger", "", "Fluree ledger identifier (owner/dataset)")
+	networkOverride := fs.String("network", "", "Hedera network (defaults to the network recorded in the ledger, then $HEDERA_NETWORK)")
+	mirrorRESTURL := fs.String("mirror-rest-url", "", "Mirror node REST API URL (defaults to $HEDERA_MIRROR_REST_URL or the public mirror node of the network)")
+	emit := fs.Bool("emit-transaction", false, "Include the transactions that correct the drift in the output")
+	commit := fs.Bool("commit", false, "Submit the correcting transactions to Fluree")
+	failOnDrift := fs.Bool("fail-on-drift", false, "Exit with status 2 when artefacts are missing from the network or drift was not corrected with --commit")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	ledgerID := strings.TrimSpace(*ledger)
+	if ledgerID == "" {
+		fmt.Fprintln(errorWriter, "ledger is required")
+		os.Exit(1)
+	}
+
+	client := fluree.NewClient(mustFlureeConfig(*apiToken, *tenant, *baseURL), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	results, err := client.QuerySPARQL(ctx, ledgerID, bhedera.LedgerStateQuery)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	state, err := bhedera.LedgerState(results)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+
+	network := strings.TrimSpace(*networkOverride)
+	if network == "" {
+		network = state.Network
+	}
+	mirrorURL := mirrorRESTBaseURL(*mirrorRESTURL, network)
+
+	report, err := bhedera.Reconcile(ctx, state, bhedera.NewMirrorClient(mirrorURL, nil))
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	transactions := report.Corrections.UpsertTransactions(ledgerID)
+	output := map[string]any{
+		"ledger":  ledgerID,
+		"network": network,
+		"mirror":  mirrorURL,
+		"checked": report.Checked,
+		"drift":   report.Drift,
+		"missing": report.Missing,
+	}
+	if *emit || *commit {
+		output["transactions"] = transactions
+	}
+	if *commit {
+		responses := make([]*fluree.TransactionReceipt, 0, len(transactions))
+		for _, transaction := range transactions {
+			resp, err := client.Transact(ctx, transaction)
+			if err != nil {
+				fmt.Fprintf(errorWriter, "%v\n", err)
+				os.Exit(1)
+			}
+			responses = append(responses, resp)
+		}
+		output["flureeResponses"] = responses
+	}
+	printJSON(output)
+
+	// Committed corrections resolve drift, but missing artefacts still need a
+	// person to look at them.
+	if *failOnDrift && (len(report.Missing) > 0 || (len(report.Drift) > 0 && !*commit)) {
+		os.Exit(2)
+	}
+}
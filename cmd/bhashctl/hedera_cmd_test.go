@@ -4,18 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/flureelocal"
 	bhedera "github.com/hashgraph/bhash/internal/hedera"
 )
 
-type flureeClientFunc func(context.Context, fluree.TransactionRequest) (any, error)
+type flureeClientFunc func(context.Context, fluree.TransactionRequest) (*fluree.TransactionReceipt, error)
 
-func (f flureeClientFunc) Transact(ctx context.Context, req fluree.TransactionRequest) (any, error) {
+func (f flureeClientFunc) Transact(ctx context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
 	return f(ctx, req)
 }
 
@@ -54,9 +57,9 @@ func TestRunHederaBootstrap(t *testing.T) {
 	defer func() { flureeClientFactory = originalFluree }()
 	var capturedLedger string
 	flureeClientFactory = func(cfg fluree.Config) flureeTransactor {
-		return flureeClientFunc(func(ctx context.Context, req fluree.TransactionRequest) (any, error) {
+		return flureeClientFunc(func(ctx context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
 			capturedLedger = req.Ledger
-			return map[string]any{"status": "ok"}, nil
+			return &fluree.TransactionReceipt{Ledger: req.Ledger, T: 1}, nil
 		})
 	}
 
@@ -111,9 +114,9 @@ func TestRunHederaBootstrapCommit(t *testing.T) {
 	originalFluree := flureeClientFactory
 	defer func() { flureeClientFactory = originalFluree }()
 	flureeClientFactory = func(cfg fluree.Config) flureeTransactor {
-		return flureeClientFunc(func(ctx context.Context, req fluree.TransactionRequest) (any, error) {
+		return flureeClientFunc(func(ctx context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
 			committed = true
-			return map[string]any{"status": "ok"}, nil
+			return &fluree.TransactionReceipt{Ledger: req.Ledger, T: 1}, nil
 		})
 	}
 
@@ -132,3 +135,119 @@ func TestRunHederaBootstrapCommit(t *testing.T) {
 		t.Fatalf("expected fluree transact to be called")
 	}
 }
+
+func TestRunHederaBootstrapUpsert(t *testing.T) {
+	tempDir := t.TempDir()
+	specPath := filepath.Join(tempDir, "spec.json")
+	spec := bhedera.BootstrapSpec{
+		Accounts: []bhedera.AccountSpec{{Alias: "treasury"}},
+		Topics:   []bhedera.TopicSpec{{Alias: "consensus"}},
+	}
+	file, err := os.Create(specPath)
+	if err != nil {
+		t.Fatalf("create spec: %v", err)
+	}
+	if err := json.NewEncoder(file).Encode(spec); err != nil {
+		t.Fatalf("encode spec: %v", err)
+	}
+	file.Close()
+
+	originalFactory := hederaNetworkFactory
+	defer func() { hederaNetworkFactory = originalFactory }()
+	hederaNetworkFactory = func(cfg bhedera.Config, simulate bool) (bhedera.Network, func(), error) {
+		return bhedera.NewMockNetwork(cfg.Network, bhedera.WithStartingIDs(1, 1, 1)), func() {}, nil
+	}
+
+	var committed []fluree.TransactionRequest
+	originalFluree := flureeClientFactory
+	defer func() { flureeClientFactory = originalFluree }()
+	flureeClientFactory = func(cfg fluree.Config) flureeTransactor {
+		return flureeClientFunc(func(ctx context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
+			committed = append(committed, req)
+			return &fluree.TransactionReceipt{Ledger: req.Ledger, T: int64(len(committed))}, nil
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	originalWriter := outputWriter
+	outputWriter = buf
+	defer func() { outputWriter = originalWriter }()
+
+	t.Setenv("FLUREE_API_TOKEN", "env-token")
+	t.Setenv("FLUREE_HANDLE", "env-tenant")
+	t.Setenv("FLUREE_BASE_URL", "http://env.example")
+
+	runHederaBootstrap([]string{"--spec", specPath, "--ledger", "tenant/dataset", "--upsert", "--commit", "--api-token", "token", "--tenant", "tenant", "--base-url", "http://example"})
+
+	if len(committed) != 2 {
+		t.Fatalf("expected one transaction per artefact, got %d", len(committed))
+	}
+	for _, req := range committed {
+		if len(req.Where) == 0 || len(req.Delete) != len(req.Where) || len(req.Insert) != 1 {
+			t.Fatalf("expected a conditional replace, got %+v", req)
+		}
+	}
+	var output map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if _, ok := output["transaction"]; ok {
+		t.Fatalf("did not expect a single transaction in upsert output")
+	}
+	if responses := output["flureeResponses"].([]any); len(responses) != 2 {
+		t.Fatalf("unexpected responses: %v", responses)
+	}
+}
+
+func TestRunHederaReconcile(t *testing.T) {
+	server, err := flureelocal.New(flureelocal.Options{})
+	if err != nil {
+		t.Fatalf("flureelocal.New returned error: %v", err)
+	}
+	flureeServer := httptest.NewServer(server)
+	defer flureeServer.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/accounts/0.0.1001" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"account":"0.0.1001","memo":"Rotated","key":{"_type":"ED25519","key":"abcd"},"deleted":false}`))
+	}))
+	defer mirror.Close()
+
+	t.Setenv("FLUREE_API_TOKEN", "env-token")
+	t.Setenv("FLUREE_HANDLE", "env-tenant")
+	t.Setenv("FLUREE_BASE_URL", flureeServer.URL)
+
+	client := fluree.NewClient(fluree.Config{APIToken: "token", TenantHandle: "tenant", BaseURL: flureeServer.URL}, nil)
+	exported := bhedera.BootstrapResult{Network: "testnet", Accounts: []bhedera.AccountRecord{{AccountID: "0.0.1001", Memo: "Treasury", PublicKey: "abcd"}}}
+	if _, err := client.Transact(context.Background(), exported.Transaction("tenant/dataset")); err != nil {
+		t.Fatalf("Transact returned error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	originalWriter := outputWriter
+	outputWriter = buf
+	defer func() { outputWriter = originalWriter }()
+
+	runHederaReconcile([]string{"--ledger", "tenant/dataset", "--mirror-rest-url", mirror.URL, "--commit", "--api-token", "token", "--tenant", "tenant", "--base-url", flureeServer.URL})
+
+	var output struct {
+		Network string          `json:"network"`
+		Drift   []bhedera.Drift `json:"drift"`
+		Commits []any           `json:"flureeResponses"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if output.Network != "testnet" || len(output.Drift) != 1 || output.Drift[0].Field != "memo" || len(output.Commits) != 1 {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	runHederaReconcile([]string{"--ledger", "tenant/dataset", "--mirror-rest-url", mirror.URL, "--fail-on-drift", "--api-token", "token", "--tenant", "tenant", "--base-url", flureeServer.URL})
+	output.Drift = nil
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil || len(output.Drift) != 0 {
+		t.Fatalf("expected no drift after commit, got %s (err %v)", buf.String(), err)
+	}
+}
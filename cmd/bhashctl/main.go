@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,15 +10,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/secrets"
 	"github.com/hashgraph/bhash/internal/tools"
 )
 
-var outputWriter io.Writer = os.Stdout
+var (
+	outputWriter io.Writer = os.Stdout
+	errorWriter  io.Writer = secrets.NewRedactingWriter(os.Stderr)
+	// secretResolver dereferences file:, env:, cmd: and vault: references in
+	// credentials before they are used.
+	secretResolver = secrets.DefaultResolver()
+)
 
 func main() {
 	if len(os.Args) < 2 {
@@ -27,14 +38,34 @@ func main() {
 	switch os.Args[1] {
 	case "install":
 		runInstall(os.Args[2:])
+	case "docs":
+		runDocs(os.Args[2:])
+	case "report":
+		runReport(os.Args[2:])
 	case "shacl":
 		runShacl(os.Args[2:])
+	case "reason":
+		runReason(os.Args[2:])
 	case "sparql":
 		runSparql(os.Args[2:])
+	case "template":
+		runTemplate(os.Args[2:])
+	case "manifest":
+		runManifest(os.Args[2:])
+	case "mappings":
+		runMappings(os.Args[2:])
 	case "fluree":
 		runFluree(os.Args[2:])
 	case "hedera":
 		runHedera(os.Args[2:])
+	case "secrets":
+		runSecrets(os.Args[2:])
+	case "release":
+		runRelease(os.Args[2:])
+	case "sign":
+		runSign(os.Args[2:])
+	case "verify":
+		runVerify(os.Args[2:])
 	default:
 		usage()
 		os.Exit(1)
@@ -42,7 +73,7 @@ func main() {
 }
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s <install|shacl|sparql|fluree|hedera> [options]\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(errorWriter, "Usage: %s <install|docs|reason|report|shacl|sparql|template|manifest|mappings|fluree|hedera|secrets|release|sign|verify> [options]\n", filepath.Base(os.Args[0]))
 }
 
 func runInstall(args []string) {
@@ -50,7 +81,7 @@ func runInstall(args []string) {
 	robotVersion := fs.String("robot-version", tools.DefaultRobotVersion, "ROBOT version to install")
 	shaclVersion := fs.String("shacl-version", tools.DefaultShaclVersion, "TopBraid SHACL distribution version")
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 
@@ -59,41 +90,322 @@ func runInstall(args []string) {
 	cfg.ShaclVersion = *shaclVersion
 
 	if err := tools.InstallRobot(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "install robot: %v\n", err)
+		fmt.Fprintf(errorWriter, "install robot: %v\n", err)
 		os.Exit(1)
 	}
 	if err := tools.InstallShacl(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "install shacl: %v\n", err)
+		fmt.Fprintf(errorWriter, "install shacl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runDocs renders the ontology documentation site.
+func runDocs(args []string) {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	out := fs.String("out", "", "Directory to write the site to (default build/docs)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	dir, err := tools.RunDocs(cfg, tools.DocsOptions{OutDir: *out})
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(outputWriter, "documentation written to %s\n", dir)
+}
+
+// runReason classifies the ontology modules with the managed ROBOT install
+// and explains any unsatisfiable classes or inconsistencies.
+func runReason(args []string) {
+	fs := flag.NewFlagSet("reason", flag.ExitOnError)
+	modules := newStringSliceFlag()
+	fs.Var(modules, "module", "Ontology module to reason over, e.g. token or alignment/aiao (may be repeated; default all)")
+	reasoner := fs.String("reasoner", "ELK", "Reasoner to use (ELK or HermiT)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	opts := tools.ReasonOptions{Modules: modules.Values(), Reasoner: *reasoner, Output: outputWriter}
+	if _, err := tools.RunReason(cfg, opts); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runReport runs ROBOT report over the ontology modules and fails on errors
+// missing from the baseline.
+func runReport(args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	modules := newStringSliceFlag()
+	fs.Var(modules, "module", "Ontology module to report on (may be repeated; default all)")
+	profile := fs.String("profile", "", "ROBOT report profile replacing ROBOT's default profile")
+	levels := fs.String("levels", "", "Rule level overrides applied to ROBOT's findings (default tests/report/levels.txt)")
+	baseline := fs.String("baseline", "", "Accepted issues (default tests/report/baseline.json)")
+	updateBaseline := fs.Bool("update-baseline", false, "Accept every issue found by rewriting the baseline")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	opts := tools.ReportOptions{
+		Modules:        modules.Values(),
+		Profile:        *profile,
+		Levels:         *levels,
+		Baseline:       *baseline,
+		UpdateBaseline: *updateBaseline,
+		Output:         outputWriter,
+	}
+	if _, err := tools.RunReport(cfg, opts); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 }
 
 func runShacl(args []string) {
 	fs := flag.NewFlagSet("shacl", flag.ExitOnError)
+	suite := fs.Bool("suite", false, "Run the negative suite under tests/shacl and check each report against its expected violations")
+	reportFormat := fs.String("report", "text", "Report format (text, json or sarif)")
+	out := fs.String("out", "", "Write the report to this file instead of stdout")
+	failOn := fs.String("fail-on", "violation", "Lowest severity that fails validation (violation, warning or info)")
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	if *suite {
+		if err := tools.RunShaclSuite(cfg, tools.ShaclSuiteOptions{Output: outputWriter}); err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	var writeReport func(*tools.ShaclReport, io.Writer) error
+	switch *reportFormat {
+	case "text":
+		writeReport = (*tools.ShaclReport).WriteText
+	case "json":
+		writeReport = (*tools.ShaclReport).WriteJSON
+	case "sarif":
+		writeReport = (*tools.ShaclReport).WriteSARIF
+	default:
+		fmt.Fprintf(errorWriter, "unsupported report format %q\n", *reportFormat)
+		os.Exit(1)
+	}
+	report, runErr := tools.RunShacl(cfg, tools.ShaclOptions{FailOn: *failOn})
+	if report != nil {
+		if err := writeReportFile(*out, func(w io.Writer) error { return writeReport(report, w) }); err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	if runErr != nil {
+		fmt.Fprintf(errorWriter, "%v\n", runErr)
+		os.Exit(1)
+	}
+}
+
+// runTemplate runs the ROBOT templates and reports drift from the
+// hand-written modules.
+func runTemplate(args []string) {
+	fs := flag.NewFlagSet("template", flag.ExitOnError)
+	mappings := fs.Bool("mappings", false, "Also template the docs/mappings crosswalks and check their source documents")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	if _, err := tools.RunTemplates(cfg, tools.TemplateOptions{Mappings: *mappings, Output: outputWriter}); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runManifest checks tests/manifest.json against the competency queries and
+// shapes in the tree.
+func runManifest(args []string) {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintf(errorWriter, "Usage: %s manifest validate\n", filepath.Base(os.Args[0]))
+		os.Exit(1)
+	}
+	fs := flag.NewFlagSet("manifest validate", flag.ExitOnError)
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	cfg := loadConfig()
+	problems, err := tools.ValidateManifest(cfg)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(errorWriter, problem)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(errorWriter, "%s has %d problem(s)\n", cfg.ManifestPath(), len(problems))
+		os.Exit(1)
+	}
+	fmt.Fprintf(outputWriter, "%s covers every query and shape\n", cfg.ManifestPath())
+}
+
+func runMappings(args []string) {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintf(errorWriter, "Usage: %s mappings check\n", filepath.Base(os.Args[0]))
+		os.Exit(1)
+	}
+	fs := flag.NewFlagSet("mappings check", flag.ExitOnError)
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 	cfg := loadConfig()
-	if err := tools.RunShacl(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+	check, err := tools.CheckMappings(cfg)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := check.WriteCoverage(outputWriter); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	for _, problem := range check.Problems {
+		fmt.Fprintln(errorWriter, problem)
+	}
+	if len(check.Problems) > 0 {
+		fmt.Fprintf(errorWriter, "docs/mappings has %d problem(s)\n", len(check.Problems))
 		os.Exit(1)
 	}
 }
 
 func runSparql(args []string) {
 	fs := flag.NewFlagSet("sparql", flag.ExitOnError)
+	backendName := fs.String("backend", "robot", "Query backend (robot or fluree)")
+	ledger := fs.String("ledger", "", "Fluree ledger identifier (required for --backend fluree)")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	reportFormat := fs.String("report", "text", "Report format (text, json or junit)")
+	out := fs.String("out", "", "Write the report to this file instead of stdout")
+	queries := fs.String("query", "", "Comma-separated query names or glob patterns to run, e.g. cq-comp-* (default all)")
+	tags := newStringSliceFlag()
+	fs.Var(tags, "tag", "Run only queries whose front matter declares key=value, e.g. module=token (may be repeated)")
+	workers := fs.Int("workers", 1, "Number of queries to run concurrently")
+	updateFixtures := fs.Bool("update-fixtures", false, "Overwrite fixtures of queries whose results differ or have no fixture")
+	only := fs.String("only", "", "Comma-separated query names or glob patterns limiting --update-fixtures")
+	interactive := fs.Bool("interactive", false, "Ask before updating each fixture")
+	strict := fs.Bool("strict", false, "Fail queries that have no fixture instead of skipping them")
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	var writeReport func(*tools.SparqlReport, io.Writer) error
+	switch *reportFormat {
+	case "text":
+		writeReport = (*tools.SparqlReport).WriteText
+	case "json":
+		writeReport = (*tools.SparqlReport).WriteJSON
+	case "junit":
+		writeReport = (*tools.SparqlReport).WriteJUnit
+	default:
+		fmt.Fprintf(errorWriter, "unsupported report format %q\n", *reportFormat)
+		os.Exit(1)
+	}
+	opts := tools.SparqlOptions{
+		Progress:       errorWriter,
+		Queries:        splitList(*queries),
+		Tags:           tags.Values(),
+		Workers:        *workers,
+		UpdateFixtures: *updateFixtures,
+		Only:           splitList(*only),
+		Strict:         *strict,
+	}
+	if *interactive {
+		opts.Confirm = confirmFixtureUpdate(bufio.NewReader(confirmInput))
+	}
+	switch *backendName {
+	case "robot":
+	case "fluree":
+		if strings.TrimSpace(*ledger) == "" {
+			fmt.Fprintln(errorWriter, "ledger is required for the fluree backend")
+			os.Exit(1)
+		}
+		flureeCfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
+		opts.Backend = tools.NewFlureeBackend(fluree.NewClient(flureeCfg, nil), strings.TrimSpace(*ledger))
+	default:
+		fmt.Fprintf(errorWriter, "unsupported backend %q\n", *backendName)
 		os.Exit(1)
 	}
 	cfg := loadConfig()
-	if err := tools.RunSparql(cfg); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+	report, runErr := tools.RunSparql(cfg, opts)
+	if report != nil {
+		if err := writeReportFile(*out, func(w io.Writer) error { return writeReport(report, w) }); err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	if runErr != nil {
+		fmt.Fprintf(errorWriter, "%v\n", runErr)
 		os.Exit(1)
 	}
 }
 
+// confirmFixtureUpdate shows the diff of a query and asks the operator
+// whether to overwrite its fixture.
+func confirmFixtureUpdate(input *bufio.Reader) func(tools.QueryResult) bool {
+	return func(result tools.QueryResult) bool {
+		fmt.Fprintf(errorWriter, "%s: %s\n", result.Name, result.Message)
+		if result.Diff != nil {
+			for _, line := range result.Diff.Missing {
+				fmt.Fprintf(errorWriter, "  - %s\n", line)
+			}
+			for _, line := range result.Diff.Unexpected {
+				fmt.Fprintf(errorWriter, "  + %s\n", line)
+			}
+		}
+		fmt.Fprintf(errorWriter, "Update %s? [y/N] ", result.Fixture)
+		answer, err := input.ReadString('\n')
+		if err != nil && answer == "" {
+			return false
+		}
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeReportFile writes a report to path, creating its directory, or to
+// outputWriter when path is empty.
+func writeReportFile(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(outputWriter)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("write report %s: %w", path, err)
+	}
+	return file.Close()
+}
+
 func runFluree(args []string) {
 	if len(args) == 0 {
 		flureeUsage()
@@ -102,8 +414,20 @@ func runFluree(args []string) {
 	switch args[0] {
 	case "create-dataset":
 		runFlureeCreateDataset(args[1:])
+	case "list-datasets":
+		runFlureeListDatasets(args[1:])
+	case "describe-dataset":
+		runFlureeDescribeDataset(args[1:])
+	case "delete-dataset":
+		runFlureeDeleteDataset(args[1:])
 	case "transact":
 		runFlureeTransact(args[1:])
+	case "query":
+		runFlureeQuery(args[1:])
+	case "load":
+		runFlureeLoad(args[1:])
+	case "serve-local":
+		runFlureeServeLocal(args[1:])
 	case "generate-sparql":
 		runFlureeGenerate(args[1:], "generate-sparql")
 	case "generate-answer":
@@ -117,12 +441,12 @@ func runFluree(args []string) {
 }
 
 func flureeUsage() {
-	fmt.Fprintf(os.Stderr, "Usage: %s fluree <create-dataset|transact|generate-sparql|generate-answer|generate-prompt> [options]\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(errorWriter, "Usage: %s fluree <create-dataset|list-datasets|describe-dataset|delete-dataset|transact|query|load|serve-local|generate-sparql|generate-answer|generate-prompt> [options]\n", filepath.Base(os.Args[0]))
 }
 
 func runFlureeCreateDataset(args []string) {
 	fs := flag.NewFlagSet("fluree create-dataset", flag.ExitOnError)
-	apiToken := fs.String("api-token", "", "Fluree API token (defaults to $FLUREE_API_TOKEN)")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
 	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
 	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
 	owner := fs.String("owner", "", "Owner handle responsible for the dataset")
@@ -132,15 +456,16 @@ func runFlureeCreateDataset(args []string) {
 	visibility := fs.String("visibility", "private", "Dataset visibility")
 	tags := newStringSliceFlag()
 	fs.Var(tags, "tag", "Tag to apply to the dataset (may be repeated)")
+	raw := fs.Bool("raw", false, "Print the response body Fluree returned instead of the parsed descriptor")
 
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 
 	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
 	if *owner == "" || *datasetName == "" || *description == "" {
-		fmt.Fprintln(os.Stderr, "owner, dataset-name, and description are required")
+		fmt.Fprintln(errorWriter, "owner, dataset-name, and description are required")
 		os.Exit(1)
 	}
 
@@ -155,54 +480,50 @@ func runFlureeCreateDataset(args []string) {
 		Tags:        tags.Values(),
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
-	printJSON(result)
+	printResponse(result, result.Raw, *raw)
 }
 
 func runFlureeTransact(args []string) {
 	fs := flag.NewFlagSet("fluree transact", flag.ExitOnError)
-	apiToken := fs.String("api-token", "", "Fluree API token (defaults to $FLUREE_API_TOKEN)")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
 	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
 	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
 	ledger := fs.String("ledger", "", "Ledger identifier")
-	insertPath := fs.String("insert", "", "Path to JSON file containing an array of insert statements")
-	deletePath := fs.String("delete", "", "Path to JSON file containing an array of delete statements")
-	wherePath := fs.String("where", "", "Path to JSON file containing a where clause array")
+	insertPath := fs.String("insert", "", "Path to a JSON array or NDJSON file of insert statements (- for stdin)")
+	deletePath := fs.String("delete", "", "Path to a JSON array or NDJSON file of delete statements")
+	wherePath := fs.String("where", "", "Path to a JSON array of where clauses (node patterns or [\"optional\", ...])")
 	contextPath := fs.String("context", "", "Path to JSON file containing a JSON-LD context object")
+	maxNodes := fs.Int("max-nodes", fluree.DefaultBatchMaxNodes, "Maximum insert nodes per transaction")
+	maxBytes := fs.Int("max-bytes", fluree.DefaultBatchMaxBytes, "Maximum encoded size per transaction")
+	resumePath := fs.String("resume", "", "File recording committed batches; when it exists, nodes it covers are skipped")
+	raw := fs.Bool("raw", false, "Print the response body Fluree returned for a conditional transaction instead of the parsed receipt")
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 
 	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
 	if strings.TrimSpace(*ledger) == "" {
-		fmt.Fprintln(os.Stderr, "ledger is required")
+		fmt.Fprintln(errorWriter, "ledger is required")
 		os.Exit(1)
 	}
 
 	req := fluree.TransactionRequest{Ledger: *ledger}
-	if *insertPath != "" {
-		values, err := loadJSONArrayMap(*insertPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "load insert payload: %v\n", err)
-			os.Exit(1)
-		}
-		req.Insert = values
-	}
 	if *deletePath != "" {
 		values, err := loadJSONArrayMap(*deletePath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "load delete payload: %v\n", err)
+			fmt.Fprintf(errorWriter, "load delete payload: %v\n", err)
 			os.Exit(1)
 		}
 		req.Delete = values
 	}
 	if *wherePath != "" {
-		values, err := loadJSONArrayMap(*wherePath)
+		values, err := loadJSONArray(*wherePath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "load where payload: %v\n", err)
+			fmt.Fprintf(errorWriter, "load where payload: %v\n", err)
 			os.Exit(1)
 		}
 		req.Where = values
@@ -210,41 +531,203 @@ func runFlureeTransact(args []string) {
 	if *contextPath != "" {
 		value, err := loadJSONMap(*contextPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "load context payload: %v\n", err)
+			fmt.Fprintf(errorWriter, "load context payload: %v\n", err)
 			os.Exit(1)
 		}
 		req.Context = value
 	}
 
+	client := fluree.NewClient(cfg, nil)
+	if len(req.Delete) > 0 || len(req.Where) > 0 {
+		// Conditional updates must be applied atomically, so they are sent
+		// as a single transaction.
+		if *resumePath != "" {
+			fmt.Fprintln(errorWriter, "resume is only supported for insert-only transactions")
+			os.Exit(1)
+		}
+		if *insertPath != "" {
+			values, err := loadJSONArrayMap(*insertPath)
+			if err != nil {
+				fmt.Fprintf(errorWriter, "load insert payload: %v\n", err)
+				os.Exit(1)
+			}
+			req.Insert = values
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		result, err := client.Transact(ctx, req)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		printResponse(result, result.Raw, *raw)
+		return
+	}
+	if *insertPath == "" {
+		fmt.Fprintln(errorWriter, "an insert, delete or where payload is required")
+		os.Exit(1)
+	}
+	if *raw {
+		fmt.Fprintln(errorWriter, "raw is only supported for conditional transactions; streamed inserts print one receipt per batch")
+		os.Exit(1)
+	}
+
+	input, err := openInput(*insertPath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "load insert payload: %v\n", err)
+		os.Exit(1)
+	}
+	defer input.Close()
+	resume, err := readResumeMarker(*resumePath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	result, err := client.TransactStream(ctx, req, fluree.NewNodeDecoder(input), fluree.StreamOptions{
+		Limits: fluree.BatchLimits{MaxNodes: *maxNodes, MaxBytes: *maxBytes},
+		Resume: resume,
+		OnBatch: func(batch fluree.BatchResult) error {
+			fmt.Fprintf(errorWriter, "batch %d: nodes %d-%d (%d bytes) committed at t=%d\n",
+				batch.Batch, batch.FirstNode, batch.FirstNode+int64(batch.Nodes)-1, batch.Bytes, batch.Receipt.T)
+			if *resumePath == "" {
+				return nil
+			}
+			return writeResumeMarker(*resumePath, batch.Marker)
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		if result != nil && *resumePath != "" {
+			fmt.Fprintf(errorWriter, "%d nodes committed; rerun with --resume %s to continue\n", result.Marker.Nodes, *resumePath)
+		}
+		os.Exit(1)
+	}
+	if result.Skipped > 0 {
+		fmt.Fprintf(errorWriter, "skipped %d nodes already committed according to %s\n", result.Skipped, *resumePath)
+	}
+	printJSON(result)
+}
+
+func runFlureeQuery(args []string) {
+	fs := flag.NewFlagSet("fluree query", flag.ExitOnError)
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
+	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
+	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
+	ledger := fs.String("ledger", "", "Ledger identifier")
+	queryPath := fs.String("file", "", "Path to a SPARQL (.rq, .sparql) or FQL (.json) query")
+	format := fs.String("format", "json", "Output format for SPARQL results (json or csv)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+
+	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
+	if strings.TrimSpace(*ledger) == "" || *queryPath == "" {
+		fmt.Fprintln(errorWriter, "ledger and file are required")
+		os.Exit(1)
+	}
+	if *format != "json" && *format != "csv" {
+		fmt.Fprintf(errorWriter, "unsupported format %q\n", *format)
+		os.Exit(1)
+	}
+
 	client := fluree.NewClient(cfg, nil)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
-	result, err := client.Transact(ctx, req)
+
+	if strings.EqualFold(filepath.Ext(*queryPath), ".json") {
+		payload, err := loadJSONMap(*queryPath)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "load query: %v\n", err)
+			os.Exit(1)
+		}
+		var result any
+		if err := client.Query(ctx, fqlRequest(*ledger, payload), &result); err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		printJSON(result)
+		return
+	}
+
+	query, err := os.ReadFile(*queryPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "load query: %v\n", err)
 		os.Exit(1)
 	}
-	printJSON(result)
+	results, err := client.QuerySPARQL(ctx, *ledger, string(query))
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	if *format == "csv" {
+		if err := results.WriteCSV(outputWriter); err != nil {
+			fmt.Fprintf(errorWriter, "write CSV: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printJSON(results)
+}
+
+// fqlRequest maps a JSON-LD query document onto a QueryRequest. Keys without a
+// dedicated field are forwarded as options.
+func fqlRequest(ledger string, payload map[string]any) fluree.QueryRequest {
+	req := fluree.QueryRequest{Ledger: ledger, Options: make(map[string]any)}
+	for key, value := range payload {
+		switch key {
+		case "from":
+			// The --ledger flag always wins so queries can be reused across ledgers.
+		case "@context":
+			if ctx, ok := value.(map[string]any); ok {
+				req.Context = ctx
+			} else {
+				req.Options[key] = value
+			}
+		case "select":
+			req.Select = value
+		case "where":
+			req.Where = value
+		case "orderBy":
+			req.OrderBy = value
+		case "groupBy":
+			req.GroupBy = value
+		default:
+			req.Options[key] = value
+		}
+	}
+	return req
 }
 
 func runFlureeGenerate(args []string, endpoint string) {
 	fs := flag.NewFlagSet("fluree "+endpoint, flag.ExitOnError)
-	apiToken := fs.String("api-token", "", "Fluree API token (defaults to $FLUREE_API_TOKEN)")
+	apiToken := fs.String("api-token", "", "Fluree API token or secret reference (defaults to $FLUREE_API_TOKEN)")
 	tenant := fs.String("tenant", "", "Fluree tenant handle (defaults to $FLUREE_HANDLE)")
 	baseURL := fs.String("base-url", "", "Fluree API base URL (defaults to $FLUREE_BASE_URL)")
 	owner := fs.String("owner", "", "Owner handle responsible for the datasets")
 	prompt := fs.String("prompt", "", "Prompt/question to send to Fluree")
 	datasets := newStringSliceFlag()
 	fs.Var(datasets, "dataset", "Dataset identifier to include (may be repeated)")
+	raw := fs.Bool("raw", false, "Print the response body Fluree returned instead of the parsed response")
+	run := false
+	if endpoint == "generate-sparql" {
+		fs.BoolVar(&run, "run", false, "Execute the generated SPARQL against the first dataset")
+	}
 
 	if err := fs.Parse(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(errorWriter, err)
 		os.Exit(1)
 	}
 
 	cfg := mustFlureeConfig(*apiToken, *tenant, *baseURL)
 	if *owner == "" || *prompt == "" {
-		fmt.Fprintln(os.Stderr, "owner and prompt are required")
+		fmt.Fprintln(errorWriter, "owner and prompt are required")
+		os.Exit(1)
+	}
+	if *raw && run {
+		fmt.Fprintln(errorWriter, "raw cannot be combined with run")
 		os.Exit(1)
 	}
 
@@ -255,23 +738,62 @@ func runFlureeGenerate(args []string, endpoint string) {
 
 	var (
 		result any
+		body   json.RawMessage
 		err    error
 	)
 	switch endpoint {
 	case "generate-sparql":
-		result, err = client.GenerateSPARQL(ctx, *owner, request)
+		var generated *fluree.SPARQLGeneration
+		generated, err = client.GenerateSPARQL(ctx, *owner, request)
+		result = generated
+		if err == nil {
+			body = generated.Raw
+		}
+		if err == nil && run {
+			result, err = runGeneratedSPARQL(ctx, client, request.Datasets, generated)
+		}
 	case "generate-answer":
-		result, err = client.GenerateAnswer(ctx, *owner, request)
+		var answer *fluree.Answer
+		answer, err = client.GenerateAnswer(ctx, *owner, request)
+		result = answer
+		if err == nil {
+			body = answer.Raw
+		}
 	case "generate-prompt":
-		result, err = client.GeneratePrompt(ctx, *owner, request)
+		var response *fluree.PromptResponse
+		response, err = client.GeneratePrompt(ctx, *owner, request)
+		result = response
+		if err == nil {
+			body = response.Raw
+		}
 	default:
 		err = errors.New("unsupported endpoint")
 	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
-	printJSON(result)
+	printResponse(result, body, *raw)
+}
+
+// runGeneratedSPARQL executes the query returned by generate-sparql against
+// the first requested dataset.
+func runGeneratedSPARQL(ctx context.Context, client *fluree.Client, datasets []string, generated *fluree.SPARQLGeneration) (any, error) {
+	if len(datasets) == 0 {
+		return nil, errors.New("--run requires at least one --dataset")
+	}
+	if strings.TrimSpace(generated.SPARQL) == "" {
+		return nil, errors.New("generate-sparql returned no query")
+	}
+	results, err := client.QuerySPARQL(ctx, datasets[0], generated.SPARQL)
+	if err != nil {
+		return nil, fmt.Errorf("run generated SPARQL: %w", err)
+	}
+	return map[string]any{
+		"sparql":  generated.SPARQL,
+		"ledger":  datasets[0],
+		"results": results,
+	}, nil
 }
 
 func mustFlureeConfig(apiToken, tenant, baseURL string) fluree.Config {
@@ -280,28 +802,58 @@ func mustFlureeConfig(apiToken, tenant, baseURL string) fluree.Config {
 		return value, ok
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 	cfg = cfg.WithOverrides(apiToken, tenant, baseURL)
 	if err := cfg.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	cfg, err = cfg.ResolveSecrets(context.Background(), secretResolver)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
 		os.Exit(1)
 	}
 	return cfg
 }
 
+// loadJSONArrayMap reads a JSON array of objects or an NDJSON file.
 func loadJSONArrayMap(path string) ([]map[string]any, error) {
-	file, err := os.Open(path)
+	file, err := openInput(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	var payload []map[string]any
-	if err := json.NewDecoder(file).Decode(&payload); err != nil {
+	return fluree.ReadAllNodes(fluree.NewNodeDecoder(file))
+}
+
+// loadJSONArray reads a JSON array, or a single value wrapped as one, keeping
+// numbers as json.Number.
+func loadJSONArray(path string) ([]any, error) {
+	file, err := openInput(path)
+	if err != nil {
 		return nil, err
 	}
-	return payload, nil
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if values, ok := value.([]any); ok {
+		return values, nil
+	}
+	return []any{value}, nil
+}
+
+// openInput opens path for reading, treating "-" as standard input.
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
 }
 
 func loadJSONMap(path string) (map[string]any, error) {
@@ -318,10 +870,37 @@ func loadJSONMap(path string) (map[string]any, error) {
 }
 
 func printJSON(value any) {
-	encoder := json.NewEncoder(outputWriter)
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(value); err != nil {
-		fmt.Fprintf(os.Stderr, "encode JSON: %v\n", err)
+		fmt.Fprintf(errorWriter, "encode JSON: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := io.WriteString(outputWriter, secrets.Redact(buf.String())); err != nil {
+		fmt.Fprintf(errorWriter, "write JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printResponse prints a typed Fluree response, or with raw the response
+// body Fluree returned for it.
+func printResponse(value any, body json.RawMessage, raw bool) {
+	if !raw {
+		printJSON(value)
+		return
+	}
+	if len(body) == 0 {
+		body = json.RawMessage("null")
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		fmt.Fprintf(errorWriter, "encode JSON: %v\n", err)
+		os.Exit(1)
+	}
+	buf.WriteByte('\n')
+	if _, err := io.WriteString(outputWriter, secrets.Redact(buf.String())); err != nil {
+		fmt.Fprintf(errorWriter, "write JSON: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -350,12 +929,12 @@ func (s *stringSliceFlag) Values() []string {
 func loadConfig() *tools.Config {
 	cwd, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "determine cwd: %v\n", err)
+		fmt.Fprintf(errorWriter, "determine cwd: %v\n", err)
 		os.Exit(1)
 	}
 	root, err := tools.FindRepoRoot(cwd)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "locate repo root: %v\n", err)
+		fmt.Fprintf(errorWriter, "locate repo root: %v\n", err)
 		os.Exit(1)
 	}
 	return tools.NewConfig(root)
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	bhedera "github.com/hashgraph/bhash/internal/hedera"
+)
+
+// signatureNow is the clock used to timestamp dataset signatures.
+var signatureNow = time.Now
+
+func runSign(args []string) {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	datasetPath := fs.String("dataset", "", "Path to the JSON-LD dataset to sign")
+	out := fs.String("out", "", "Path of the detached signature (defaults to <dataset>.sig.json)")
+	operatorID := fs.String("operator-id", "", "Hedera operator account ID (defaults to $HEDERA_OPERATOR_ID)")
+	operatorKey := fs.String("operator-key", "", "Hedera operator private key or secret reference (defaults to $HEDERA_OPERATOR_KEY)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	if *datasetPath == "" {
+		fmt.Fprintln(errorWriter, "dataset is required")
+		os.Exit(1)
+	}
+	doc, err := readJSONDocument(*datasetPath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	cfg := mustOperatorConfig(*operatorID, *operatorKey)
+	signature, err := bhedera.SignDataset(doc, cfg.OperatorAccountID, cfg.OperatorPrivateKey, signatureNow())
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	target := *out
+	if target == "" {
+		target = signaturePath(*datasetPath)
+	}
+	if err := writeJSONFile(target, signature); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(map[string]any{"dataset": *datasetPath, "signatureFile": target, "signature": signature})
+}
+
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	datasetPath := fs.String("dataset", "", "Path to the signed JSON-LD dataset")
+	sigPath := fs.String("signature", "", "Path of the detached signature (defaults to <dataset>.sig.json)")
+	publicKey := fs.String("public-key", "", "Verify against this public key instead of the account key on the mirror node")
+	network := fs.String("network", "", "Hedera network used to pick the default mirror node (defaults to $HEDERA_NETWORK)")
+	mirrorRESTURL := fs.String("mirror-rest-url", "", "Mirror node REST API URL (defaults to $HEDERA_MIRROR_REST_URL or the public mirror node of the network)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	if *datasetPath == "" {
+		fmt.Fprintln(errorWriter, "dataset is required")
+		os.Exit(1)
+	}
+	if *sigPath == "" {
+		*sigPath = signaturePath(*datasetPath)
+	}
+	doc, err := readJSONDocument(*datasetPath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	var signature bhedera.DatasetSignature
+	data, err := os.ReadFile(*sigPath)
+	if err == nil {
+		err = json.Unmarshal(data, &signature)
+	}
+	if err != nil {
+		fmt.Fprintf(errorWriter, "read signature %s: %v\n", *sigPath, err)
+		os.Exit(1)
+	}
+
+	output := map[string]any{
+		"dataset":   *datasetPath,
+		"signature": *sigPath,
+		"accountId": signature.AccountID,
+		"digest":    signature.Digest,
+	}
+	key := strings.TrimSpace(*publicKey)
+	if key != "" {
+		output["keySource"] = "flag"
+	} else {
+		mirrorURL := mirrorRESTBaseURL(*mirrorRESTURL, *network)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		account, err := bhedera.NewMirrorClient(mirrorURL, nil).Account(ctx, signature.AccountID)
+		if err != nil {
+			fmt.Fprintf(errorWriter, "%v\n", err)
+			os.Exit(1)
+		}
+		key = account.PublicKey
+		output["keySource"] = mirrorURL
+		if account.Deleted {
+			output["accountDeleted"] = true
+		}
+	}
+	output["publicKey"] = key
+	if err := bhedera.VerifyDataset(doc, signature, key); err != nil {
+		fmt.Fprintf(errorWriter, "verification failed: %v\n", err)
+		os.Exit(1)
+	}
+	output["verified"] = true
+	printJSON(output)
+}
+
+// mustOperatorConfig reads the Hedera operator credentials from the
+// environment and flags and resolves secret references in the key.
+func mustOperatorConfig(operatorID, operatorKey string) bhedera.Config {
+	cfg := mustHederaConfig("", operatorID, operatorKey, "")
+	if !cfg.HasOperator() {
+		fmt.Fprintln(errorWriter, "operator credentials are required for signing")
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// mustHederaConfig applies flag overrides to the Hedera configuration from
+// the environment and resolves secret references in the operator key.
+func mustHederaConfig(network, operatorID, operatorKey, mirrorURL string) bhedera.Config {
+	cfg, err := bhedera.EnvConfigFromLookup(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	cfg = cfg.WithOverrides(network, operatorID, operatorKey, mirrorURL)
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	cfg, err = cfg.ResolveSecrets(context.Background(), secretResolver)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// mirrorRESTBaseURL picks the mirror node REST endpoint from the flag,
+// $HEDERA_MIRROR_REST_URL or the public mirror node of network.
+func mirrorRESTBaseURL(flagValue, network string) string {
+	if url := strings.TrimSpace(flagValue); url != "" {
+		return url
+	}
+	if url := strings.TrimSpace(os.Getenv("HEDERA_MIRROR_REST_URL")); url != "" {
+		return url
+	}
+	if strings.TrimSpace(network) == "" {
+		network = os.Getenv("HEDERA_NETWORK")
+	}
+	url, err := bhedera.DefaultMirrorRESTURL(network)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	return url
+}
+
+// writeEvidence writes a JSON-LD dataset and, when signature is set, its
+// detached signature next to it.
+func writeEvidence(path string, doc map[string]any, signature *bhedera.DatasetSignature) (string, error) {
+	if err := writeJSONFile(path, doc); err != nil {
+		return "", err
+	}
+	if signature == nil {
+		return "", nil
+	}
+	sigPath := signaturePath(path)
+	return sigPath, writeJSONFile(sigPath, signature)
+}
+
+func signaturePath(datasetPath string) string {
+	return datasetPath + ".sig.json"
+}
+
+func readJSONDocument(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read dataset %s: %w", path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode dataset %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+func writeJSONFile(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/hashgraph/hedera-sdk-go/v2"
+
+	bhedera "github.com/hashgraph/bhash/internal/hedera"
+)
+
+func TestRunHederaBootstrapSignAndVerify(t *testing.T) {
+	tempDir := t.TempDir()
+	specPath := filepath.Join(tempDir, "spec.json")
+	spec := bhedera.BootstrapSpec{
+		Network:  "testnet",
+		Accounts: []bhedera.AccountSpec{{Alias: "treasury", Memo: "Treasury"}},
+		Topics:   []bhedera.TopicSpec{{Alias: "events", Memo: "Events"}},
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("encode spec: %v", err)
+	}
+	if err := os.WriteFile(specPath, data, 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	key, err := sdk.PrivateKeyGenerateEd25519()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	t.Setenv("HEDERA_OPERATOR_ID", "0.0.2")
+	t.Setenv("HEDERA_OPERATOR_KEY", key.String())
+	t.Setenv("HEDERA_MIRROR_REST_URL", "")
+
+	fixedTime := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	originalFactory := hederaNetworkFactory
+	defer func() { hederaNetworkFactory = originalFactory }()
+	hederaNetworkFactory = func(cfg bhedera.Config, simulate bool) (bhedera.Network, func(), error) {
+		return bhedera.NewMockNetwork(cfg.Network, bhedera.WithNowFunc(func() time.Time { return fixedTime })), func() {}, nil
+	}
+	originalNow := signatureNow
+	defer func() { signatureNow = originalNow }()
+	signatureNow = func() time.Time { return fixedTime }
+
+	originalWriter := outputWriter
+	defer func() { outputWriter = originalWriter }()
+	buf := &bytes.Buffer{}
+	outputWriter = buf
+
+	evidencePath := filepath.Join(tempDir, "bootstrap.jsonld")
+	runHederaBootstrap([]string{"--spec", specPath, "--ledger", "tenant/dataset", "--sign", "--evidence-out", evidencePath})
+
+	var output map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	signature, ok := output["signature"].(map[string]any)
+	if !ok || signature["accountId"] != "0.0.2" || signature["keyType"] != "ED25519" {
+		t.Fatalf("unexpected signature: %#v", output["signature"])
+	}
+	if output["evidenceSignature"] != evidencePath+".sig.json" {
+		t.Fatalf("unexpected evidence signature path: %#v", output["evidenceSignature"])
+	}
+
+	var requested string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		json.NewEncoder(w).Encode(map[string]any{
+			"account": "0.0.2",
+			"key":     map[string]any{"_type": "ED25519", "key": key.PublicKey().StringRaw()},
+		})
+	}))
+	defer mirror.Close()
+
+	buf.Reset()
+	runVerify([]string{"--dataset", evidencePath, "--mirror-rest-url", mirror.URL})
+	var verified map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &verified); err != nil {
+		t.Fatalf("decode verify output: %v", err)
+	}
+	if verified["verified"] != true || verified["digest"] != signature["digest"] {
+		t.Fatalf("unexpected verify output: %#v", verified)
+	}
+	if requested != "/api/v1/accounts/0.0.2" {
+		t.Fatalf("unexpected mirror request %q", requested)
+	}
+
+	// Re-serialising the dataset does not change the canonical digest.
+	doc, err := readJSONDocument(evidencePath)
+	if err != nil {
+		t.Fatalf("read evidence: %v", err)
+	}
+	compact, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("encode evidence: %v", err)
+	}
+	resigned := filepath.Join(tempDir, "compact.jsonld")
+	if err := os.WriteFile(resigned, compact, 0o644); err != nil {
+		t.Fatalf("write compact evidence: %v", err)
+	}
+	buf.Reset()
+	runVerify([]string{"--dataset", resigned, "--signature", evidencePath + ".sig.json", "--public-key", key.PublicKey().StringDer()})
+	if err := json.Unmarshal(buf.Bytes(), &verified); err != nil {
+		t.Fatalf("decode verify output: %v", err)
+	}
+	if verified["verified"] != true || verified["keySource"] != "flag" {
+		t.Fatalf("unexpected verify output: %#v", verified)
+	}
+}
+
+func TestRunSign(t *testing.T) {
+	tempDir := t.TempDir()
+	datasetPath := filepath.Join(tempDir, "dataset.jsonld")
+	dataset := `{"@context":{"schema":"http://schema.org/"},"@id":"urn:example:1","schema:name":"Example"}`
+	if err := os.WriteFile(datasetPath, []byte(dataset), 0o644); err != nil {
+		t.Fatalf("write dataset: %v", err)
+	}
+	key, err := sdk.PrivateKeyGenerateEcdsa()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	t.Setenv("HEDERA_OPERATOR_ID", "")
+	t.Setenv("HEDERA_OPERATOR_KEY", "")
+
+	originalWriter := outputWriter
+	defer func() { outputWriter = originalWriter }()
+	outputWriter = &bytes.Buffer{}
+
+	runSign([]string{"--dataset", datasetPath, "--operator-id", "0.0.1001", "--operator-key", key.String()})
+
+	data, err := os.ReadFile(datasetPath + ".sig.json")
+	if err != nil {
+		t.Fatalf("read signature: %v", err)
+	}
+	var signature bhedera.DatasetSignature
+	if err := json.Unmarshal(data, &signature); err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	doc, err := readJSONDocument(datasetPath)
+	if err != nil {
+		t.Fatalf("read dataset: %v", err)
+	}
+	if err := bhedera.VerifyDataset(doc, signature, key.PublicKey().StringRaw()); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if signature.AccountID != "0.0.1001" || signature.KeyType != "ECDSA_SECP256K1" {
+		t.Fatalf("unexpected signature: %#v", signature)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bhedera "github.com/hashgraph/bhash/internal/hedera"
+	"github.com/hashgraph/bhash/internal/release"
+)
+
+func runRelease(args []string) {
+	if len(args) == 0 {
+		releaseUsage()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "anchor":
+		runReleaseAnchor(args[1:])
+	case "verify":
+		runReleaseVerify(args[1:])
+	default:
+		releaseUsage()
+		os.Exit(1)
+	}
+}
+
+func releaseUsage() {
+	fmt.Fprintf(errorWriter, "Usage: %s release <anchor|verify> [options]\n", filepath.Base(os.Args[0]))
+}
+
+func runReleaseAnchor(args []string) {
+	fs := flag.NewFlagSet("release anchor", flag.ExitOnError)
+	paths := newStringSliceFlag()
+	fs.Var(paths, "path", "File or directory, relative to the repository root, whose Turtle files belong to the release (may be repeated; default ontology/src and ontology/shapes)")
+	version := fs.String("version", "", "Release version recorded in the manifest and the anchor message")
+	topic := fs.String("topic", "", "HCS topic ID to anchor the root to (defaults to $HEDERA_RELEASE_TOPIC_ID)")
+	manifestPath := fs.String("manifest", "release-manifest.json", "Path of the release manifest to write")
+	simulate := fs.Bool("simulate", true, "Use the deterministic mock Hedera network")
+	networkOverride := fs.String("network", "", "Hedera network (overrides $HEDERA_NETWORK)")
+	operatorID := fs.String("operator-id", "", "Hedera operator account ID")
+	operatorKey := fs.String("operator-key", "", "Hedera operator private key or secret reference (file:, env:, cmd:, vault:)")
+	mirrorURL := fs.String("mirror-url", "", "Hedera mirror network URL")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	topicID := strings.TrimSpace(*topic)
+	if topicID == "" {
+		topicID = strings.TrimSpace(os.Getenv("HEDERA_RELEASE_TOPIC_ID"))
+	}
+	if topicID == "" {
+		fmt.Fprintln(errorWriter, "topic is required (--topic or $HEDERA_RELEASE_TOPIC_ID)")
+		os.Exit(1)
+	}
+
+	releasePaths := paths.Values()
+	if len(releasePaths) == 0 {
+		releasePaths = release.DefaultPaths
+	}
+	manifest, err := release.Build(loadConfig().RepoRoot, releasePaths, *version)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := mustHederaConfig(*networkOverride, *operatorID, *operatorKey, *mirrorURL)
+	if !*simulate && !cfg.HasOperator() {
+		fmt.Fprintln(errorWriter, "operator credentials are required when simulate=false")
+		os.Exit(1)
+	}
+	network, closer, err := hederaNetworkFactory(cfg, *simulate)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	if closer != nil {
+		defer closer()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if err := manifest.AnchorTo(ctx, network, cfg.Network, topicID); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	manifest.Anchor.Simulated = *simulate
+	if err := manifest.Write(*manifestPath); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(map[string]any{"manifest": *manifestPath, "simulated": *simulate, "root": manifest.Root, "files": len(manifest.Files), "anchor": manifest.Anchor})
+}
+
+func runReleaseVerify(args []string) {
+	fs := flag.NewFlagSet("release verify", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "release-manifest.json", "Path of the release manifest to verify")
+	network := fs.String("network", "", "Hedera network used to pick the default mirror node (defaults to the network recorded in the manifest)")
+	mirrorRESTURL := fs.String("mirror-rest-url", "", "Mirror node REST API URL (defaults to $HEDERA_MIRROR_REST_URL or the public mirror node of the network)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	manifest, err := release.LoadManifest(*manifestPath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	networkName := strings.TrimSpace(*network)
+	if networkName == "" && manifest.Anchor != nil {
+		networkName = manifest.Anchor.Network
+	}
+	mirror := bhedera.NewMirrorClient(mirrorRESTBaseURL(*mirrorRESTURL, networkName), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := release.Verify(ctx, loadConfig().RepoRoot, manifest, mirror)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(result)
+	if !result.Verified {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bhedera "github.com/hashgraph/bhash/internal/hedera"
+	"github.com/hashgraph/bhash/internal/release"
+	sdk "github.com/hashgraph/hedera-sdk-go/v2"
+)
+
+func TestRunReleaseAnchorAndVerify(t *testing.T) {
+	consensus := time.Date(2024, 9, 1, 12, 0, 0, 42, time.UTC)
+	network := bhedera.NewMockNetwork("testnet", bhedera.WithNowFunc(func() time.Time { return consensus }))
+	originalFactory := hederaNetworkFactory
+	defer func() { hederaNetworkFactory = originalFactory }()
+	hederaNetworkFactory = func(cfg bhedera.Config, simulate bool) (bhedera.Network, func(), error) {
+		return network, func() {}, nil
+	}
+	t.Setenv("HEDERA_RELEASE_TOPIC_ID", "0.0.5005")
+	t.Setenv("HEDERA_MIRROR_REST_URL", "")
+	key, err := sdk.PrivateKeyGenerateEd25519()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	t.Setenv("HEDERA_OPERATOR_ID", "0.0.2")
+	t.Setenv("HEDERA_OPERATOR_KEY", key.String())
+
+	originalWriter := outputWriter
+	defer func() { outputWriter = originalWriter }()
+	buf := &bytes.Buffer{}
+	outputWriter = buf
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	simulated := filepath.Join(t.TempDir(), "simulated.json")
+	runReleaseAnchor([]string{"--path", "ontology/shapes", "--version", "v0.1.0", "--manifest", simulated})
+	if manifest, err := release.LoadManifest(simulated); err != nil || !manifest.Anchor.Simulated {
+		t.Fatalf("expected the default anchor to be marked simulated: %v", err)
+	}
+	buf.Reset()
+	runReleaseAnchor([]string{"--path", "ontology/shapes", "--version", "v0.1.0", "--manifest", manifestPath, "--simulate=false"})
+
+	var anchored struct {
+		Root   string         `json:"root"`
+		Files  int            `json:"files"`
+		Anchor release.Anchor `json:"anchor"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &anchored); err != nil {
+		t.Fatalf("decode anchor output: %v", err)
+	}
+	if anchored.Files == 0 || anchored.Anchor.TopicID != "0.0.5005" || anchored.Anchor.SequenceNumber != 2 || anchored.Anchor.Simulated {
+		t.Fatalf("unexpected anchor output: %s", buf.String())
+	}
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record, err := network.TopicMessage(context.Background(), "0.0.5005", 2)
+		if err != nil || r.URL.Path != "/api/v1/topics/0.0.5005/messages/2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"topic_id":"0.0.5005","sequence_number":2,"message":%q,"consensus_timestamp":"%d.%09d"}`,
+			base64.StdEncoding.EncodeToString(record.Message), consensus.Unix(), consensus.Nanosecond())
+	}))
+	defer mirror.Close()
+
+	buf.Reset()
+	runReleaseVerify([]string{"--manifest", manifestPath, "--mirror-rest-url", mirror.URL})
+	var verified map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &verified); err != nil {
+		t.Fatalf("decode verify output: %v", err)
+	}
+	if verified["verified"] != true || verified["anchoredRoot"] != anchored.Root {
+		t.Fatalf("unexpected verify output: %#v", verified)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/secrets"
+)
+
+var secretInput io.Reader = os.Stdin
+
+func runSecrets(args []string) {
+	if len(args) == 0 {
+		secretsUsage()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "set":
+		runSecretsSet(args[1:])
+	case "list":
+		runSecretsList(args[1:])
+	default:
+		secretsUsage()
+		os.Exit(1)
+	}
+}
+
+func secretsUsage() {
+	fmt.Fprintf(errorWriter, "Usage: %s secrets <set|list> --vault <path> [options]\n", filepath.Base(os.Args[0]))
+}
+
+func runSecretsSet(args []string) {
+	fs := flag.NewFlagSet("secrets set", flag.ExitOnError)
+	vaultPath := fs.String("vault", "", "Path to the encrypted vault file")
+	name := fs.String("name", "", "Entry name (referenced as vault:<path>#<name>)")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	if *vaultPath == "" || *name == "" {
+		fmt.Fprintln(errorWriter, "vault and name are required")
+		os.Exit(1)
+	}
+	passphrase := mustVaultPassphrase()
+
+	// Read the value from stdin so that it never appears in shell history.
+	value, err := bufio.NewReader(secretInput).ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(errorWriter, "read secret: %v\n", err)
+		os.Exit(1)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		fmt.Fprintln(errorWriter, "secret value must be supplied on stdin")
+		os.Exit(1)
+	}
+	if err := secrets.SetVaultEntry(*vaultPath, passphrase, *name, value); err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	printJSON(map[string]any{
+		"vault":     *vaultPath,
+		"name":      *name,
+		"reference": secrets.SchemeVault + *vaultPath + "#" + *name,
+	})
+}
+
+func runSecretsList(args []string) {
+	fs := flag.NewFlagSet("secrets list", flag.ExitOnError)
+	vaultPath := fs.String("vault", "", "Path to the encrypted vault file")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintln(errorWriter, err)
+		os.Exit(1)
+	}
+	if *vaultPath == "" {
+		fmt.Fprintln(errorWriter, "vault is required")
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(*vaultPath)
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	entries, err := secrets.OpenVault(data, mustVaultPassphrase())
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	printJSON(map[string]any{"vault": *vaultPath, "entries": names})
+}
+
+func mustVaultPassphrase() string {
+	passphrase, err := secretResolver.Passphrase()
+	if err != nil {
+		fmt.Fprintf(errorWriter, "%v\n", err)
+		os.Exit(1)
+	}
+	secrets.Register(passphrase)
+	return passphrase
+}
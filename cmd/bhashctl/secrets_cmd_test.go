@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashgraph/bhash/internal/secrets"
+)
+
+func TestRunSecretsSetRoundTripsThroughVaultReferences(t *testing.T) {
+	t.Setenv(secrets.VaultPassphraseEnv, "correct horse battery staple")
+	vaultPath := filepath.Join(t.TempDir(), "vault.json")
+
+	buf := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	originalWriter, originalError := outputWriter, errorWriter
+	outputWriter, errorWriter = buf, stderr
+	defer func() { outputWriter, errorWriter = originalWriter, originalError }()
+	originalInput := secretInput
+	defer func() { secretInput = originalInput }()
+
+	set := func(name, value string) string {
+		t.Helper()
+		buf.Reset()
+		secretInput = strings.NewReader(value + "\n")
+		runSecretsSet([]string{"--vault", vaultPath, "--name", name})
+		var output map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+			t.Fatalf("decode output: %v\n%s", err, buf.String())
+		}
+		return output["reference"]
+	}
+	tokenRef := set("fluree-api", "token-1")
+	if tokenRef != "vault:"+vaultPath+"#fluree-api" {
+		t.Fatalf("unexpected reference %q", tokenRef)
+	}
+	keyRef := set("operator-key", "302e0201")
+	// Re-setting an entry re-encrypts the vault without dropping the others.
+	set("fluree-api", "token-2")
+
+	sealed, err := os.ReadFile(vaultPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("token-2")) || bytes.Contains(sealed, []byte("302e0201")) {
+		t.Fatalf("vault stores a secret in plain text:\n%s", sealed)
+	}
+
+	buf.Reset()
+	runSecretsList([]string{"--vault", vaultPath})
+	var listed struct{ Entries []string }
+	if err := json.Unmarshal(buf.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list output: %v", err)
+	}
+	if !reflect.DeepEqual(listed.Entries, []string{"fluree-api", "operator-key"}) {
+		t.Fatalf("unexpected entries: %v", listed.Entries)
+	}
+
+	for ref, want := range map[string]string{tokenRef: "token-2", keyRef: "302e0201"} {
+		got, err := secretResolver.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("Resolve(%s): %v", ref, err)
+		}
+		if got != want {
+			t.Fatalf("Resolve(%s) = %q, want %q", ref, got, want)
+		}
+	}
+
+	t.Setenv(secrets.VaultPassphraseEnv, "wrong passphrase")
+	if _, err := secretResolver.Resolve(context.Background(), tokenRef); !errors.Is(err, secrets.ErrVaultPassphrase) {
+		t.Fatalf("expected a wrong passphrase to be rejected, got %v", err)
+	}
+	if err := secrets.SetVaultEntry(vaultPath, "wrong passphrase", "fluree-api", "token-3"); !errors.Is(err, secrets.ErrVaultPassphrase) {
+		t.Fatalf("expected set with a wrong passphrase to be rejected, got %v", err)
+	}
+	if unchanged, err := os.ReadFile(vaultPath); err != nil || !bytes.Equal(unchanged, sealed) {
+		t.Fatalf("a rejected set must leave the vault unchanged (err %v)", err)
+	}
+}
@@ -2,7 +2,10 @@ module github.com/hashgraph/bhash
 
 go 1.22
 
-require github.com/hashgraph/hedera-sdk-go/v2 v2.39.0
+require (
+	github.com/hashgraph/hedera-sdk-go/v2 v2.39.0
+	golang.org/x/crypto v0.23.0
+)
 
 require (
 	github.com/bits-and-blooms/bitset v1.13.0 // indirect
@@ -28,7 +31,6 @@ require (
 	github.com/supranational/blst v0.3.11 // indirect
 	github.com/tyler-smith/go-bip39 v1.1.0 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20201027041543-1326539a0a0a // indirect
-	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/exp v0.0.0-20240416160154-fe59bbe5cc7f // indirect
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
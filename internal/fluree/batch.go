@@ -0,0 +1,199 @@
+package fluree
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Default batch limits keep individual transactions comfortably below the
+// request size accepted by Fluree Cloud.
+const (
+	DefaultBatchMaxNodes = 500
+	DefaultBatchMaxBytes = 1 << 20
+)
+
+// BatchLimits bounds the size of a single transaction. Zero values select
+// the defaults.
+type BatchLimits struct {
+	MaxNodes int
+	MaxBytes int
+}
+
+func (l BatchLimits) withDefaults() BatchLimits {
+	if l.MaxNodes <= 0 {
+		l.MaxNodes = DefaultBatchMaxNodes
+	}
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = DefaultBatchMaxBytes
+	}
+	return l
+}
+
+// SplitTransaction splits the insert nodes of req into transactions that
+// respect limits. Blank node labels are scoped to a transaction, so nodes
+// connected through a shared blank node are kept in the same batch; the
+// group moves up to the position of its first node and otherwise node order
+// is preserved. Transactions carrying delete or where clauses are returned
+// unchanged because splitting them would change their semantics. A single
+// node or group larger than the limits is placed in its own batch.
+func SplitTransaction(req TransactionRequest, limits BatchLimits) ([]TransactionRequest, error) {
+	if len(req.Delete) > 0 || len(req.Where) > 0 || len(req.Insert) == 0 {
+		return []TransactionRequest{req}, nil
+	}
+	b, err := newBatcher(req, limits)
+	if err != nil {
+		return nil, err
+	}
+	var batches []TransactionRequest
+	for _, group := range blankNodeGroups(req.Insert) {
+		nodes := make([]map[string]any, len(group))
+		for i, index := range group {
+			nodes[i] = req.Insert[index]
+		}
+		full, _, err := b.add(nodes...)
+		if err != nil {
+			return nil, fmt.Errorf("fluree: encode insert node %d: %w", group[0], err)
+		}
+		if full != nil {
+			batches = append(batches, TransactionRequest{Ledger: req.Ledger, Context: req.Context, Insert: full})
+		}
+	}
+	if last, _ := b.flush(); last != nil {
+		batches = append(batches, TransactionRequest{Ledger: req.Ledger, Context: req.Context, Insert: last})
+	}
+	return batches, nil
+}
+
+// blankNodeGroups partitions the indexes of nodes into groups of nodes that
+// mention a common blank node label, directly or through other members.
+// Groups are ordered by their first node.
+func blankNodeGroups(nodes []map[string]any) [][]int {
+	parent := make([]int, len(nodes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	owner := map[string]int{}
+	for i, node := range nodes {
+		for _, label := range blankNodeLabels(node, nil) {
+			j, ok := owner[label]
+			if !ok {
+				owner[label] = i
+				continue
+			}
+			if ri, rj := find(i), find(j); ri != rj {
+				if ri < rj {
+					parent[rj] = ri
+				} else {
+					parent[ri] = rj
+				}
+			}
+		}
+	}
+	members := map[int][]int{}
+	var roots []int
+	for i := range nodes {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+	groups := make([][]int, len(roots))
+	for i, root := range roots {
+		groups[i] = members[root]
+	}
+	return groups
+}
+
+// blankNodeLabels appends the "_:" labels used as @id anywhere in value.
+func blankNodeLabels(value any, labels []string) []string {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			labels = blankNodeLabels(item, labels)
+		}
+	case map[string]any:
+		if id, ok := v["@id"].(string); ok && strings.HasPrefix(id, "_:") {
+			labels = append(labels, id)
+		}
+		for key, item := range v {
+			if key != "@id" {
+				labels = blankNodeLabels(item, labels)
+			}
+		}
+	}
+	return labels
+}
+
+// batcher accumulates insert nodes until the next node would exceed the
+// batch limits.
+type batcher struct {
+	limits   BatchLimits
+	overhead int
+	current  []map[string]any
+	size     int
+}
+
+func newBatcher(req TransactionRequest, limits BatchLimits) (*batcher, error) {
+	overhead, err := transactionOverhead(req)
+	if err != nil {
+		return nil, err
+	}
+	return &batcher{limits: limits.withDefaults(), overhead: overhead, size: overhead}, nil
+}
+
+// add appends nodes, which must share a batch. When they do not fit
+// alongside the pending nodes, the pending batch and its estimated request
+// size are returned first.
+func (b *batcher) add(nodes ...map[string]any) ([]map[string]any, int, error) {
+	nodesSize := 0
+	for _, node := range nodes {
+		encoded, err := json.Marshal(node)
+		if err != nil {
+			return nil, 0, err
+		}
+		nodesSize += len(encoded) + 1
+	}
+	var (
+		full []map[string]any
+		size int
+	)
+	if len(b.current) > 0 && (len(b.current)+len(nodes) > b.limits.MaxNodes || b.size+nodesSize > b.limits.MaxBytes) {
+		full, size = b.flush()
+	}
+	b.current = append(b.current, nodes...)
+	b.size += nodesSize
+	return full, size, nil
+}
+
+// flush returns the pending nodes and their estimated request size, or nil
+// when nothing is pending.
+func (b *batcher) flush() ([]map[string]any, int) {
+	if len(b.current) == 0 {
+		return nil, 0
+	}
+	full, size := b.current, b.size
+	b.current, b.size = nil, b.overhead
+	return full, size
+}
+
+// transactionOverhead estimates the encoded size of req without its inserts.
+func transactionOverhead(req TransactionRequest) (int, error) {
+	payload := map[string]any{"ledger": req.Ledger, "insert": []any{}}
+	if req.Context != nil {
+		payload["context"] = req.Context
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("fluree: encode transaction: %w", err)
+	}
+	return len(encoded), nil
+}
@@ -0,0 +1,102 @@
+package fluree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSplitTransactionRespectsLimits(t *testing.T) {
+	t.Parallel()
+
+	var nodes []map[string]any
+	for i := 0; i < 7; i++ {
+		nodes = append(nodes, map[string]any{"@id": "ex:node", "ex:index": i})
+	}
+	req := TransactionRequest{Ledger: "tenant/ledger", Context: map[string]any{"ex": "https://example.org/"}, Insert: nodes}
+
+	batches, err := SplitTransaction(req, BatchLimits{MaxNodes: 3})
+	if err != nil {
+		t.Fatalf("SplitTransaction returned error: %v", err)
+	}
+	if len(batches) != 3 || len(batches[0].Insert) != 3 || len(batches[2].Insert) != 1 {
+		t.Fatalf("unexpected node split: %+v", batches)
+	}
+	index := 0
+	for _, batch := range batches {
+		if batch.Ledger != req.Ledger || batch.Context == nil {
+			t.Fatalf("batch lost ledger or context: %+v", batch)
+		}
+		for _, node := range batch.Insert {
+			if node["ex:index"] != index {
+				t.Fatalf("order not preserved: %v at %d", node["ex:index"], index)
+			}
+			index++
+		}
+	}
+
+	encoded, _ := json.Marshal(nodes[0])
+	overhead, err := transactionOverhead(req)
+	if err != nil {
+		t.Fatalf("transactionOverhead returned error: %v", err)
+	}
+	maxBytes := overhead + 2*(len(encoded)+1)
+	batches, err = SplitTransaction(req, BatchLimits{MaxBytes: maxBytes})
+	if err != nil {
+		t.Fatalf("SplitTransaction returned error: %v", err)
+	}
+	for _, batch := range batches {
+		body, _ := json.Marshal(map[string]any{"ledger": batch.Ledger, "context": batch.Context, "insert": batch.Insert})
+		if len(body) > maxBytes {
+			t.Fatalf("batch exceeds byte limit: %d", len(body))
+		}
+	}
+	if len(batches) != 4 {
+		t.Fatalf("expected byte limit to split, got %d batches", len(batches))
+	}
+}
+
+func TestSplitTransactionKeepsSharedBlankNodesTogether(t *testing.T) {
+	t.Parallel()
+
+	nodes := []map[string]any{
+		{"@id": "ex:a", "ex:p": map[string]any{"@id": "_:b0"}},
+		{"@id": "ex:b"},
+		{"@id": "ex:c", "ex:p": map[string]any{"@id": "_:b0"}},
+		{"@id": "ex:d"},
+		{"@id": "_:b0", "ex:q": map[string]any{"@id": "_:b1"}},
+		{"@id": "_:b1", "ex:r": "v"},
+	}
+	batches, err := SplitTransaction(TransactionRequest{Ledger: "tenant/ledger", Insert: nodes}, BatchLimits{MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("SplitTransaction returned error: %v", err)
+	}
+	var got [][]any
+	for _, batch := range batches {
+		var ids []any
+		for _, node := range batch.Insert {
+			ids = append(ids, node["@id"])
+		}
+		got = append(got, ids)
+	}
+	want := `[["ex:a","ex:c","_:b0","_:b1"],["ex:b","ex:d"]]`
+	if encoded, _ := json.Marshal(got); string(encoded) != want {
+		t.Fatalf("unexpected batches %s, want %s", encoded, want)
+	}
+}
+
+func TestSplitTransactionKeepsConditionalUpdates(t *testing.T) {
+	t.Parallel()
+
+	req := TransactionRequest{
+		Ledger: "tenant/ledger",
+		Where:  []any{map[string]any{"@id": "?s"}},
+		Insert: []map[string]any{{"@id": "ex:a"}, {"@id": "ex:b"}},
+	}
+	batches, err := SplitTransaction(req, BatchLimits{MaxNodes: 1})
+	if err != nil {
+		t.Fatalf("SplitTransaction returned error: %v", err)
+	}
+	if len(batches) != 1 || len(batches[0].Insert) != 2 {
+		t.Fatalf("conditional transaction should not be split: %+v", batches)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,22 +19,44 @@ import (
 type Client struct {
 	httpClient *http.Client
 	config     Config
+	retry      RetryPolicy
+	breaker    *CircuitBreaker
+	sleep      func(context.Context, time.Duration) error
+	random     func() float64
 }
 
 // NewClient returns a Client configured with the supplied credentials. When
 // httpClient is nil the default http.Client with a 30s timeout is used.
-func NewClient(cfg Config, httpClient *http.Client) *Client {
+// Requests are retried with DefaultRetryPolicy and guarded by a circuit
+// breaker that opens after five consecutive failures; use WithRetryPolicy and
+// WithCircuitBreaker to change either.
+func NewClient(cfg Config, httpClient *http.Client, opts ...ClientOption) *Client {
 	client := httpClient
 	if client == nil {
 		client = &http.Client{Timeout: 30 * time.Second}
 	}
-	return &Client{httpClient: client, config: cfg}
+	c := &Client{
+		httpClient: client,
+		config:     cfg,
+		retry:      DefaultRetryPolicy(),
+		breaker:    NewCircuitBreaker(5, 30*time.Second),
+		sleep:      sleepContext,
+		random:     jitter,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // APIError captures the HTTP status code and message returned by Fluree.
+// Kind classifies the failure; RetryAfter holds the delay requested by the
+// server, if any.
 type APIError struct {
 	StatusCode int
 	Message    string
+	Kind       ErrorKind
+	RetryAfter time.Duration
 }
 
 func (e *APIError) Error() string {
@@ -43,47 +66,93 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("fluree: %s (status %d)", e.Message, e.StatusCode)
 }
 
-func (c *Client) doPost(ctx context.Context, endpoint string, payload any) (any, error) {
+// doPost encodes payload as JSON, POSTs it to endpoint and decodes the
+// response into out.
+func (c *Client) doPost(ctx context.Context, endpoint string, payload, out any, mode retryMode) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("fluree: encode payload: %w", err)
+	}
+	data, err := c.doRequest(ctx, endpoint, "application/json", "", body, mode)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(data, out)
+}
+
+// doRequest POSTs body to endpoint and returns the raw response payload,
+// retrying failures permitted by mode according to the client's policy.
+func (c *Client) doRequest(ctx context.Context, endpoint, contentType, accept string, body []byte, mode retryMode) ([]byte, error) {
 	base, err := url.Parse(c.config.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("fluree: invalid base URL %q: %w", c.config.BaseURL, err)
 	}
 	base.Path = path.Join(strings.TrimSuffix(base.Path, "/"), endpoint)
+	target := base.String()
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("fluree: encode payload: %w", err)
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return nil, err
+		}
+		data, err := c.send(ctx, target, contentType, accept, body)
+		c.breaker.record(breakerFailure(err))
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= c.retry.MaxAttempts || !shouldRetry(err, mode) {
+			return nil, err
+		}
+		delay := c.retry.backoff(attempt, c.random)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			if c.retry.MaxRetryAfter > 0 && apiErr.RetryAfter > c.retry.MaxRetryAfter {
+				return nil, err
+			}
+			if apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+		}
+		if sleepErr := c.sleep(ctx, delay); sleepErr != nil {
+			return nil, fmt.Errorf("fluree: %w while waiting to retry: %v", sleepErr, err)
+		}
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base.String(), bytes.NewReader(body))
+// send performs a single HTTP attempt.
+func (c *Client) send(ctx context.Context, target, contentType, accept string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("fluree: build request: %w", err)
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIToken))
 	req.Header.Set("x-user-handle", c.config.TenantHandle)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fluree: perform request: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("fluree: perform request: %w", ctxErr)
+		}
+		return nil, &networkError{err: err}
 	}
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("fluree: read response: %w", err)
+		return nil, &networkError{err: fmt.Errorf("read response: %w", err)}
 	}
 	if resp.StatusCode >= 400 {
-		return nil, &APIError{StatusCode: resp.StatusCode, Message: parseErrorMessage(data, resp.Status)}
-	}
-	if len(data) == 0 {
-		return nil, nil
-	}
-	var decoded any
-	if err := json.Unmarshal(data, &decoded); err != nil {
-		return string(data), nil
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    parseErrorMessage(data, resp.Status),
+			Kind:       classifyStatus(resp.StatusCode),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
-	return decoded, nil
+	return data, nil
 }
 
 func parseErrorMessage(data []byte, fallback string) string {
@@ -116,8 +185,9 @@ type CreateDatasetRequest struct {
 	Tags        []string
 }
 
-// CreateDataset creates a dataset owned by the provided Fluree handle.
-func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req CreateDatasetRequest) (any, error) {
+// CreateDataset creates a dataset owned by the provided Fluree handle. Fields
+// missing from the response are filled in from the request.
+func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req CreateDatasetRequest) (*DatasetDescriptor, error) {
 	if ownerHandle == "" {
 		return nil, fmt.Errorf("fluree: owner handle is required")
 	}
@@ -133,20 +203,45 @@ func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req Crea
 		payload["tags"] = req.Tags
 	}
 	endpoint := path.Join("api", ownerHandle, "create-dataset")
-	return c.doPost(ctx, endpoint, payload)
+	var dataset DatasetDescriptor
+	if err := c.doPost(ctx, endpoint, payload, &dataset, retryThrottledOnly); err != nil {
+		return nil, err
+	}
+	dataset.Name = firstNonEmpty(dataset.Name, req.DatasetName)
+	dataset.Owner = firstNonEmpty(dataset.Owner, ownerHandle)
+	dataset.Ledger = firstNonEmpty(dataset.Ledger, ownerHandle+"/"+req.DatasetName)
+	dataset.StorageType = firstNonEmpty(dataset.StorageType, req.StorageType)
+	dataset.Description = firstNonEmpty(dataset.Description, req.Description)
+	dataset.Visibility = firstNonEmpty(dataset.Visibility, req.Visibility)
+	if len(dataset.Tags) == 0 {
+		dataset.Tags = req.Tags
+	}
+	return &dataset, nil
 }
 
 // TransactionRequest represents the payload for a Fluree transact request.
+// Where holds node patterns and clauses such as those built by Optional.
 type TransactionRequest struct {
 	Ledger  string
 	Insert  []map[string]any
 	Delete  []map[string]any
-	Where   []map[string]any
+	Where   []any
 	Context map[string]any
 }
 
-// Transact executes a ledger transaction.
-func (c *Client) Transact(ctx context.Context, req TransactionRequest) (any, error) {
+// Optional returns a where clause whose node patterns may fail to match
+// without discarding the solution, so deletes of absent values are skipped.
+func Optional(nodes ...map[string]any) []any {
+	clause := make([]any, 0, len(nodes)+1)
+	clause = append(clause, "optional")
+	for _, node := range nodes {
+		clause = append(clause, node)
+	}
+	return clause
+}
+
+// Transact executes a ledger transaction and returns the commit receipt.
+func (c *Client) Transact(ctx context.Context, req TransactionRequest) (*TransactionReceipt, error) {
 	if strings.TrimSpace(req.Ledger) == "" {
 		return nil, fmt.Errorf("fluree: ledger is required")
 	}
@@ -163,7 +258,60 @@ func (c *Client) Transact(ctx context.Context, req TransactionRequest) (any, err
 	if len(req.Where) > 0 {
 		payload["where"] = req.Where
 	}
-	return c.doPost(ctx, "fluree/transact", payload)
+	// Inserts whose node objects all carry IRIs can be replayed safely
+	// because RDF assertions are set-valued. Replaying a blank node mints a
+	// new one, so such inserts, like conditional updates, are only retried
+	// when throttled.
+	mode := retrySafe
+	if len(req.Delete) > 0 || len(req.Where) > 0 || hasBlankNodes(req.Insert) {
+		mode = retryThrottledOnly
+	}
+	var receipt TransactionReceipt
+	if err := c.doPost(ctx, "fluree/transact", payload, &receipt, mode); err != nil {
+		return nil, err
+	}
+	receipt.Ledger = firstNonEmpty(receipt.Ledger, req.Ledger)
+	return &receipt, nil
+}
+
+// hasBlankNodes reports whether any node object in nodes, including
+// embedded ones, lacks an @id or is identified by a blank node label.
+func hasBlankNodes(nodes []map[string]any) bool {
+	for _, node := range nodes {
+		if isBlankNode(node) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankNode(value any) bool {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if isBlankNode(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		if _, ok := v["@value"]; ok {
+			return false
+		}
+		_, isList := v["@list"]
+		_, isSet := v["@set"]
+		if !isList && !isSet {
+			id, _ := v["@id"].(string)
+			if id == "" || strings.HasPrefix(id, "_:") {
+				return true
+			}
+		}
+		for key, item := range v {
+			if key != "@id" && key != "@type" && isBlankNode(item) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // PromptRequest describes a request that renders natural language responses.
@@ -173,28 +321,40 @@ type PromptRequest struct {
 }
 
 // GeneratePrompt calls the Fluree generate-prompt endpoint.
-func (c *Client) GeneratePrompt(ctx context.Context, ownerHandle string, req PromptRequest) (any, error) {
-	return c.generateHelper(ctx, ownerHandle, "generate-prompt", req)
+func (c *Client) GeneratePrompt(ctx context.Context, ownerHandle string, req PromptRequest) (*PromptResponse, error) {
+	var resp PromptResponse
+	if err := c.generateHelper(ctx, ownerHandle, "generate-prompt", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 // GenerateSPARQL calls the Fluree generate-sparql endpoint.
-func (c *Client) GenerateSPARQL(ctx context.Context, ownerHandle string, req PromptRequest) (any, error) {
-	return c.generateHelper(ctx, ownerHandle, "generate-sparql", req)
+func (c *Client) GenerateSPARQL(ctx context.Context, ownerHandle string, req PromptRequest) (*SPARQLGeneration, error) {
+	var resp SPARQLGeneration
+	if err := c.generateHelper(ctx, ownerHandle, "generate-sparql", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 // GenerateAnswer calls the Fluree generate-answer endpoint.
-func (c *Client) GenerateAnswer(ctx context.Context, ownerHandle string, req PromptRequest) (any, error) {
-	return c.generateHelper(ctx, ownerHandle, "generate-answer", req)
+func (c *Client) GenerateAnswer(ctx context.Context, ownerHandle string, req PromptRequest) (*Answer, error) {
+	var resp Answer
+	if err := c.generateHelper(ctx, ownerHandle, "generate-answer", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
-func (c *Client) generateHelper(ctx context.Context, ownerHandle, suffix string, req PromptRequest) (any, error) {
+func (c *Client) generateHelper(ctx context.Context, ownerHandle, suffix string, req PromptRequest, out any) error {
 	if ownerHandle == "" {
-		return nil, fmt.Errorf("fluree: owner handle is required")
+		return fmt.Errorf("fluree: owner handle is required")
 	}
 	payload := map[string]any{
 		"datasets": req.Datasets,
 		"prompt":   req.Prompt,
 	}
 	endpoint := path.Join("api", ownerHandle, suffix)
-	return c.doPost(ctx, endpoint, payload)
+	return c.doPost(ctx, endpoint, payload, out, retrySafe)
 }
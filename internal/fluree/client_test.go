@@ -47,9 +47,8 @@ func TestGeneratePromptPostsPayload(t *testing.T) {
 	if err != nil {
 		t.Fatalf("generate prompt: %v", err)
 	}
-	decoded := resp.(map[string]any)
-	if decoded["prompt"].(string) != "SELECT *" {
-		t.Fatalf("unexpected response: %#v", decoded)
+	if resp.Prompt != "SELECT *" {
+		t.Fatalf("unexpected response: %#v", resp)
 	}
 }
 
@@ -81,8 +80,8 @@ func TestTransactIncludesOptionalBlocks(t *testing.T) {
 			{"@id": "ex:thing", "@type": "ex:Class"},
 		},
 		Delete: []map[string]any{},
-		Where: []map[string]any{
-			{"@id": "ex:thing"},
+		Where: []any{
+			map[string]any{"@id": "ex:thing"},
 		},
 	})
 	if err != nil {
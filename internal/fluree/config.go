@@ -1,8 +1,13 @@
 package fluree
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
+
+	"github.com/hashgraph/bhash/internal/secrets"
 )
 
 const defaultBaseURL = "https://data.flur.ee"
@@ -67,3 +72,31 @@ func (c Config) WithOverrides(apiToken, tenant, baseURL string) Config {
 	}
 	return clone
 }
+
+// IsLoopback reports whether BaseURL points at this machine, as it does when
+// bhashctl talks to the fluree serve-local stand-in.
+func (c Config) IsLoopback() bool {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+	host := base.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ResolveSecrets returns a copy of the configuration with the API token
+// dereferenced through resolver, so FLUREE_API_TOKEN and --api-token may hold
+// references such as `file:/run/secrets/fluree` instead of the raw token.
+func (c Config) ResolveSecrets(ctx context.Context, resolver secrets.Resolver) (Config, error) {
+	clone := c
+	token, err := resolver.Resolve(ctx, c.APIToken)
+	if err != nil {
+		return Config{}, fmt.Errorf("resolve FLUREE_API_TOKEN: %w", err)
+	}
+	clone.APIToken = token
+	return clone, nil
+}
@@ -54,3 +54,22 @@ func TestWithOverrides(t *testing.T) {
 		t.Fatalf("expected original config unchanged")
 	}
 }
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"http://127.0.0.1:8090": true,
+		"http://localhost:8090": true,
+		"http://[::1]:8090":     true,
+		"https://data.flur.ee":  false,
+		"http://10.0.0.5:8090":  false,
+		"http://localhost.evil": false,
+		"://not a url":          false,
+	}
+	for baseURL, want := range cases {
+		if got := (Config{BaseURL: baseURL}).IsLoopback(); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", baseURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package fluree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The dataset management endpoints follow the create-dataset naming used by
+// Fluree Cloud (POST /api/{handle}/<action>). They are experimental: Fluree
+// Cloud does not document these routes and they have only been exercised
+// against flureelocal, so ListDatasets, DescribeDataset and DeleteDataset may
+// fail against the hosted service.
+const (
+	listDatasetsAction    = "list-datasets"
+	describeDatasetAction = "describe-dataset"
+	deleteDatasetAction   = "delete-dataset"
+)
+
+// ListDatasets returns the datasets owned by ownerHandle. It is experimental.
+func (c *Client) ListDatasets(ctx context.Context, ownerHandle string) ([]DatasetDescriptor, error) {
+	if ownerHandle == "" {
+		return nil, fmt.Errorf("fluree: owner handle is required")
+	}
+	var list datasetList
+	endpoint := path.Join("api", ownerHandle, listDatasetsAction)
+	if err := c.doPost(ctx, endpoint, map[string]any{}, &list, retrySafe); err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Owner = firstNonEmpty(list[i].Owner, ownerHandle)
+		if list[i].Ledger == "" && list[i].Name != "" {
+			list[i].Ledger = list[i].Owner + "/" + list[i].Name
+		}
+	}
+	return list, nil
+}
+
+// DescribeDataset returns the descriptor of a single dataset. It is
+// experimental.
+func (c *Client) DescribeDataset(ctx context.Context, ownerHandle, datasetName string) (*DatasetDescriptor, error) {
+	if ownerHandle == "" || datasetName == "" {
+		return nil, fmt.Errorf("fluree: owner handle and dataset name are required")
+	}
+	var dataset DatasetDescriptor
+	endpoint := path.Join("api", ownerHandle, describeDatasetAction)
+	if err := c.doPost(ctx, endpoint, map[string]any{"datasetName": datasetName}, &dataset, retrySafe); err != nil {
+		return nil, err
+	}
+	dataset.Name = firstNonEmpty(dataset.Name, datasetName)
+	dataset.Owner = firstNonEmpty(dataset.Owner, ownerHandle)
+	dataset.Ledger = firstNonEmpty(dataset.Ledger, ownerHandle+"/"+datasetName)
+	return &dataset, nil
+}
+
+// DeleteDataset permanently deletes a dataset. It is experimental. Deleting
+// is not retried after gateway or network failures because the outcome of
+// the first attempt is unknown.
+func (c *Client) DeleteDataset(ctx context.Context, ownerHandle, datasetName string) error {
+	if ownerHandle == "" || datasetName == "" {
+		return fmt.Errorf("fluree: owner handle and dataset name are required")
+	}
+	endpoint := path.Join("api", ownerHandle, deleteDatasetAction)
+	var ignored json.RawMessage
+	return c.doPost(ctx, endpoint, map[string]any{"datasetName": datasetName}, &ignored, retryThrottledOnly)
+}
+
+// datasetList accepts either a bare array or an object wrapping the array
+// under "datasets" or "data".
+type datasetList []DatasetDescriptor
+
+func (l *datasetList) UnmarshalJSON(data []byte) error {
+	if fields, ok := objectFields(data); ok {
+		for _, key := range []string{"datasets", "data", "items"} {
+			if raw, ok := fields[key]; ok {
+				data = raw
+				break
+			}
+		}
+	}
+	var items []DatasetDescriptor
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("fluree: decode dataset list: %w", err)
+	}
+	*l = items
+	return nil
+}
+
+// DatasetFilter selects datasets for listing or bulk deletion. Zero-valued
+// fields match everything.
+type DatasetFilter struct {
+	Tag        string
+	NamePrefix string
+	// OlderThan matches datasets created more than this long before Now.
+	// Datasets without a creation time never match a non-zero OlderThan.
+	OlderThan time.Duration
+	Now       time.Time
+}
+
+// IsZero reports whether the filter matches every dataset.
+func (f DatasetFilter) IsZero() bool {
+	return f.Tag == "" && f.NamePrefix == "" && f.OlderThan == 0
+}
+
+// Match reports whether dataset satisfies every criterion in the filter.
+func (f DatasetFilter) Match(dataset DatasetDescriptor) bool {
+	if f.NamePrefix != "" && !strings.HasPrefix(dataset.Name, f.NamePrefix) {
+		return false
+	}
+	if f.Tag != "" {
+		found := false
+		for _, tag := range dataset.Tags {
+			if tag == f.Tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.OlderThan > 0 {
+		if dataset.CreatedAt == nil {
+			return false
+		}
+		now := f.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		if now.Sub(*dataset.CreatedAt) <= f.OlderThan {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterDatasets returns the datasets matching filter sorted by name.
+func FilterDatasets(datasets []DatasetDescriptor, filter DatasetFilter) []DatasetDescriptor {
+	var matched []DatasetDescriptor
+	for _, dataset := range datasets {
+		if filter.Match(dataset) {
+			matched = append(matched, dataset)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+	return matched
+}
+
+// ParseAge parses durations such as "36h", "90m" or "7d". The day suffix is
+// accepted in addition to the units understood by time.ParseDuration.
+func ParseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return d, nil
+}
+
+// parseTimestamp accepts RFC 3339 strings and Unix epoch values in seconds
+// or milliseconds.
+func parseTimestamp(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), true
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n).UTC(), true
+		}
+		return time.Unix(n, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,90 @@
+package fluree
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDatasetLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch r.URL.Path {
+		case "/api/tenant/list-datasets":
+			_, _ = w.Write([]byte(`{"datasets":[{"datasetName":"pilot-1","tags":["pilot"],"createdAt":"2024-08-01T00:00:00Z"},{"name":"core","createdAt":1725148800000}]}`))
+		case "/api/tenant/describe-dataset":
+			_ = json.NewEncoder(w).Encode(map[string]any{"datasetName": payload["datasetName"], "visibility": "private"})
+		case "/api/tenant/delete-dataset":
+			deleted = append(deleted, payload["datasetName"].(string))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client())
+	ctx := context.Background()
+
+	datasets, err := client.ListDatasets(ctx, "tenant")
+	if err != nil {
+		t.Fatalf("list datasets: %v", err)
+	}
+	if len(datasets) != 2 || datasets[0].Ledger != "tenant/pilot-1" || datasets[1].Name != "core" {
+		t.Fatalf("unexpected datasets: %+v", datasets)
+	}
+	if datasets[1].CreatedAt == nil || !datasets[1].CreatedAt.Equal(time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected epoch createdAt: %v", datasets[1].CreatedAt)
+	}
+
+	dataset, err := client.DescribeDataset(ctx, "tenant", "core")
+	if err != nil {
+		t.Fatalf("describe dataset: %v", err)
+	}
+	if dataset.Name != "core" || dataset.Visibility != "private" || dataset.Owner != "tenant" {
+		t.Fatalf("unexpected descriptor: %+v", dataset)
+	}
+
+	if err := client.DeleteDataset(ctx, "tenant", "pilot-1"); err != nil {
+		t.Fatalf("delete dataset: %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"pilot-1"}) {
+		t.Fatalf("unexpected deletes: %v", deleted)
+	}
+}
+
+func TestFilterDatasets(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-10 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	datasets := []DatasetDescriptor{
+		{Name: "hedera-topics-2", Tags: []string{"pilot"}, CreatedAt: &recent},
+		{Name: "hedera-topics-1", Tags: []string{"pilot"}, CreatedAt: &old},
+		{Name: "core", CreatedAt: &old},
+		{Name: "hedera-topics-undated", Tags: []string{"pilot"}},
+	}
+
+	age, err := ParseAge("7d")
+	if err != nil || age != 7*24*time.Hour {
+		t.Fatalf("ParseAge(7d) = %v, %v", age, err)
+	}
+	matched := FilterDatasets(datasets, DatasetFilter{Tag: "pilot", NamePrefix: "hedera-topics-", OlderThan: age, Now: now})
+	if len(matched) != 1 || matched[0].Name != "hedera-topics-1" {
+		t.Fatalf("unexpected matches: %+v", matched)
+	}
+	if all := FilterDatasets(datasets, DatasetFilter{}); len(all) != 4 || all[0].Name != "core" {
+		t.Fatalf("zero filter should match all sorted by name: %+v", all)
+	}
+	if _, err := ParseAge("soon"); err == nil {
+		t.Fatalf("expected invalid age error")
+	}
+}
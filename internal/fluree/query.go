@@ -0,0 +1,425 @@
+package fluree
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+const (
+	queryEndpoint       = "fluree/query"
+	sparqlQueryMIME     = "application/sparql-query"
+	sparqlResultsJSON   = "application/sparql-results+json"
+	sparqlResultsAccept = sparqlResultsJSON + ", application/json;q=0.9"
+)
+
+// QueryRequest represents an FQL (JSON-LD) query against a single ledger.
+type QueryRequest struct {
+	Ledger  string
+	Context map[string]any
+	Select  any
+	Where   any
+	OrderBy any
+	GroupBy any
+	Limit   int
+	Offset  int
+	// Options carries additional top-level query keys (for example "having"
+	// or "opts") that are passed through verbatim.
+	Options map[string]any
+}
+
+func (r QueryRequest) payload() map[string]any {
+	payload := make(map[string]any, len(r.Options)+8)
+	for k, v := range r.Options {
+		payload[k] = v
+	}
+	payload["from"] = r.Ledger
+	if r.Context != nil {
+		payload["@context"] = r.Context
+	}
+	if r.Select != nil {
+		payload["select"] = r.Select
+	}
+	if r.Where != nil {
+		payload["where"] = r.Where
+	}
+	if r.OrderBy != nil {
+		payload["orderBy"] = r.OrderBy
+	}
+	if r.GroupBy != nil {
+		payload["groupBy"] = r.GroupBy
+	}
+	if r.Limit > 0 {
+		payload["limit"] = r.Limit
+	}
+	if r.Offset > 0 {
+		payload["offset"] = r.Offset
+	}
+	return payload
+}
+
+// Query executes an FQL query and decodes the JSON response into out, which
+// should be a pointer (for example *[]map[string]any or a slice of structs).
+func (c *Client) Query(ctx context.Context, req QueryRequest, out any) error {
+	if strings.TrimSpace(req.Ledger) == "" {
+		return fmt.Errorf("fluree: ledger is required")
+	}
+	if req.Select == nil {
+		return fmt.Errorf("fluree: select clause is required")
+	}
+	body, err := json.Marshal(req.payload())
+	if err != nil {
+		return fmt.Errorf("fluree: encode query: %w", err)
+	}
+	data, err := c.doRequest(ctx, queryEndpoint, "application/json", "application/json", body, retrySafe)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("fluree: decode query results: %w", err)
+	}
+	return nil
+}
+
+// SPARQLTerm is an RDF term in the W3C SPARQL 1.1 JSON results format.
+type SPARQLTerm struct {
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Datatype string `json:"datatype,omitempty"`
+	Lang     string `json:"xml:lang,omitempty"`
+}
+
+// SPARQLResults holds the decoded results of a SPARQL SELECT or ASK query.
+type SPARQLResults struct {
+	Head struct {
+		Vars []string `json:"vars"`
+	} `json:"head"`
+	Results struct {
+		Bindings []map[string]SPARQLTerm `json:"bindings"`
+	} `json:"results"`
+	Boolean *bool `json:"boolean,omitempty"`
+}
+
+// Vars returns the projected variable names in query order.
+func (r *SPARQLResults) Vars() []string {
+	return append([]string(nil), r.Head.Vars...)
+}
+
+// Rows returns the bindings as lexical values in variable order. Unbound
+// variables produce empty strings.
+func (r *SPARQLResults) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Results.Bindings))
+	for _, binding := range r.Results.Bindings {
+		row := make([]string, len(r.Head.Vars))
+		for i, name := range r.Head.Vars {
+			row[i] = binding[name].Value
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteCSV renders the results in the same layout as `robot query` CSV
+// output: a header row of variable names followed by lexical values.
+func (r *SPARQLResults) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(r.Head.Vars); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(r.Rows()); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// QuerySPARQL executes a SPARQL query against ledger. When the query does not
+// name a dataset with FROM, the ledger is injected so that the competency
+// queries under tests/queries can be used unchanged.
+func (c *Client) QuerySPARQL(ctx context.Context, ledger, query string) (*SPARQLResults, error) {
+	if strings.TrimSpace(ledger) == "" {
+		return nil, fmt.Errorf("fluree: ledger is required")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("fluree: query is required")
+	}
+	query = withFromClause(query, ledger)
+	data, err := c.doRequest(ctx, queryEndpoint, sparqlQueryMIME, sparqlResultsAccept, []byte(query), retrySafe)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSPARQLResults(data, query)
+}
+
+var (
+	queryFormPattern    = regexp.MustCompile(`(?i)\b(SELECT|CONSTRUCT|DESCRIBE|ASK)\b`)
+	fromClausePattern   = regexp.MustCompile(`(?i)\bFROM\s*<`)
+	wherePattern        = regexp.MustCompile(`(?i)\bWHERE\s*\{|\{`)
+	selectClausePattern = regexp.MustCompile(`(?is)\bSELECT\s+(?:DISTINCT\s+|REDUCED\s+)?(.*?)(?:\bFROM\b|\bWHERE\b|\{)`)
+)
+
+func withFromClause(query, ledger string) string {
+	masked := maskQuery(query)
+	if fromClausePattern.MatchString(masked) {
+		return query
+	}
+	body := stripPrologue(masked)
+	loc := wherePattern.FindStringIndex(body)
+	if loc == nil {
+		return query
+	}
+	offset := len(masked) - len(body) + loc[0]
+	return query[:offset] + "FROM <" + ledger + ">\n" + query[offset:]
+}
+
+// stripPrologue returns the masked query text starting at the query form so
+// that keywords in the prologue are never matched.
+func stripPrologue(masked string) string {
+	if loc := queryFormPattern.FindStringIndex(masked); loc != nil {
+		return masked[loc[0]:]
+	}
+	return masked
+}
+
+var iriRefPattern = regexp.MustCompile(`^<[^<>"{}|^\x60\\\x00-\x20]*>`)
+
+// maskQuery blanks out comments, the inside of IRIs and string literals so
+// that keywords and braces they contain are not mistaken for query syntax.
+// The result has the same length as query, so offsets found in it apply to
+// the original text; newlines are kept.
+func maskQuery(query string) string {
+	masked := []byte(query)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '#':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			blank(i, i+end)
+			i += end
+		case c == '<':
+			if loc := iriRefPattern.FindStringIndex(query[i:]); loc != nil {
+				blank(i+1, i+loc[1]-1)
+				i += loc[1]
+				continue
+			}
+			i++
+		case c == '"' || c == '\'':
+			delim := string(c)
+			if strings.HasPrefix(query[i:], strings.Repeat(delim, 3)) {
+				delim = strings.Repeat(delim, 3)
+			}
+			end := i + len(delim)
+			for end < len(query) && !strings.HasPrefix(query[end:], delim) {
+				if query[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+len(delim), len(query))
+			blank(i+1, end-1)
+			i = end
+		default:
+			i++
+		}
+	}
+	return string(masked)
+}
+
+// selectVariables returns the projected variables of a SELECT query. It is
+// used to label tuple-style responses that omit the SPARQL JSON head, and
+// returns nil for SELECT *, whose variables only the server knows.
+func selectVariables(query string) []string {
+	masked := maskQuery(query)
+	loc := selectClausePattern.FindStringSubmatchIndex(masked)
+	if loc == nil {
+		return nil
+	}
+	clause := strings.NewReplacer("(", " ( ", ")", " ) ").Replace(masked[loc[2]:loc[3]])
+	var (
+		vars  []string
+		depth int
+		prev  string
+	)
+	for _, token := range strings.Fields(clause) {
+		switch {
+		case token == "(":
+			depth++
+		case token == ")":
+			depth--
+		case token[0] == '?' || token[0] == '$':
+			if depth == 0 || strings.EqualFold(prev, "AS") {
+				vars = append(vars, token[1:])
+			}
+		}
+		prev = token
+	}
+	return vars
+}
+
+// iriVariables returns the variables of query that can only bind IRIs:
+// those in subject or predicate position and the objects of rdf:type, unless
+// a BIND or VALUES clause also assigns them. Queries outside the subset
+// rdf.ParseSPARQL understands yield none.
+func iriVariables(query string) map[string]bool {
+	parsed, err := rdf.ParseSPARQL(query)
+	if err != nil {
+		return nil
+	}
+	iris, assigned := map[string]bool{}, map[string]bool{}
+	var walk func(group *rdf.GroupPattern)
+	walk = func(group *rdf.GroupPattern) {
+		if group == nil {
+			return
+		}
+		for _, element := range group.Elements {
+			switch e := element.(type) {
+			case rdf.TriplesBlock:
+				for _, pattern := range e.Patterns {
+					for _, term := range []rdf.Term{pattern.Subject, pattern.Predicate} {
+						if term.IsVariable() {
+							iris[term.Value] = true
+						}
+					}
+					if pattern.Object.IsVariable() && pattern.Predicate == rdf.IRI(rdf.RDFType) {
+						iris[pattern.Object.Value] = true
+					}
+				}
+			case rdf.OptionalPattern:
+				walk(e.Group)
+			case rdf.UnionPattern:
+				for _, branch := range e.Branches {
+					walk(branch)
+				}
+			case rdf.BindPattern:
+				assigned[e.Var] = true
+			case rdf.ValuesPattern:
+				for _, name := range e.Vars {
+					assigned[name] = true
+				}
+			}
+		}
+	}
+	walk(parsed.Where)
+	for name := range assigned {
+		delete(iris, name)
+	}
+	return iris
+}
+
+// decodeSPARQLResults decodes either the standard SPARQL JSON results or
+// Fluree's tuple rows, whose columns are named and typed from query.
+func decodeSPARQLResults(data []byte, query string) (*SPARQLResults, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		return decodeTupleResults([]byte(trimmed), selectVariables(query), iriVariables(query))
+	}
+	var results SPARQLResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("fluree: decode SPARQL results: %w", err)
+	}
+	return &results, nil
+}
+
+// decodeTupleResults converts Fluree's compact array-of-arrays response into
+// the standard SPARQL results structure. Numbers are kept as json.Number so
+// integers beyond 2^53 keep every digit. Tuples carry IRIs and strings alike
+// as JSON strings, so a string is an IRI only in a column of iris; elsewhere
+// only an {"@id": ...} value is.
+func decodeTupleResults(data []byte, vars []string, iris map[string]bool) (*SPARQLResults, error) {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("fluree: decode SPARQL results: %w", err)
+	}
+	if len(vars) == 0 && len(rows) > 0 {
+		return nil, fmt.Errorf("fluree: SPARQL results have no head to name their columns; list the projected variables instead of SELECT *")
+	}
+	results := &SPARQLResults{}
+	results.Head.Vars = vars
+	results.Results.Bindings = make([]map[string]SPARQLTerm, 0, len(rows))
+	for _, raw := range rows {
+		var values []any
+		if err := decodeNumbers(raw, &values); err != nil {
+			var single any
+			if err := decodeNumbers(raw, &single); err != nil {
+				return nil, fmt.Errorf("fluree: decode SPARQL row: %w", err)
+			}
+			values = []any{single}
+		}
+		if len(vars) != len(values) {
+			return nil, fmt.Errorf("fluree: SPARQL row has %d values for %d variables", len(values), len(vars))
+		}
+		binding := make(map[string]SPARQLTerm, len(values))
+		for i, value := range values {
+			if value == nil {
+				continue
+			}
+			binding[vars[i]] = tupleTerm(value, iris[vars[i]])
+		}
+		results.Results.Bindings = append(results.Results.Bindings, binding)
+	}
+	return results, nil
+}
+
+func decodeNumbers(data []byte, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+func tupleTerm(value any, iri bool) SPARQLTerm {
+	switch v := value.(type) {
+	case string:
+		if iri {
+			return SPARQLTerm{Type: "uri", Value: v}
+		}
+		return SPARQLTerm{Type: "literal", Value: v}
+	case map[string]any:
+		if id, ok := v["@id"].(string); ok {
+			return SPARQLTerm{Type: "uri", Value: id}
+		}
+		if lexical, ok := v["@value"]; ok {
+			term := tupleTerm(lexical, false)
+			if datatype, ok := v["@type"].(string); ok {
+				term.Datatype = datatype
+			}
+			if lang, ok := v["@language"].(string); ok {
+				term.Datatype, term.Lang = "", lang
+			}
+			return term
+		}
+		encoded, _ := json.Marshal(v)
+		return SPARQLTerm{Type: "literal", Value: string(encoded)}
+	case json.Number:
+		switch {
+		case !strings.ContainsAny(v.String(), ".eE"):
+			return SPARQLTerm{Type: "literal", Value: v.String(), Datatype: "http://www.w3.org/2001/XMLSchema#integer"}
+		case strings.ContainsAny(v.String(), "eE"):
+			return SPARQLTerm{Type: "literal", Value: v.String(), Datatype: "http://www.w3.org/2001/XMLSchema#double"}
+		}
+		return SPARQLTerm{Type: "literal", Value: v.String(), Datatype: "http://www.w3.org/2001/XMLSchema#decimal"}
+	case bool:
+		return SPARQLTerm{Type: "literal", Value: fmt.Sprint(v), Datatype: "http://www.w3.org/2001/XMLSchema#boolean"}
+	default:
+		encoded, _ := json.Marshal(v)
+		return SPARQLTerm{Type: "literal", Value: string(encoded)}
+	}
+}
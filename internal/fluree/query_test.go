@@ -0,0 +1,174 @@
+package fluree
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueryPostsFQL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fluree/query" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		if payload["from"] != "tenant/sample" {
+			t.Fatalf("unexpected ledger: %#v", payload["from"])
+		}
+		if payload["limit"].(float64) != 5 {
+			t.Fatalf("unexpected limit: %#v", payload["limit"])
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]any{{"@id": "urn:hedera:account:0.0.1001"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client())
+	var results []struct {
+		ID string `json:"@id"`
+	}
+	err := client.Query(context.Background(), QueryRequest{
+		Ledger: "tenant/sample",
+		Select: map[string]any{"?s": []string{"*"}},
+		Where:  map[string]any{"@id": "?s", "@type": "hedera:Account"},
+		Limit:  5,
+	}, &results)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "urn:hedera:account:0.0.1001" {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
+
+func TestQuerySPARQLInjectsLedger(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/sparql-query" {
+			t.Fatalf("unexpected content type: %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "FROM <tenant/sample>\nWHERE") {
+			t.Fatalf("expected FROM clause in query:\n%s", body)
+		}
+		w.Header().Set("Content-Type", "application/sparql-results+json")
+		_, _ = io.WriteString(w, `{"head":{"vars":["token","symbol"]},"results":{"bindings":[
+			{"token":{"type":"uri","value":"https://bhash.dev/examples/token/USDH"},"symbol":{"type":"literal","value":"USDH"}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client())
+	query := "PREFIX hedera: <https://bhash.dev/hedera/core/>\nSELECT ?token ?symbol\nWHERE { ?token hedera:hasSymbol ?symbol }"
+	results, err := client.QuerySPARQL(context.Background(), "tenant/sample", query)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := results.WriteCSV(&buf); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	want := "token,symbol\nhttps://bhash.dev/examples/token/USDH,USDH\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestDecodeTupleResults(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT ?account (COUNT(?token) AS ?tokens) WHERE { ?token <x:treasury> ?account } GROUP BY ?account"
+	if vars := selectVariables(query); !reflect.DeepEqual(vars, []string{"account", "tokens"}) {
+		t.Fatalf("unexpected vars: %v", vars)
+	}
+	results, err := decodeSPARQLResults([]byte(`[["urn:hedera:account:0.0.1", 2], ["urn:hedera:account:0.0.2", null]]`), query)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	rows := results.Rows()
+	want := [][]string{{"urn:hedera:account:0.0.1", "2"}, {"urn:hedera:account:0.0.2", ""}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if term := results.Results.Bindings[0]["tokens"]; term.Datatype != "http://www.w3.org/2001/XMLSchema#integer" {
+		t.Fatalf("unexpected term: %+v", term)
+	}
+
+	results, err = decodeSPARQLResults([]byte(`[["urn:hedera:token:0.0.9", 9223372036854775807000]]`), query)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if supply := results.Rows()[0][1]; supply != "9223372036854775807000" {
+		t.Fatalf("large integer lost precision: %s", supply)
+	}
+	if _, err := decodeSPARQLResults([]byte(`[["urn:hedera:token:0.0.9"]]`), "SELECT * WHERE { ?s ?p ?o }"); err == nil || !strings.Contains(err.Error(), "SELECT *") {
+		t.Fatalf("expected SELECT * tuples to be rejected, got %v", err)
+	}
+}
+
+func TestDecodeTupleResultsTypesIRIsFromTheQuery(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT ?token ?class ?memo ?treasury ?label WHERE {\n" +
+		"  ?token a ?class ; <x:memo> ?memo ; <x:treasury> ?treasury .\n" +
+		"  BIND(\"urn:not-an-iri\" AS ?label)\n}"
+	results, err := decodeSPARQLResults([]byte(`[["urn:hedera:token:0.0.9", "https://bhash.dev/hedera/core/Token", "see https://example.org/memo", {"@id": "urn:hedera:account:0.0.1"}, "urn:not-an-iri"]]`), query)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	binding := results.Results.Bindings[0]
+	for name, want := range map[string]SPARQLTerm{
+		"token":    {Type: "uri", Value: "urn:hedera:token:0.0.9"},
+		"class":    {Type: "uri", Value: "https://bhash.dev/hedera/core/Token"},
+		"memo":     {Type: "literal", Value: "see https://example.org/memo"},
+		"treasury": {Type: "uri", Value: "urn:hedera:account:0.0.1"},
+		"label":    {Type: "literal", Value: "urn:not-an-iri"},
+	} {
+		if binding[name] != want {
+			t.Errorf("%s = %+v, want %+v", name, binding[name], want)
+		}
+	}
+
+	results, err = decodeSPARQLResults([]byte(`[[{"@value": "https://example.org", "@type": "http://www.w3.org/2001/XMLSchema#anyURI"}]]`), "SELECT ?o WHERE { <x:s> <x:p> ?o }")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if term := results.Results.Bindings[0]["o"]; term != (SPARQLTerm{Type: "literal", Value: "https://example.org", Datatype: "http://www.w3.org/2001/XMLSchema#anyURI"}) {
+		t.Fatalf("unexpected value object term: %+v", term)
+	}
+}
+
+func TestQueryParsingIgnoresCommentsAndIRIs(t *testing.T) {
+	t.Parallel()
+
+	query := "# title: which SELECT ?wrong WHERE { ... }\n# module: token\n" +
+		"PREFIX ex: <https://example.org/{select}/>\n" +
+		"SELECT ?token (\"?label\" AS ?kind) # FROM <nowhere>\nWHERE { ?token ex:p \"{ WHERE\" }"
+	if vars := selectVariables(query); !reflect.DeepEqual(vars, []string{"token", "kind"}) {
+		t.Fatalf("unexpected vars: %v", vars)
+	}
+	want := "SELECT ?token (\"?label\" AS ?kind) # FROM <nowhere>\nFROM <tenant/sample>\nWHERE {"
+	if got := withFromClause(query, "tenant/sample"); !strings.Contains(got, want) {
+		t.Fatalf("unexpected FROM placement:\n%s", got)
+	}
+}
+
+func TestWithFromClauseKeepsExplicitDataset(t *testing.T) {
+	t.Parallel()
+
+	query := "SELECT ?s FROM <other/ledger> WHERE { ?s ?p ?o }"
+	if got := withFromClause(query, "tenant/sample"); got != query {
+		t.Fatalf("expected query unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,252 @@
+package fluree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TransactionReceipt describes the commit produced by a transact call. Raw
+// retains the full response so fields added by newer Fluree releases remain
+// available to callers; like the Raw fields of the other responses it is left
+// out of the JSON encoding so the typed fields are not printed twice.
+type TransactionReceipt struct {
+	Ledger  string          `json:"ledger,omitempty"`
+	T       int64           `json:"t,omitempty"`
+	TxID    string          `json:"txId,omitempty"`
+	Address string          `json:"address,omitempty"`
+	Hash    string          `json:"hash,omitempty"`
+	Raw     json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts both the flat and the nested commit layouts returned
+// by Fluree ({"commit": "fluree:…"} and {"commit": {"address": …}}).
+func (r *TransactionReceipt) UnmarshalJSON(data []byte) error {
+	*r = TransactionReceipt{Raw: cloneRaw(data)}
+	fields, ok := objectFields(data)
+	if !ok {
+		return nil
+	}
+	r.Ledger = stringField(fields, "ledger", "ledger-id", "ledgerId")
+	r.T = int64Field(fields, "t")
+	r.TxID = stringField(fields, "tx-id", "txId", "txid")
+	r.Address = stringField(fields, "address", "commit-address", "commitAddress")
+	r.Hash = stringField(fields, "hash", "commit-hash", "commitHash")
+	if commit, ok := fields["commit"]; ok {
+		var address string
+		if err := json.Unmarshal(commit, &address); err == nil {
+			r.Address = firstNonEmpty(r.Address, address)
+		} else if nested, ok := objectFields(commit); ok {
+			r.Address = firstNonEmpty(r.Address, stringField(nested, "address", "id"))
+			r.Hash = firstNonEmpty(r.Hash, stringField(nested, "hash"))
+			if r.T == 0 {
+				r.T = int64Field(nested, "t")
+			}
+		}
+	}
+	return nil
+}
+
+// DatasetDescriptor describes a dataset returned by the Fluree Cloud API.
+type DatasetDescriptor struct {
+	Name        string          `json:"name,omitempty"`
+	Owner       string          `json:"owner,omitempty"`
+	Ledger      string          `json:"ledger,omitempty"`
+	StorageType string          `json:"storageType,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Visibility  string          `json:"visibility,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	CreatedAt   *time.Time      `json:"createdAt,omitempty"`
+	Raw         json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON reads the dataset fields from either the top-level object or
+// a nested "dataset" object.
+func (d *DatasetDescriptor) UnmarshalJSON(data []byte) error {
+	*d = DatasetDescriptor{Raw: cloneRaw(data)}
+	fields, ok := objectFields(data)
+	if !ok {
+		return nil
+	}
+	if nested, ok := objectFields(fields["dataset"]); ok {
+		fields = nested
+	}
+	d.Name = stringField(fields, "datasetName", "name", "dataset")
+	d.Owner = stringField(fields, "owner", "handle", "ownerHandle")
+	d.Ledger = stringField(fields, "ledger", "ledgerId", "id")
+	d.StorageType = stringField(fields, "storageType", "storage-type")
+	d.Description = stringField(fields, "description")
+	d.Visibility = stringField(fields, "visibility")
+	if raw, ok := fields["tags"]; ok {
+		_ = json.Unmarshal(raw, &d.Tags)
+	}
+	if created, ok := parseTimestamp(stringField(fields, "createdAt", "created-at", "created", "creationDate")); ok {
+		d.CreatedAt = &created
+	}
+	if d.Ledger == "" && d.Owner != "" && d.Name != "" {
+		d.Ledger = d.Owner + "/" + d.Name
+	}
+	return nil
+}
+
+// PromptResponse is returned by the generate-prompt endpoint.
+type PromptResponse struct {
+	Prompt string          `json:"prompt,omitempty"`
+	Raw    json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts an object with a prompt field or a bare string.
+func (p *PromptResponse) UnmarshalJSON(data []byte) error {
+	*p = PromptResponse{Raw: cloneRaw(data)}
+	p.Prompt = textOrField(data, "prompt", "text")
+	return nil
+}
+
+// SPARQLGeneration is returned by the generate-sparql endpoint.
+type SPARQLGeneration struct {
+	SPARQL string          `json:"sparql,omitempty"`
+	Raw    json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts an object with a sparql field or a bare query string.
+func (g *SPARQLGeneration) UnmarshalJSON(data []byte) error {
+	*g = SPARQLGeneration{Raw: cloneRaw(data)}
+	g.SPARQL = textOrField(data, "sparql", "query")
+	return nil
+}
+
+// AnswerSource identifies a dataset or resource cited by a generated answer.
+type AnswerSource struct {
+	Dataset string          `json:"dataset,omitempty"`
+	ID      string          `json:"id,omitempty"`
+	Label   string          `json:"label,omitempty"`
+	Raw     json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts either a bare identifier or a source object.
+func (s *AnswerSource) UnmarshalJSON(data []byte) error {
+	*s = AnswerSource{Raw: cloneRaw(data)}
+	fields, ok := objectFields(data)
+	if !ok {
+		_ = json.Unmarshal(data, &s.ID)
+		return nil
+	}
+	s.Dataset = stringField(fields, "dataset", "ledger")
+	s.ID = stringField(fields, "@id", "id", "iri", "uri")
+	s.Label = stringField(fields, "label", "title", "name")
+	return nil
+}
+
+// Answer is returned by the generate-answer endpoint.
+type Answer struct {
+	Answer  string          `json:"answer,omitempty"`
+	SPARQL  string          `json:"sparql,omitempty"`
+	Sources []AnswerSource  `json:"sources,omitempty"`
+	Raw     json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts an object with answer/sources fields or a bare string.
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	*a = Answer{Raw: cloneRaw(data)}
+	a.Answer = textOrField(data, "answer", "response", "result")
+	fields, ok := objectFields(data)
+	if !ok {
+		return nil
+	}
+	a.SPARQL = stringField(fields, "sparql", "query")
+	for _, key := range []string{"sources", "citations"} {
+		if raw, ok := fields[key]; ok {
+			if err := json.Unmarshal(raw, &a.Sources); err != nil {
+				return fmt.Errorf("fluree: decode answer %s: %w", key, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// decodeResponse decodes data into out. Bodies that are not valid JSON are
+// treated as a JSON string so plain-text responses still populate the typed
+// fields.
+func decodeResponse(data []byte, out any) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	if !json.Valid(data) {
+		encoded, err := json.Marshal(string(data))
+		if err != nil {
+			return fmt.Errorf("fluree: encode text response: %w", err)
+		}
+		data = encoded
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("fluree: decode response: %w", err)
+	}
+	return nil
+}
+
+func cloneRaw(data []byte) json.RawMessage {
+	return append(json.RawMessage(nil), bytes.TrimSpace(data)...)
+}
+
+func objectFields(data []byte) (map[string]json.RawMessage, bool) {
+	var fields map[string]json.RawMessage
+	if len(data) == 0 || json.Unmarshal(data, &fields) != nil || fields == nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// stringField returns the first key holding a string (or a number, rendered
+// as text).
+func stringField(fields map[string]json.RawMessage, keys ...string) string {
+	for _, key := range keys {
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil && s != "" {
+			return s
+		}
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err == nil {
+			return n.String()
+		}
+	}
+	return ""
+}
+
+func int64Field(fields map[string]json.RawMessage, keys ...string) int64 {
+	for _, key := range keys {
+		if value := stringField(fields, key); value != "" {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+func textOrField(data []byte, keys ...string) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	if fields, ok := objectFields(data); ok {
+		return stringField(fields, keys...)
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package fluree
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransactReturnsReceipt(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ledger":"tenant/sample","t":4,"tx-id":"abc","commit":{"address":"fluree:file://commit/4.json","hash":"h4"},"extra":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client())
+	receipt, err := client.Transact(context.Background(), TransactionRequest{Ledger: "tenant/sample", Insert: []map[string]any{{"@id": "ex:a"}}})
+	if err != nil {
+		t.Fatalf("transact: %v", err)
+	}
+	if receipt.T != 4 || receipt.TxID != "abc" || receipt.Address != "fluree:file://commit/4.json" || receipt.Hash != "h4" {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(receipt.Raw, &raw); err != nil || raw["extra"] != true {
+		t.Fatalf("raw response not preserved: %s", receipt.Raw)
+	}
+	encoded, err := json.Marshal(receipt)
+	if err != nil {
+		t.Fatalf("encode receipt: %v", err)
+	}
+	if want := `{"ledger":"tenant/sample","t":4,"txId":"abc","address":"fluree:file://commit/4.json","hash":"h4"}`; string(encoded) != want {
+		t.Fatalf("receipt encodes as %s, want %s", encoded, want)
+	}
+}
+
+func TestResponseDecodingVariants(t *testing.T) {
+	t.Parallel()
+
+	var receipt TransactionReceipt
+	if err := decodeResponse([]byte(`{"t":"7","commit":"fluree:memory://7"}`), &receipt); err != nil {
+		t.Fatalf("decode receipt: %v", err)
+	}
+	if receipt.T != 7 || receipt.Address != "fluree:memory://7" {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+
+	var generated SPARQLGeneration
+	if err := decodeResponse([]byte("SELECT ?s WHERE { ?s ?p ?o }\n"), &generated); err != nil {
+		t.Fatalf("decode text SPARQL: %v", err)
+	}
+	if generated.SPARQL != "SELECT ?s WHERE { ?s ?p ?o }" {
+		t.Fatalf("unexpected SPARQL: %q", generated.SPARQL)
+	}
+
+	var answer Answer
+	body := `{"answer":"Two accounts","sparql":"SELECT ...","sources":["tenant/a",{"dataset":"tenant/b","@id":"ex:acct","label":"Treasury"}]}`
+	if err := decodeResponse([]byte(body), &answer); err != nil {
+		t.Fatalf("decode answer: %v", err)
+	}
+	if answer.Answer != "Two accounts" || answer.SPARQL != "SELECT ..." || len(answer.Sources) != 2 {
+		t.Fatalf("unexpected answer: %+v", answer)
+	}
+	if answer.Sources[0].ID != "tenant/a" || answer.Sources[1].Dataset != "tenant/b" || answer.Sources[1].Label != "Treasury" {
+		t.Fatalf("unexpected sources: %+v", answer.Sources)
+	}
+
+	var dataset DatasetDescriptor
+	if err := decodeResponse([]byte(`{"dataset":{"datasetName":"sample","owner":"tenant","tags":["demo"]}}`), &dataset); err != nil {
+		t.Fatalf("decode dataset: %v", err)
+	}
+	if dataset.Name != "sample" || dataset.Ledger != "tenant/sample" || len(dataset.Tags) != 1 {
+		t.Fatalf("unexpected dataset: %+v", dataset)
+	}
+}
@@ -0,0 +1,271 @@
+package fluree
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorKind classifies Fluree API failures so callers can branch on the
+// category rather than on raw status codes.
+type ErrorKind string
+
+const (
+	ErrorAuth       ErrorKind = "auth"
+	ErrorValidation ErrorKind = "validation"
+	ErrorNotFound   ErrorKind = "not_found"
+	ErrorConflict   ErrorKind = "conflict"
+	ErrorThrottled  ErrorKind = "throttled"
+	ErrorServer     ErrorKind = "server"
+	ErrorUnknown    ErrorKind = "unknown"
+)
+
+func classifyStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorAuth
+	case status == http.StatusNotFound:
+		return ErrorNotFound
+	case status == http.StatusConflict || status == http.StatusPreconditionFailed:
+		return ErrorConflict
+	case status == http.StatusTooManyRequests:
+		return ErrorThrottled
+	case status >= 500:
+		return ErrorServer
+	case status >= 400:
+		return ErrorValidation
+	default:
+		return ErrorUnknown
+	}
+}
+
+// ErrCircuitOpen is returned without contacting Fluree while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("fluree: circuit breaker open after repeated failures")
+
+// RetryPolicy controls how failed requests are retried. Delays grow
+// exponentially from BaseDelay up to MaxDelay with full jitter; a Retry-After
+// header overrides the computed delay when it is longer.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first. Values
+	// below 2 disable retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// MaxRetryAfter bounds how long a Retry-After header may ask the client
+	// to wait. Longer requests fail immediately with the throttled error.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by NewClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   4,
+		BaseDelay:     250 * time.Millisecond,
+		MaxDelay:      10 * time.Second,
+		MaxRetryAfter: time.Minute,
+	}
+}
+
+// NoRetry disables retries.
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// backoff returns the jittered delay before retry number attempt (1-based).
+func (p RetryPolicy) backoff(attempt int, random func() float64) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	ceiling := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && ceiling > float64(p.MaxDelay) {
+		ceiling = float64(p.MaxDelay)
+	}
+	return time.Duration(random() * ceiling)
+}
+
+// CircuitBreaker stops issuing requests after Threshold consecutive
+// retryable failures and lets a single trial request through once Cooldown
+// has elapsed.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker returns a breaker that opens after threshold consecutive
+// failures. A threshold of zero disables the breaker.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown, now: time.Now}
+}
+
+func (b *CircuitBreaker) allow() error {
+	if b == nil || b.Threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.Threshold {
+		return nil
+	}
+	if b.trial || b.clock().Sub(b.openedAt) < b.Cooldown {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+func (b *CircuitBreaker) record(failed bool) {
+	if b == nil || b.Threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openedAt = b.clock()
+	}
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now == nil {
+		return time.Now()
+	}
+	return b.now()
+}
+
+// ClientOption customises a Client.
+type ClientOption func(*Client)
+
+// WithRetryPolicy overrides the default retry policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithCircuitBreaker installs breaker. Passing nil disables the breaker.
+func WithCircuitBreaker(breaker *CircuitBreaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = breaker
+	}
+}
+
+// retryMode describes which failures are safe to retry for a request.
+type retryMode int
+
+const (
+	// retrySafe marks reads and idempotent writes: throttling, gateway
+	// errors and network failures are retried.
+	retrySafe retryMode = iota
+	// retryThrottledOnly marks writes that must not be replayed unless the
+	// server rejected them before processing (HTTP 429).
+	retryThrottledOnly
+)
+
+// shouldRetry reports whether err may be retried under mode.
+func shouldRetry(err error, mode retryMode) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Kind == ErrorThrottled:
+			return true
+		case mode == retryThrottledOnly:
+			return false
+		default:
+			switch apiErr.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				return true
+			}
+			return false
+		}
+	}
+	if mode == retryThrottledOnly || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr *networkError
+	return errors.As(err, &netErr)
+}
+
+// breakerFailure reports whether err counts against the circuit breaker.
+func breakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind == ErrorServer || apiErr.Kind == ErrorThrottled
+	}
+	var netErr *networkError
+	return errors.As(err, &netErr)
+}
+
+// networkError wraps transport failures so they can be told apart from
+// local errors such as invalid URLs.
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return "fluree: perform request: " + e.err.Error() }
+func (e *networkError) Unwrap() error { return e.err }
+
+// parseRetryAfter accepts both delta-seconds and HTTP-date values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func jitter() float64 {
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return jitterRand.Float64()
+}
@@ -0,0 +1,159 @@
+package fluree
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(server *httptest.Server, delays *[]time.Duration, opts ...ClientOption) *Client {
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client(), opts...)
+	client.random = func() float64 { return 1 }
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return client
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"t":1}`))
+		}
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := newRetryTestClient(server, &delays)
+	receipt, err := client.Transact(context.Background(), TransactionRequest{Ledger: "tenant/sample", Insert: []map[string]any{{"@id": "ex:a"}}})
+	if err != nil {
+		t.Fatalf("transact: %v", err)
+	}
+	if receipt.T != 1 || calls.Load() != 3 {
+		t.Fatalf("unexpected result after %d calls: %+v", calls.Load(), receipt)
+	}
+	if len(delays) != 2 || delays[0] != 3*time.Second || delays[1] != 500*time.Millisecond {
+		t.Fatalf("unexpected delays: %v", delays)
+	}
+}
+
+func TestRetrySkipsUnsafeFailures(t *testing.T) {
+	t.Parallel()
+
+	requests := map[string]TransactionRequest{
+		"conditional update": {
+			Ledger: "tenant/sample",
+			Where:  []any{map[string]any{"@id": "?s"}},
+			Delete: []map[string]any{{"@id": "?s"}},
+		},
+		"blank node insert": {
+			Ledger: "tenant/sample",
+			Insert: []map[string]any{{"@id": "_:b0", "ex:p": "v"}},
+		},
+		"embedded blank node insert": {
+			Ledger: "tenant/sample",
+			Insert: []map[string]any{{"@id": "ex:a", "ex:p": []any{map[string]any{"ex:q": map[string]any{"@value": "v"}}}}},
+		},
+	}
+	for name, req := range requests {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		var delays []time.Duration
+		client := newRetryTestClient(server, &delays)
+		_, err := client.Transact(context.Background(), req)
+		server.Close()
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Kind != ErrorServer {
+			t.Fatalf("%s: expected server APIError, got %v", name, err)
+		}
+		if calls.Load() != 1 {
+			t.Fatalf("%s should not be retried, got %d calls", name, calls.Load())
+		}
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]ErrorKind{
+		http.StatusBadRequest:          ErrorValidation,
+		http.StatusUnauthorized:        ErrorAuth,
+		http.StatusForbidden:           ErrorAuth,
+		http.StatusNotFound:            ErrorNotFound,
+		http.StatusConflict:            ErrorConflict,
+		http.StatusUnprocessableEntity: ErrorValidation,
+		http.StatusTooManyRequests:     ErrorThrottled,
+		http.StatusInternalServerError: ErrorServer,
+	}
+	for status, want := range cases {
+		if got := classifyStatus(status); got != want {
+			t.Fatalf("status %d classified as %s, want %s", status, got, want)
+		}
+	}
+
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	if got := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); got != 90*time.Second {
+		t.Fatalf("unexpected HTTP-date Retry-After: %v", got)
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"prompt":"ok"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	var delays []time.Duration
+	client := newRetryTestClient(server, &delays, WithRetryPolicy(NoRetry()), WithCircuitBreaker(breaker))
+
+	req := PromptRequest{Datasets: []string{"tenant/sample"}, Prompt: "List"}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GeneratePrompt(context.Background(), "tenant", req); err == nil {
+			t.Fatalf("expected failure %d", i)
+		}
+	}
+	if _, err := client.GeneratePrompt(context.Background(), "tenant", req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("open circuit should not contact the server, got %d calls", calls.Load())
+	}
+
+	failing.Store(false)
+	now = now.Add(2 * time.Minute)
+	resp, err := client.GeneratePrompt(context.Background(), "tenant", req)
+	if err != nil || resp.Prompt != "ok" {
+		t.Fatalf("expected trial request to succeed, got %v", err)
+	}
+}
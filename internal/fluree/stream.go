@@ -0,0 +1,313 @@
+package fluree
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// NodeReader yields JSON-LD node objects one at a time so large inputs can
+// be transacted without holding them in memory.
+type NodeReader interface {
+	// Next returns the next node, or io.EOF when the input is exhausted.
+	Next() (map[string]any, error)
+}
+
+// NewNodeDecoder reads node objects from r. The input may be a JSON array of
+// objects or newline-delimited JSON (NDJSON) with one object per line.
+// Numbers are decoded as json.Number so large integers survive unchanged.
+func NewNodeDecoder(r io.Reader) NodeReader {
+	buffered := bufio.NewReader(r)
+	decoder := json.NewDecoder(buffered)
+	decoder.UseNumber()
+	return &nodeDecoder{reader: buffered, decoder: decoder}
+}
+
+type nodeDecoder struct {
+	reader  *bufio.Reader
+	decoder *json.Decoder
+	started bool
+	array   bool
+	done    bool
+	index   int
+}
+
+func (d *nodeDecoder) Next() (map[string]any, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+	if !d.started {
+		d.started = true
+		first, err := firstNonSpace(d.reader)
+		if err != nil {
+			d.done = true
+			return nil, err
+		}
+		if first == '[' {
+			if _, err := d.decoder.Token(); err != nil {
+				return nil, fmt.Errorf("fluree: decode nodes: %w", err)
+			}
+			d.array = true
+		}
+	}
+	if d.array && !d.decoder.More() {
+		d.done = true
+		if _, err := d.decoder.Token(); err != nil {
+			return nil, fmt.Errorf("fluree: decode nodes: %w", err)
+		}
+		return nil, io.EOF
+	}
+	var node map[string]any
+	if err := d.decoder.Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) && !d.array {
+			d.done = true
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("fluree: decode node %d: %w", d.index+1, err)
+	}
+	d.index++
+	if node == nil {
+		return nil, fmt.Errorf("fluree: decode node %d: expected a JSON object", d.index)
+	}
+	return node, nil
+}
+
+// firstNonSpace returns the first non-whitespace byte of r without
+// consuming it.
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c, r.UnreadByte()
+	}
+}
+
+// NodeSlice returns a NodeReader over nodes.
+func NodeSlice(nodes []map[string]any) NodeReader {
+	return &nodeSlice{nodes: nodes}
+}
+
+type nodeSlice struct {
+	nodes []map[string]any
+	next  int
+}
+
+func (s *nodeSlice) Next() (map[string]any, error) {
+	if s.next >= len(s.nodes) {
+		return nil, io.EOF
+	}
+	s.next++
+	return s.nodes[s.next-1], nil
+}
+
+// ReadAllNodes drains r.
+func ReadAllNodes(r NodeReader) ([]map[string]any, error) {
+	var nodes []map[string]any
+	for {
+		node, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nodes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+// ResumeMarker records how far a streamed transaction has progressed.
+// Persist it after each batch and pass it back through StreamOptions.Resume
+// to continue after the last committed batch. Digest is the SHA-256 of the
+// committed nodes, so a marker is only accepted for the input it was
+// recorded against.
+type ResumeMarker struct {
+	Ledger  string `json:"ledger"`
+	Nodes   int64  `json:"nodes"`
+	Batches int    `json:"batches"`
+	Digest  string `json:"digest,omitempty"`
+	T       int64  `json:"t,omitempty"`
+	TxID    string `json:"txId,omitempty"`
+}
+
+// StreamOptions configures TransactStream.
+type StreamOptions struct {
+	Limits BatchLimits
+	// Resume skips the nodes committed by an earlier run against the same
+	// input. The skipped nodes must match the marker's digest.
+	Resume *ResumeMarker
+	// OnBatch is called after each batch commits, in submission order. An
+	// error stops the stream.
+	OnBatch func(BatchResult) error
+}
+
+// BatchResult describes one committed batch. FirstNode is the zero-based
+// position of the batch's first node in the input.
+type BatchResult struct {
+	Batch     int                 `json:"batch"`
+	FirstNode int64               `json:"firstNode"`
+	Nodes     int                 `json:"nodes"`
+	Bytes     int                 `json:"bytes"`
+	Receipt   *TransactionReceipt `json:"receipt,omitempty"`
+	Marker    ResumeMarker        `json:"-"`
+}
+
+// StreamResult summarises a TransactStream run. Marker reflects the last
+// committed batch, including when the stream stopped with an error.
+type StreamResult struct {
+	Ledger  string        `json:"ledger"`
+	Skipped int64         `json:"skipped,omitempty"`
+	Nodes   int64         `json:"nodes"`
+	Batches []BatchResult `json:"batches"`
+	Marker  ResumeMarker  `json:"marker"`
+}
+
+// TransactStream reads insert nodes from nodes and commits them to
+// req.Ledger in order, one size-bounded transaction at a time. req supplies
+// the ledger and context; conditional transactions (delete or where) cannot
+// be split and are rejected. Each batch is an insert-only transaction and is
+// retried like Transact: batches containing blank nodes are only retried
+// when throttled, since replaying a committed batch would duplicate them.
+// Blank node labels are scoped to a batch, so a node that refers to a blank
+// node label used by an already committed batch is rejected with an error
+// instead of silently minting a second, unrelated node; SplitTransaction
+// keeps such nodes together.
+func (c *Client) TransactStream(ctx context.Context, req TransactionRequest, nodes NodeReader, opts StreamOptions) (*StreamResult, error) {
+	if len(req.Delete) > 0 || len(req.Where) > 0 {
+		return nil, fmt.Errorf("fluree: conditional transactions cannot be streamed")
+	}
+	if len(req.Insert) > 0 {
+		return nil, fmt.Errorf("fluree: streamed transactions read inserts from the node reader")
+	}
+	if req.Ledger == "" {
+		return nil, fmt.Errorf("fluree: ledger is required")
+	}
+	marker := ResumeMarker{Ledger: req.Ledger}
+	if opts.Resume != nil {
+		if opts.Resume.Ledger != "" && opts.Resume.Ledger != req.Ledger {
+			return nil, fmt.Errorf("fluree: resume marker is for ledger %s, not %s", opts.Resume.Ledger, req.Ledger)
+		}
+		marker = *opts.Resume
+		marker.Ledger = req.Ledger
+	}
+	b, err := newBatcher(req, opts.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StreamResult{Ledger: req.Ledger, Marker: marker}
+	digest := sha256.New()
+	var position int64
+	// committed maps each blank node label of a committed batch to that
+	// batch; 0 stands for the batches of the run being resumed.
+	committed := map[string]int{}
+	commit := func(batch []map[string]any, size int) error {
+		first := result.Marker.Nodes
+		receipt, err := c.Transact(ctx, TransactionRequest{Ledger: req.Ledger, Context: req.Context, Insert: batch})
+		if err != nil {
+			return fmt.Errorf("fluree: batch %d (nodes %d-%d): %w", result.Marker.Batches+1, first, first+int64(len(batch))-1, err)
+		}
+		for _, node := range batch {
+			if err := hashNode(digest, node); err != nil {
+				return err
+			}
+			for _, label := range blankNodeLabels(node, nil) {
+				committed[label] = result.Marker.Batches + 1
+			}
+		}
+		result.Marker.Nodes += int64(len(batch))
+		result.Marker.Batches++
+		result.Marker.Digest = hex.EncodeToString(digest.Sum(nil))
+		result.Marker.T = receipt.T
+		result.Marker.TxID = receipt.TxID
+		result.Nodes += int64(len(batch))
+		record := BatchResult{
+			Batch:     result.Marker.Batches,
+			FirstNode: first,
+			Nodes:     len(batch),
+			Bytes:     size,
+			Receipt:   receipt,
+			Marker:    result.Marker,
+		}
+		result.Batches = append(result.Batches, record)
+		if opts.OnBatch != nil {
+			return opts.OnBatch(record)
+		}
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		node, err := nodes.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		position++
+		if position <= marker.Nodes {
+			if err := hashNode(digest, node); err != nil {
+				return result, err
+			}
+			for _, label := range blankNodeLabels(node, nil) {
+				committed[label] = 0
+			}
+			result.Skipped++
+			if position == marker.Nodes && hex.EncodeToString(digest.Sum(nil)) != marker.Digest {
+				return result, fmt.Errorf("fluree: the first %d nodes of the input differ from those the resume marker recorded; the input changed since the marker was written", marker.Nodes)
+			}
+			continue
+		}
+		full, size, err := b.add(node)
+		if err != nil {
+			return result, fmt.Errorf("fluree: encode insert node %d: %w", position, err)
+		}
+		if full != nil {
+			if err := commit(full, size); err != nil {
+				return result, err
+			}
+		}
+		for _, label := range blankNodeLabels(node, nil) {
+			if batch, ok := committed[label]; ok {
+				where := fmt.Sprintf("batch %d", batch)
+				if batch == 0 {
+					where = "the resumed run"
+				}
+				return result, fmt.Errorf("fluree: insert node %d refers to blank node %s, which %s already committed; blank nodes cannot be shared across batches, so give it an IRI", position, label, where)
+			}
+		}
+	}
+	if position < marker.Nodes {
+		return result, fmt.Errorf("fluree: resume marker expects at least %d nodes but the input has %d", marker.Nodes, position)
+	}
+	if last, size := b.flush(); last != nil {
+		if err := commit(last, size); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// hashNode adds the canonical encoding of node to the resume digest.
+// encoding/json sorts map keys, so equal nodes always hash alike.
+func hashNode(digest io.Writer, node map[string]any) error {
+	encoded, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("fluree: encode insert node: %w", err)
+	}
+	_, err = digest.Write(append(encoded, '\n'))
+	return err
+}
@@ -0,0 +1,176 @@
+package fluree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNodeDecoderReadsArraysAndNDJSON(t *testing.T) {
+	t.Parallel()
+
+	want := []map[string]any{
+		{"@id": "ex:a", "ex:n": json.Number("12345678901234567")},
+		{"@id": "ex:b"},
+	}
+	for name, input := range map[string]string{
+		"array":  "  [\n{\"@id\":\"ex:a\",\"ex:n\":12345678901234567},\n{\"@id\":\"ex:b\"}\n]\n",
+		"ndjson": "{\"@id\":\"ex:a\",\"ex:n\":12345678901234567}\n\n{\"@id\":\"ex:b\"}\n",
+	} {
+		nodes, err := ReadAllNodes(NewNodeDecoder(strings.NewReader(input)))
+		if err != nil {
+			t.Fatalf("%s: ReadAllNodes returned error: %v", name, err)
+		}
+		if !reflect.DeepEqual(nodes, want) {
+			t.Fatalf("%s: unexpected nodes: %#v", name, nodes)
+		}
+	}
+
+	if nodes, err := ReadAllNodes(NewNodeDecoder(strings.NewReader(" \n"))); err != nil || len(nodes) != 0 {
+		t.Fatalf("expected no nodes from blank input, got %v (err %v)", nodes, err)
+	}
+	if _, err := ReadAllNodes(NewNodeDecoder(strings.NewReader("{\"@id\":\"ex:a\"}\n[1]\n"))); err == nil || !strings.Contains(err.Error(), "node 2") {
+		t.Fatalf("expected error naming node 2, got %v", err)
+	}
+}
+
+// streamServer records the inserts of each transaction and fails the
+// transaction numbered failAt (1-based) with a validation error.
+type streamServer struct {
+	mu      sync.Mutex
+	batches [][]string
+	failAt  int
+	calls   int
+}
+
+func (s *streamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls == s.failAt {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"invalid node"}`))
+		return
+	}
+	var payload struct {
+		Insert []map[string]any `json:"insert"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	var ids []string
+	for _, node := range payload.Insert {
+		ids = append(ids, node["@id"].(string))
+	}
+	s.batches = append(s.batches, ids)
+	_, _ = fmt.Fprintf(w, `{"t":%d,"tx-id":"tx-%d"}`, len(s.batches), len(s.batches))
+}
+
+func streamNodes(n int) NodeReader {
+	nodes := make([]map[string]any, n)
+	for i := range nodes {
+		nodes[i] = map[string]any{"@id": fmt.Sprintf("ex:%d", i)}
+	}
+	return NodeSlice(nodes)
+}
+
+func TestTransactStreamBatchesAndResumes(t *testing.T) {
+	t.Parallel()
+
+	handler := &streamServer{failAt: 3}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client(), WithRetryPolicy(NoRetry()))
+	req := TransactionRequest{Ledger: "tenant/ledger", Context: map[string]any{"ex": "https://example.org/"}}
+	opts := StreamOptions{Limits: BatchLimits{MaxNodes: 2}}
+
+	var seen []int
+	opts.OnBatch = func(batch BatchResult) error {
+		seen = append(seen, batch.Batch)
+		return nil
+	}
+	result, err := client.TransactStream(context.Background(), req, streamNodes(7), opts)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(err.Error(), "batch 3 (nodes 4-5)") {
+		t.Fatalf("expected batch 3 to fail, got %v", err)
+	}
+	if result.Marker.Nodes != 4 || result.Marker.Batches != 2 || result.Marker.TxID != "tx-2" {
+		t.Fatalf("unexpected marker after failure: %+v", result.Marker)
+	}
+	if !reflect.DeepEqual(seen, []int{1, 2}) {
+		t.Fatalf("unexpected OnBatch calls: %v", seen)
+	}
+
+	marker := result.Marker
+	opts.Resume = &marker
+	result, err = client.TransactStream(context.Background(), req, streamNodes(7), opts)
+	if err != nil {
+		t.Fatalf("resumed TransactStream returned error: %v", err)
+	}
+	if result.Skipped != 4 || result.Nodes != 3 || result.Marker.Nodes != 7 || result.Marker.Batches != 4 {
+		t.Fatalf("unexpected resumed result: %+v", result)
+	}
+	if len(result.Batches) != 2 || result.Batches[0].Batch != 3 || result.Batches[0].FirstNode != 4 {
+		t.Fatalf("unexpected resumed batches: %+v", result.Batches)
+	}
+	want := [][]string{{"ex:0", "ex:1"}, {"ex:2", "ex:3"}, {"ex:4", "ex:5"}, {"ex:6"}}
+	if !reflect.DeepEqual(handler.batches, want) {
+		t.Fatalf("unexpected committed batches: %v", handler.batches)
+	}
+
+	if _, err := client.TransactStream(context.Background(), req, streamNodes(3), opts); err == nil {
+		t.Fatalf("expected error when the marker is past the end of the input")
+	}
+	edited, err := ReadAllNodes(streamNodes(7))
+	if err != nil {
+		t.Fatalf("ReadAllNodes: %v", err)
+	}
+	edited[1]["ex:index"] = 42
+	committed := len(handler.batches)
+	if _, err := client.TransactStream(context.Background(), req, NodeSlice(edited), opts); err == nil || !strings.Contains(err.Error(), "differ") {
+		t.Fatalf("expected a changed input to be refused, got %v", err)
+	}
+	if len(handler.batches) != committed {
+		t.Fatalf("changed input should not be committed: %v", handler.batches)
+	}
+	if _, err := client.TransactStream(context.Background(), TransactionRequest{Ledger: "tenant/ledger", Where: []any{map[string]any{}}}, streamNodes(1), StreamOptions{}); err == nil {
+		t.Fatalf("expected conditional transactions to be rejected")
+	}
+}
+
+func TestTransactStreamRejectsBlankNodesSharedAcrossBatches(t *testing.T) {
+	t.Parallel()
+
+	handler := &streamServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := NewClient(Config{APIToken: "token", TenantHandle: "tenant", BaseURL: server.URL}, server.Client(), WithRetryPolicy(NoRetry()))
+	req := TransactionRequest{Ledger: "tenant/ledger"}
+	opts := StreamOptions{Limits: BatchLimits{MaxNodes: 2}}
+
+	// ex:a and ex:b share _:b0 within the first batch, which is fine; ex:c
+	// refers to it again after that batch has committed.
+	nodes := []map[string]any{
+		{"@id": "ex:a", "ex:p": map[string]any{"@id": "_:b0"}},
+		{"@id": "ex:b", "ex:p": map[string]any{"@id": "_:b0"}},
+		{"@id": "ex:c", "ex:p": map[string]any{"@id": "_:b0"}},
+	}
+	result, err := client.TransactStream(context.Background(), req, NodeSlice(nodes), opts)
+	if err == nil || !strings.Contains(err.Error(), "insert node 3 refers to blank node _:b0, which batch 1 already committed") {
+		t.Fatalf("expected the shared blank node to be rejected, got %v", err)
+	}
+	if result.Marker.Nodes != 2 || !reflect.DeepEqual(handler.batches, [][]string{{"ex:a", "ex:b"}}) {
+		t.Fatalf("only the first batch should be committed: %+v %v", result.Marker, handler.batches)
+	}
+
+	marker := result.Marker
+	opts.Resume = &marker
+	if _, err := client.TransactStream(context.Background(), req, NodeSlice(nodes), opts); err == nil || !strings.Contains(err.Error(), "which the resumed run already committed") {
+		t.Fatalf("expected a resumed run to reject the shared blank node, got %v", err)
+	}
+}
@@ -0,0 +1,369 @@
+package flureelocal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// ledger is a single dataset: its descriptor, current triples and commit
+// counter.
+type ledger struct {
+	descriptor fluree.DatasetDescriptor
+	store      *rdf.Store
+	t          int64
+	hash       string
+}
+
+// handleTransact applies an insert/delete/where transaction. Transactions
+// against unknown ledgers create them, so loaders can target a fresh ledger
+// without a create-dataset call; /fluree/create (create=true) fails instead
+// when the ledger already exists.
+func (s *Server) handleTransact(w http.ResponseWriter, body []byte, create bool) (int, error) {
+	payload, err := decodeObject(body)
+	if err != nil {
+		return 0, err
+	}
+	name, _ := payload["ledger"].(string)
+	if strings.TrimSpace(name) == "" {
+		return 0, errorf(http.StatusBadRequest, "ledger is required")
+	}
+	ctx, err := rdf.ParseJSONLDContext(firstPresent(payload, "@context", "context"))
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "invalid context: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, exists := s.ledgers[name]
+	if exists && create {
+		return 0, errorf(http.StatusConflict, "ledger %s already exists", name)
+	}
+	if !exists {
+		l = s.newLedger(name)
+	}
+
+	// Blank nodes are scoped to a transaction, so give each commit its own
+	// label prefix.
+	prefix := fmt.Sprintf("t%d-", l.t+1)
+	solutions := []rdf.Solution{{}}
+	if where, ok := payload["where"]; ok {
+		group, err := whereGroup(where, ctx)
+		if err != nil {
+			return 0, err
+		}
+		results, err := (&rdf.Query{Where: group, Limit: -1}).Evaluate(l.store)
+		if err != nil {
+			return 0, errorf(http.StatusBadRequest, "evaluate where: %v", err)
+		}
+		solutions = results.Solutions
+	}
+	deletes, err := instantiate(payload["delete"], ctx, prefix, solutions)
+	if err != nil {
+		return 0, err
+	}
+	inserts, err := instantiate(payload["insert"], ctx, prefix, solutions)
+	if err != nil {
+		return 0, err
+	}
+
+	retracted, asserted := l.changes(deletes, inserts)
+	l.store.Remove(retracted...)
+	l.store.Add(asserted...)
+	previous := l.hash
+	l.t++
+	l.hash = commitHash(previous, l.t, retracted, asserted)
+	if err := s.persist(l); err != nil {
+		l.store.Remove(asserted...)
+		l.store.Add(retracted...)
+		l.t--
+		l.hash = previous
+		return 0, err
+	}
+	s.ledgers[name] = l
+
+	txID := sha256.Sum256(body)
+	scheme := "memory"
+	if s.opts.DataDir != "" {
+		scheme = "file"
+	}
+	return writeJSON(w, http.StatusOK, map[string]any{
+		"ledger":    name,
+		"t":         l.t,
+		"tx-id":     hex.EncodeToString(txID[:]),
+		"asserted":  len(asserted),
+		"retracted": len(retracted),
+		"commit": map[string]any{
+			"address": fmt.Sprintf("fluree:%s://%s/commit/%s", scheme, name, l.hash),
+			"hash":    l.hash,
+			"t":       l.t,
+		},
+	}), nil
+}
+
+// changes returns the deletes that are present in the ledger and the inserts
+// that will be new once the deletes are applied.
+func (l *ledger) changes(deletes, inserts []rdf.Triple) (retracted, asserted []rdf.Triple) {
+	removed := make(map[rdf.Triple]bool)
+	for _, triple := range deletes {
+		if !removed[triple] && len(l.store.Match(triple.Subject, triple.Predicate, triple.Object)) > 0 {
+			removed[triple] = true
+			retracted = append(retracted, triple)
+		}
+	}
+	added := make(map[rdf.Triple]bool)
+	for _, triple := range inserts {
+		present := len(l.store.Match(triple.Subject, triple.Predicate, triple.Object)) > 0 && !removed[triple]
+		if !present && !added[triple] {
+			added[triple] = true
+			asserted = append(asserted, triple)
+		}
+	}
+	return retracted, asserted
+}
+
+// whereGroup converts an FQL where clause into a group pattern. Node
+// patterns become triple patterns and ["optional", node...] clauses become
+// OPTIONAL groups. Blank nodes (node patterns without @id) become anonymous
+// variables.
+func whereGroup(where any, ctx *rdf.JSONLDContext) (*rdf.GroupPattern, error) {
+	var clauses []any
+	switch v := where.(type) {
+	case map[string]any:
+		clauses = []any{v}
+	case []any:
+		clauses = v
+	default:
+		return nil, errorf(http.StatusBadRequest, "where must be a node pattern or an array of clauses")
+	}
+	group := &rdf.GroupPattern{}
+	var nodes []any
+	for i, clause := range clauses {
+		switch v := clause.(type) {
+		case map[string]any:
+			nodes = append(nodes, v)
+			continue
+		case []any:
+			if len(v) > 1 && v[0] == "optional" {
+				patterns, err := nodePatterns(v[1:], ctx, fmt.Sprintf("where-%d-", i))
+				if err != nil {
+					return nil, err
+				}
+				group.Elements = append(group.Elements, rdf.OptionalPattern{Group: patternGroup(patterns)})
+				continue
+			}
+		}
+		return nil, errorf(http.StatusBadRequest, "unsupported where clause %v: only node patterns and optional clauses are supported", clause)
+	}
+	if len(nodes) > 0 {
+		patterns, err := nodePatterns(nodes, ctx, "where-")
+		if err != nil {
+			return nil, err
+		}
+		// Required patterns are matched before the optional clauses are joined.
+		group.Elements = append([]rdf.PatternElement{rdf.TriplesBlock{Patterns: patterns}}, group.Elements...)
+	}
+	return group, nil
+}
+
+func nodePatterns(nodes []any, ctx *rdf.JSONLDContext, prefix string) ([]rdf.Triple, error) {
+	for _, node := range nodes {
+		if _, ok := node.(map[string]any); !ok {
+			return nil, errorf(http.StatusBadRequest, "unsupported where clause %v: only node patterns are supported", node)
+		}
+	}
+	triples, err := rdf.FromJSONLD(nodes, ctx, rdf.JSONLDOptions{BlankNodePrefix: prefix, Variables: true})
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid where clause: %v", err)
+	}
+	anonymous := func(term rdf.Term) rdf.Term {
+		if term.IsBlankNode() {
+			return rdf.Variable("_:" + term.Value)
+		}
+		return term
+	}
+	for i, triple := range triples {
+		triples[i].Subject = anonymous(triple.Subject)
+		triples[i].Object = anonymous(triple.Object)
+	}
+	return triples, nil
+}
+
+func patternGroup(patterns []rdf.Triple) *rdf.GroupPattern {
+	return &rdf.GroupPattern{Elements: []rdf.PatternElement{rdf.TriplesBlock{Patterns: patterns}}}
+}
+
+// instantiate expands a JSON-LD template once per solution. Triples that
+// mention a variable left unbound by the solution are skipped.
+func instantiate(template any, ctx *rdf.JSONLDContext, prefix string, solutions []rdf.Solution) ([]rdf.Triple, error) {
+	if template == nil {
+		return nil, nil
+	}
+	patterns, err := rdf.FromJSONLD(template, ctx, rdf.JSONLDOptions{BlankNodePrefix: prefix, Variables: true})
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid transaction data: %v", err)
+	}
+	var triples []rdf.Triple
+	for i, solution := range solutions {
+		terms := func(term rdf.Term) (rdf.Term, bool) {
+			switch {
+			case term.IsVariable():
+				value, ok := solution[term.Value]
+				return value, ok
+			case term.IsBlankNode() && i > 0:
+				return rdf.BlankNode(term.Value + "-" + strconv.Itoa(i)), true
+			}
+			return term, true
+		}
+		for _, pattern := range patterns {
+			subject, ok1 := terms(pattern.Subject)
+			predicate, ok2 := terms(pattern.Predicate)
+			object, ok3 := terms(pattern.Object)
+			if !ok1 || !ok2 || !ok3 {
+				continue
+			}
+			if subject.IsLiteral() || !predicate.IsIRI() {
+				return nil, errorf(http.StatusBadRequest, "invalid triple %s %s %s", subject, predicate, object)
+			}
+			triples = append(triples, rdf.Triple{Subject: subject, Predicate: predicate, Object: object})
+		}
+	}
+	return triples, nil
+}
+
+// commitHash chains the previous commit hash with the sorted changes of the
+// new commit.
+func commitHash(previous string, t int64, deletes, inserts []rdf.Triple) string {
+	lines := func(marker string, triples []rdf.Triple) []string {
+		out := make([]string, len(triples))
+		for i, triple := range triples {
+			out[i] = marker + triple.Subject.String() + " " + triple.Predicate.String() + " " + triple.Object.String()
+		}
+		sort.Strings(out)
+		return out
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", previous, t)
+	for _, line := range append(lines("-", deletes), lines("+", inserts)...) {
+		fmt.Fprintln(h, line)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ledgerFile is the on-disk layout of a persisted ledger: a JSON-LD document
+// whose @graph holds the current triples.
+type ledgerFile struct {
+	Dataset fluree.DatasetDescriptor `json:"dataset"`
+	T       int64                    `json:"t"`
+	Hash    string                   `json:"hash,omitempty"`
+	Context map[string]any           `json:"@context"`
+	Graph   []map[string]any         `json:"@graph"`
+}
+
+func ledgerPath(dir, name string) string {
+	return filepath.Join(dir, url.PathEscape(name)+".jsonld")
+}
+
+// persist writes l to the data directory, if one is configured, replacing the
+// previous file atomically.
+func (s *Server) persist(l *ledger) error {
+	if s.opts.DataDir == "" {
+		return nil
+	}
+	ctx := rdf.DefaultContext()
+	file := ledgerFile{
+		Dataset: l.descriptor,
+		T:       l.t,
+		Hash:    l.hash,
+		Context: ctx.Object(),
+		Graph:   rdf.ToJSONLD(l.store.Triples(), ctx),
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode ledger %s: %w", l.descriptor.Ledger, err)
+	}
+	target := ledgerPath(s.opts.DataDir, l.descriptor.Ledger)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write ledger %s: %w", l.descriptor.Ledger, err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return fmt.Errorf("write ledger %s: %w", l.descriptor.Ledger, err)
+	}
+	return nil
+}
+
+func loadLedgers(dir string) ([]*ledger, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonld"))
+	if err != nil {
+		return nil, err
+	}
+	ledgers := make([]*ledger, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("flureelocal: read %s: %w", path, err)
+		}
+		var file ledgerFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("flureelocal: decode %s: %w", path, err)
+		}
+		doc, err := decodeObject(data)
+		if err != nil {
+			return nil, fmt.Errorf("flureelocal: decode %s: %w", path, err)
+		}
+		ctx, err := rdf.ParseJSONLDContext(doc["@context"])
+		if err != nil {
+			return nil, fmt.Errorf("flureelocal: %s: %w", path, err)
+		}
+		triples, err := rdf.FromJSONLD(doc["@graph"], ctx, rdf.JSONLDOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("flureelocal: %s: %w", path, err)
+		}
+		file.Dataset.Raw = nil
+		if file.Dataset.Ledger == "" {
+			name, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(path), ".jsonld"))
+			if err != nil {
+				return nil, fmt.Errorf("flureelocal: %s: %w", path, err)
+			}
+			file.Dataset.Ledger = name
+		}
+		l := &ledger{descriptor: file.Dataset, store: rdf.NewStore(), t: file.T, hash: file.Hash}
+		l.store.Add(triples...)
+		ledgers = append(ledgers, l)
+	}
+	return ledgers, nil
+}
+
+// decodeObject decodes a JSON object, keeping numbers as json.Number so
+// integer literals survive unchanged.
+func decodeObject(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload map[string]any
+	if err := decoder.Decode(&payload); err != nil || payload == nil {
+		return nil, errorf(http.StatusBadRequest, "request body must be a JSON object")
+	}
+	return payload, nil
+}
+
+func firstPresent(payload map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if value, ok := payload[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package flureelocal
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// handleSPARQL evaluates a SPARQL query against the ledger named by its
+// FROM clause and answers in the SPARQL 1.1 JSON results format.
+func (s *Server) handleSPARQL(w http.ResponseWriter, text string) (int, error) {
+	query, err := rdf.ParseSPARQL(text)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "%v", err)
+	}
+	if len(query.From) != 1 {
+		return 0, errorf(http.StatusBadRequest, "SPARQL queries must name exactly one ledger with FROM")
+	}
+	l, err := s.ledger(query.From[0])
+	if err != nil {
+		return 0, err
+	}
+	results, err := query.Evaluate(l.store)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	var out fluree.SPARQLResults
+	out.Boolean = results.Boolean
+	out.Head.Vars = results.Vars
+	if out.Head.Vars == nil {
+		out.Head.Vars = []string{}
+	}
+	out.Results.Bindings = make([]map[string]fluree.SPARQLTerm, 0, len(results.Solutions))
+	for _, solution := range results.Solutions {
+		binding := make(map[string]fluree.SPARQLTerm, len(solution))
+		for name, term := range solution {
+			binding[name] = sparqlTerm(term)
+		}
+		out.Results.Bindings = append(out.Results.Bindings, binding)
+	}
+	w.Header().Set("Content-Type", "application/sparql-results+json")
+	return writeJSON(w, http.StatusOK, out), nil
+}
+
+func sparqlTerm(term rdf.Term) fluree.SPARQLTerm {
+	switch {
+	case term.IsIRI():
+		return fluree.SPARQLTerm{Type: "uri", Value: term.Value}
+	case term.IsBlankNode():
+		return fluree.SPARQLTerm{Type: "bnode", Value: term.Value}
+	case term.Lang != "":
+		return fluree.SPARQLTerm{Type: "literal", Value: term.Value, Lang: term.Lang}
+	case term.Datatype == rdf.XSDString:
+		return fluree.SPARQLTerm{Type: "literal", Value: term.Value}
+	}
+	return fluree.SPARQLTerm{Type: "literal", Value: term.Value, Datatype: term.Datatype}
+}
+
+// handleFQL evaluates the JSON-LD query subset used by bhashctl: select as a
+// variable, an array of variables or a {"?var": ["*"]} graph crawl; where as
+// node patterns and optional clauses; orderBy, limit and offset.
+func (s *Server) handleFQL(w http.ResponseWriter, body []byte) (int, error) {
+	payload, err := decodeObject(body)
+	if err != nil {
+		return 0, err
+	}
+	name, _ := payload["from"].(string)
+	if name == "" {
+		return 0, errorf(http.StatusBadRequest, "from is required")
+	}
+	l, err := s.ledger(name)
+	if err != nil {
+		return 0, err
+	}
+	rawContext := firstPresent(payload, "@context", "context")
+	ctx, err := rdf.ParseJSONLDContext(rawContext)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "invalid context: %v", err)
+	}
+	for _, key := range []string{"groupBy", "having"} {
+		if _, ok := payload[key]; ok {
+			return 0, errorf(http.StatusNotImplemented, "%s is not supported by the local Fluree server", key)
+		}
+	}
+
+	query := &rdf.Query{Limit: -1, Where: &rdf.GroupPattern{}}
+	if where, ok := payload["where"]; ok {
+		if query.Where, err = whereGroup(where, ctx); err != nil {
+			return 0, err
+		}
+	}
+	if query.OrderBy, err = orderConditions(payload["orderBy"]); err != nil {
+		return 0, err
+	}
+	if query.Limit, err = intOption(payload, "limit", -1); err != nil {
+		return 0, err
+	}
+	if query.Offset, err = intOption(payload, "offset", 0); err != nil {
+		return 0, err
+	}
+
+	selection, err := parseSelect(payload["select"])
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range selection.vars {
+		query.Projection = append(query.Projection, rdf.Projection{Var: name})
+	}
+	results, err := query.Evaluate(l.store)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	compact := prefixes(rawContext)
+	rows := make([]any, 0, len(results.Solutions))
+	for _, solution := range results.Solutions {
+		switch {
+		case selection.crawl:
+			subject, ok := solution[selection.vars[0]]
+			if !ok {
+				continue
+			}
+			rows = append(rows, describeNode(l.store, subject, compact))
+		case selection.single:
+			rows = append(rows, fqlValue(solution, selection.vars[0], compact))
+		default:
+			row := make([]any, len(selection.vars))
+			for i, name := range selection.vars {
+				row[i] = fqlValue(solution, name, compact)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return writeJSON(w, http.StatusOK, rows), nil
+}
+
+func (s *Server) ledger(name string) (*ledger, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.ledgers[name]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "ledger %s not found", name)
+	}
+	return l, nil
+}
+
+type fqlSelect struct {
+	vars   []string
+	single bool
+	crawl  bool
+}
+
+func parseSelect(raw any) (fqlSelect, error) {
+	variable := func(value any) (string, bool) {
+		s, ok := value.(string)
+		if !ok || !strings.HasPrefix(s, "?") || len(s) < 2 {
+			return "", false
+		}
+		return s[1:], true
+	}
+	switch v := raw.(type) {
+	case string:
+		if name, ok := variable(v); ok {
+			return fqlSelect{vars: []string{name}, single: true}, nil
+		}
+	case []any:
+		var sel fqlSelect
+		for _, item := range v {
+			name, ok := variable(item)
+			if !ok {
+				return fqlSelect{}, errorf(http.StatusBadRequest, "unsupported select item %v", item)
+			}
+			sel.vars = append(sel.vars, name)
+		}
+		if len(sel.vars) > 0 {
+			return sel, nil
+		}
+	case map[string]any:
+		if len(v) == 1 {
+			for key := range v {
+				if name, ok := variable(key); ok {
+					return fqlSelect{vars: []string{name}, crawl: true}, nil
+				}
+			}
+		}
+	}
+	return fqlSelect{}, errorf(http.StatusBadRequest, "select must be a variable, an array of variables or {\"?var\": [\"*\"]}")
+}
+
+// orderConditions accepts "?x", "(desc ?x)" and arrays of either.
+func orderConditions(raw any) ([]rdf.OrderCondition, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		items = []any{raw}
+	}
+	conditions := make([]rdf.OrderCondition, 0, len(items))
+	for _, item := range items {
+		text, _ := item.(string)
+		fields := strings.Fields(strings.Trim(text, "()"))
+		condition := rdf.OrderCondition{}
+		if len(fields) == 2 && (strings.EqualFold(fields[0], "asc") || strings.EqualFold(fields[0], "desc")) {
+			condition.Descending = strings.EqualFold(fields[0], "desc")
+			fields = fields[1:]
+		}
+		if len(fields) != 1 || !strings.HasPrefix(fields[0], "?") {
+			return nil, errorf(http.StatusBadRequest, "unsupported orderBy %v", item)
+		}
+		condition.Expr = rdf.VariableExpression(fields[0][1:])
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func intOption(payload map[string]any, key string, fallback int) (int, error) {
+	raw, ok := payload[key]
+	if !ok {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(stringValue(raw)))
+	if err != nil || n < 0 {
+		return 0, errorf(http.StatusBadRequest, "%s must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case interface{ String() string }:
+		return v.String()
+	}
+	return ""
+}
+
+// prefixes extracts the prefix definitions of a query context for compacting
+// IRIs in results.
+func prefixes(raw any) rdf.Context {
+	ctx := rdf.Context{}
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]any:
+			for prefix, ns := range v {
+				if s, ok := ns.(string); ok && !strings.HasPrefix(prefix, "@") && (strings.HasSuffix(s, "/") || strings.HasSuffix(s, "#")) {
+					ctx[prefix] = s
+				}
+			}
+		}
+	}
+	collect(raw)
+	return ctx
+}
+
+func fqlValue(solution rdf.Solution, name string, ctx rdf.Context) any {
+	term, ok := solution[name]
+	if !ok {
+		return nil
+	}
+	return jsonValue(term, ctx)
+}
+
+// jsonValue renders a term the way Fluree does in FQL results: IRIs compacted
+// with the query context, native JSON for numbers and booleans, and the
+// lexical form for other literals.
+func jsonValue(term rdf.Term, ctx rdf.Context) any {
+	switch {
+	case term.IsIRI():
+		return ctx.CompactIRI(term.Value)
+	case term.IsBlankNode():
+		return "_:" + term.Value
+	case term.Datatype == rdf.XSDBoolean:
+		return term.Value == "true"
+	case term.Datatype == rdf.XSDInteger:
+		if n, err := strconv.ParseInt(term.Value, 10, 64); err == nil {
+			return n
+		}
+	case term.Datatype == rdf.XSDDecimal || term.Datatype == rdf.XSDDouble:
+		if f, err := strconv.ParseFloat(term.Value, 64); err == nil {
+			return f
+		}
+	}
+	return term.Value
+}
+
+// describeNode renders the outgoing properties of subject as a node object.
+func describeNode(store *rdf.Store, subject rdf.Term, ctx rdf.Context) map[string]any {
+	node := map[string]any{"@id": jsonValue(subject, ctx)}
+	for _, triple := range store.Match(subject, rdf.Term{}, rdf.Term{}) {
+		key := ctx.CompactIRI(triple.Predicate.Value)
+		var value any
+		if triple.Predicate.Value == rdf.RDFType {
+			key = "@type"
+			value = ctx.CompactIRI(triple.Object.Value)
+		} else if triple.Object.IsLiteral() {
+			value = jsonValue(triple.Object, ctx)
+		} else {
+			value = map[string]any{"@id": jsonValue(triple.Object, ctx)}
+		}
+		switch existing := node[key].(type) {
+		case nil:
+			node[key] = value
+		case []any:
+			node[key] = append(existing, value)
+		default:
+			node[key] = []any{existing, value}
+		}
+	}
+	return node
+}
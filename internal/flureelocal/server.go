@@ -0,0 +1,263 @@
+// Package flureelocal implements an in-process stand-in for the subset of the
+// Fluree HTTP API used by bhashctl: dataset management, JSON-LD transactions
+// and SPARQL/FQL queries. It lets bootstrap --commit, the ontology loader and
+// the competency query regression run without Fluree Cloud credentials.
+package flureelocal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// maxRequestBytes bounds request bodies; it is well above the batch limits
+// applied by the loader.
+const maxRequestBytes = 64 << 20
+
+// Options configures a Server.
+type Options struct {
+	// DataDir persists each ledger as a JSON-LD file. When empty the server
+	// keeps everything in memory.
+	DataDir string
+	// Token, when set, is required as a bearer token on every request.
+	Token string
+	// Log receives one line per request when non-nil.
+	Log io.Writer
+	// Now returns the current time; it defaults to time.Now.
+	Now func() time.Time
+}
+
+// Server serves the local Fluree API. It is safe for concurrent use.
+type Server struct {
+	opts    Options
+	mu      sync.Mutex
+	ledgers map[string]*ledger
+}
+
+// New returns a Server, loading any ledgers previously persisted to
+// opts.DataDir.
+func New(opts Options) (*Server, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Server{opts: opts, ledgers: make(map[string]*ledger)}
+	if opts.DataDir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(opts.DataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("flureelocal: create data dir: %w", err)
+	}
+	ledgers, err := loadLedgers(opts.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range ledgers {
+		s.ledgers[l.descriptor.Ledger] = l
+	}
+	return s, nil
+}
+
+// Ledgers returns the names of the ledgers held by the server.
+func (s *Server) Ledgers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.ledgers))
+	for name := range s.ledgers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// httpError is returned by handlers to produce a Fluree-style error body.
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string { return e.message }
+
+func errorf(status int, format string, args ...any) error {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, err := s.serve(w, r)
+	if err != nil {
+		var httpErr *httpError
+		if !errors.As(err, &httpErr) {
+			httpErr = &httpError{status: http.StatusInternalServerError, message: err.Error()}
+		}
+		status = httpErr.status
+		writeJSON(w, status, map[string]string{"error": http.StatusText(status), "message": httpErr.message})
+	}
+	if s.opts.Log != nil {
+		fmt.Fprintf(s.opts.Log, "%s %s %d\n", r.Method, r.URL.Path, status)
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) (int, error) {
+	if s.opts.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.opts.Token {
+		return 0, errorf(http.StatusUnauthorized, "invalid or missing bearer token")
+	}
+	if r.Method != http.MethodPost {
+		return 0, errorf(http.StatusMethodNotAllowed, "%s is not supported", r.Method)
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes+1))
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "read request: %v", err)
+	}
+	if len(body) > maxRequestBytes {
+		return 0, errorf(http.StatusRequestEntityTooLarge, "request body exceeds %d bytes", maxRequestBytes)
+	}
+
+	route := strings.Trim(path.Clean(r.URL.Path), "/")
+	switch route {
+	case "fluree/transact":
+		return s.handleTransact(w, body, false)
+	case "fluree/create":
+		return s.handleTransact(w, body, true)
+	case "fluree/query":
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/sparql-query") {
+			return s.handleSPARQL(w, string(body))
+		}
+		return s.handleFQL(w, body)
+	}
+	parts := strings.Split(route, "/")
+	if len(parts) != 3 || parts[0] != "api" || parts[1] == "" {
+		return 0, errorf(http.StatusNotFound, "unknown endpoint /%s", route)
+	}
+	owner, action := parts[1], parts[2]
+	switch action {
+	case "create-dataset":
+		return s.handleCreateDataset(w, owner, body)
+	case "list-datasets":
+		return s.handleListDatasets(w, owner)
+	case "describe-dataset", "delete-dataset":
+		var req struct {
+			DatasetName string `json:"datasetName"`
+		}
+		if err := decodeBody(body, &req); err != nil {
+			return 0, err
+		}
+		if req.DatasetName == "" {
+			return 0, errorf(http.StatusBadRequest, "datasetName is required")
+		}
+		if action == "describe-dataset" {
+			return s.handleDescribeDataset(w, owner, req.DatasetName)
+		}
+		return s.handleDeleteDataset(w, owner, req.DatasetName)
+	case "generate-prompt", "generate-sparql", "generate-answer":
+		return 0, errorf(http.StatusNotImplemented, "%s is not available on the local Fluree server", action)
+	}
+	return 0, errorf(http.StatusNotFound, "unknown endpoint /%s", route)
+}
+
+func (s *Server) handleCreateDataset(w http.ResponseWriter, owner string, body []byte) (int, error) {
+	var req struct {
+		DatasetName string   `json:"datasetName"`
+		StorageType string   `json:"storageType"`
+		Description string   `json:"description"`
+		Visibility  string   `json:"visibility"`
+		Tags        []string `json:"tags"`
+	}
+	if err := decodeBody(body, &req); err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(req.DatasetName) == "" {
+		return 0, errorf(http.StatusBadRequest, "datasetName is required")
+	}
+	name := owner + "/" + req.DatasetName
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ledgers[name]; ok {
+		return 0, errorf(http.StatusConflict, "dataset %s already exists", name)
+	}
+	l := s.newLedger(name)
+	l.descriptor.StorageType = req.StorageType
+	l.descriptor.Description = req.Description
+	l.descriptor.Visibility = req.Visibility
+	l.descriptor.Tags = req.Tags
+	if err := s.persist(l); err != nil {
+		return 0, err
+	}
+	s.ledgers[name] = l
+	return writeJSON(w, http.StatusCreated, l.descriptor), nil
+}
+
+func (s *Server) handleListDatasets(w http.ResponseWriter, owner string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	datasets := make([]fluree.DatasetDescriptor, 0, len(s.ledgers))
+	for _, l := range s.ledgers {
+		if l.descriptor.Owner == owner {
+			datasets = append(datasets, l.descriptor)
+		}
+	}
+	sort.Slice(datasets, func(i, j int) bool { return datasets[i].Name < datasets[j].Name })
+	return writeJSON(w, http.StatusOK, map[string]any{"datasets": datasets}), nil
+}
+
+func (s *Server) handleDescribeDataset(w http.ResponseWriter, owner, name string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.ledgers[owner+"/"+name]
+	if !ok {
+		return 0, errorf(http.StatusNotFound, "dataset %s/%s not found", owner, name)
+	}
+	return writeJSON(w, http.StatusOK, l.descriptor), nil
+}
+
+func (s *Server) handleDeleteDataset(w http.ResponseWriter, owner, name string) (int, error) {
+	ledgerName := owner + "/" + name
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ledgers[ledgerName]; !ok {
+		return 0, errorf(http.StatusNotFound, "dataset %s not found", ledgerName)
+	}
+	if s.opts.DataDir != "" {
+		if err := os.Remove(ledgerPath(s.opts.DataDir, ledgerName)); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("delete %s: %w", ledgerName, err)
+		}
+	}
+	delete(s.ledgers, ledgerName)
+	return writeJSON(w, http.StatusOK, map[string]any{"deleted": ledgerName}), nil
+}
+
+func (s *Server) newLedger(name string) *ledger {
+	created := s.opts.Now().UTC().Truncate(time.Second)
+	descriptor := fluree.DatasetDescriptor{Ledger: name, Name: name, CreatedAt: &created}
+	if owner, dataset, ok := strings.Cut(name, "/"); ok {
+		descriptor.Owner, descriptor.Name = owner, dataset
+	}
+	return &ledger{descriptor: descriptor, store: rdf.NewStore()}
+}
+
+func decodeBody(body []byte, out any) error {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return errorf(http.StatusBadRequest, "invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	_ = encoder.Encode(value)
+	return status
+}
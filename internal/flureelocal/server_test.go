@@ -0,0 +1,234 @@
+package flureelocal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+func newTestClient(t *testing.T, opts Options) (*Server, *fluree.Client) {
+	t.Helper()
+	server, err := New(opts)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	cfg := fluree.Config{APIToken: "local", TenantHandle: "bhash", BaseURL: httpServer.URL}
+	return server, fluree.NewClient(cfg, httpServer.Client(), fluree.WithRetryPolicy(fluree.NoRetry()))
+}
+
+var testContext = map[string]any{
+	"hedera": "https://bhash.dev/hedera/core/",
+	"ex":     "https://example.org/",
+}
+
+func TestServerManagesDatasets(t *testing.T) {
+	t.Parallel()
+
+	_, client := newTestClient(t, Options{})
+	ctx := context.Background()
+	created, err := client.CreateDataset(ctx, "bhash", fluree.CreateDatasetRequest{DatasetName: "hedera", Tags: []string{"ci"}})
+	if err != nil {
+		t.Fatalf("CreateDataset returned error: %v", err)
+	}
+	if created.Ledger != "bhash/hedera" || created.CreatedAt == nil {
+		t.Fatalf("unexpected descriptor: %+v", created)
+	}
+	_, err = client.CreateDataset(ctx, "bhash", fluree.CreateDatasetRequest{DatasetName: "hedera"})
+	var apiErr *fluree.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != fluree.ErrorConflict {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	datasets, err := client.ListDatasets(ctx, "bhash")
+	if err != nil || len(datasets) != 1 || datasets[0].Name != "hedera" || !reflect.DeepEqual(datasets[0].Tags, []string{"ci"}) {
+		t.Fatalf("unexpected datasets %+v (err %v)", datasets, err)
+	}
+	if err := client.DeleteDataset(ctx, "bhash", "hedera"); err != nil {
+		t.Fatalf("DeleteDataset returned error: %v", err)
+	}
+	if _, err := client.DescribeDataset(ctx, "bhash", "hedera"); !errors.As(err, &apiErr) || apiErr.Kind != fluree.ErrorNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := client.GenerateSPARQL(ctx, "bhash", fluree.PromptRequest{Prompt: "tokens"}); !errors.As(err, &apiErr) || apiErr.StatusCode != 501 {
+		t.Fatalf("expected not implemented, got %v", err)
+	}
+}
+
+func TestServerTransactsAndQueries(t *testing.T) {
+	t.Parallel()
+
+	_, client := newTestClient(t, Options{})
+	ctx := context.Background()
+	receipt, err := client.Transact(ctx, fluree.TransactionRequest{
+		Ledger:  "bhash/hedera",
+		Context: testContext,
+		Insert: []map[string]any{
+			{"@id": "ex:token", "@type": "hedera:Token", "hedera:hasSymbol": "TKN", "hedera:decimals": 8},
+			{"@id": "ex:other", "@type": "hedera:Token", "hedera:hasSymbol": "OTH", "hedera:decimals": 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Transact returned error: %v", err)
+	}
+	if receipt.T != 1 || receipt.Hash == "" || !strings.HasPrefix(receipt.Address, "fluree:memory://bhash/hedera/") {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+
+	// Replace the symbol of ex:token with a conditional update.
+	_, err = client.Transact(ctx, fluree.TransactionRequest{
+		Ledger:  "bhash/hedera",
+		Context: testContext,
+		Where:   []any{map[string]any{"@id": "ex:token", "hedera:hasSymbol": "?symbol"}},
+		Delete:  []map[string]any{{"@id": "ex:token", "hedera:hasSymbol": "?symbol"}},
+		Insert:  []map[string]any{{"@id": "ex:token", "hedera:hasSymbol": "NEW"}},
+	})
+	if err != nil {
+		t.Fatalf("conditional Transact returned error: %v", err)
+	}
+
+	results, err := client.QuerySPARQL(ctx, "bhash/hedera", `PREFIX hedera: <https://bhash.dev/hedera/core/>
+SELECT ?token ?symbol WHERE { ?token a hedera:Token ; hedera:hasSymbol ?symbol ; hedera:decimals ?decimals FILTER(?decimals > 4) }`)
+	if err != nil {
+		t.Fatalf("QuerySPARQL returned error: %v", err)
+	}
+	if rows := results.Rows(); !reflect.DeepEqual(rows, [][]string{{"https://example.org/token", "NEW"}}) {
+		t.Fatalf("unexpected SPARQL rows: %v", rows)
+	}
+
+	var rows [][]any
+	err = client.Query(ctx, fluree.QueryRequest{
+		Ledger:  "bhash/hedera",
+		Context: testContext,
+		Select:  []any{"?token", "?decimals"},
+		Where:   []any{map[string]any{"@id": "?token", "hedera:decimals": "?decimals"}},
+		OrderBy: "(desc ?decimals)",
+	}, &rows)
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	want := [][]any{{"ex:token", float64(8)}, {"ex:other", float64(2)}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected FQL rows: %v", rows)
+	}
+}
+
+func TestServerPersistsLedgers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, client := newTestClient(t, Options{DataDir: dir})
+	ctx := context.Background()
+	if _, err := client.CreateDataset(ctx, "bhash", fluree.CreateDatasetRequest{DatasetName: "hedera"}); err != nil {
+		t.Fatalf("CreateDataset returned error: %v", err)
+	}
+	_, err := client.Transact(ctx, fluree.TransactionRequest{
+		Ledger:  "bhash/hedera",
+		Context: testContext,
+		Insert:  []map[string]any{{"@id": "ex:account", "@type": "hedera:Account", "hedera:owns": map[string]any{"hedera:hasSymbol": "TKN"}}},
+	})
+	if err != nil {
+		t.Fatalf("Transact returned error: %v", err)
+	}
+
+	reloaded, client := newTestClient(t, Options{DataDir: dir})
+	if got := reloaded.Ledgers(); !reflect.DeepEqual(got, []string{"bhash/hedera"}) {
+		t.Fatalf("unexpected ledgers after reload: %v", got)
+	}
+	results, err := client.QuerySPARQL(ctx, "bhash/hedera", `PREFIX hedera: <https://bhash.dev/hedera/core/>
+SELECT ?symbol WHERE { ?account a hedera:Account ; hedera:owns [ hedera:hasSymbol ?symbol ] }`)
+	if err != nil {
+		t.Fatalf("QuerySPARQL returned error: %v", err)
+	}
+	if rows := results.Rows(); !reflect.DeepEqual(rows, [][]string{{"TKN"}}) {
+		t.Fatalf("unexpected rows after reload: %v", rows)
+	}
+	receipt, err := client.Transact(ctx, fluree.TransactionRequest{Ledger: "bhash/hedera", Insert: []map[string]any{{"@id": "https://example.org/x", "https://example.org/p": 1}}})
+	if err != nil || receipt.T != 2 {
+		t.Fatalf("expected t=2 after reload, got %+v (err %v)", receipt, err)
+	}
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	server, err := New(Options{Token: "secret"})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client := fluree.NewClient(fluree.Config{APIToken: "wrong", TenantHandle: "bhash", BaseURL: httpServer.URL}, httpServer.Client(), fluree.WithRetryPolicy(fluree.NoRetry()))
+	var apiErr *fluree.APIError
+	if _, err := client.ListDatasets(context.Background(), "bhash"); !errors.As(err, &apiErr) || apiErr.Kind != fluree.ErrorAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+}
+
+// TestServerRunsCompetencyQueries loads the example graphs and checks the
+// competency queries against their expected results, mirroring
+// `bhashctl sparql --backend fluree`.
+func TestServerRunsCompetencyQueries(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("..", "..")
+	examples, _ := filepath.Glob(filepath.Join(root, "ontology", "examples", "*.ttl"))
+	fixtures, _ := filepath.Glob(filepath.Join(root, "tests", "fixtures", "datasets", "*.ttl"))
+	if len(examples) == 0 {
+		t.Skip("example graphs not found")
+	}
+	_, client := newTestClient(t, Options{})
+	ctx := context.Background()
+	jsonldContext := rdf.DefaultContext()
+	for _, path := range append(examples, fixtures...) {
+		graph, err := rdf.ParseTurtleFile(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		_, err = client.Transact(ctx, fluree.TransactionRequest{
+			Ledger:  "bhash/examples",
+			Context: jsonldContext.Object(),
+			Insert:  rdf.ToJSONLD(graph.Triples, jsonldContext),
+		})
+		if err != nil {
+			t.Fatalf("transact %s: %v", path, err)
+		}
+	}
+
+	queries, _ := filepath.Glob(filepath.Join(root, "tests", "queries", "*.rq"))
+	for _, queryPath := range queries {
+		name := strings.TrimSuffix(filepath.Base(queryPath), ".rq")
+		expected, err := os.ReadFile(filepath.Join(root, "tests", "fixtures", "results", name+".csv"))
+		if err != nil {
+			continue
+		}
+		query, err := os.ReadFile(queryPath)
+		if err != nil {
+			t.Fatalf("read %s: %v", queryPath, err)
+		}
+		results, err := client.QuerySPARQL(ctx, "bhash/examples", string(query))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		var got bytes.Buffer
+		if err := results.WriteCSV(&got); err != nil {
+			t.Fatalf("%s: write CSV: %v", name, err)
+		}
+		// As in the regression runner, "Z" and "+00:00" offsets compare equal.
+		normalize := strings.NewReplacer("\r\n", "\n", "+00:00", "Z").Replace
+		if strings.TrimSpace(normalize(got.String())) != strings.TrimSpace(normalize(string(expected))) {
+			t.Errorf("%s: unexpected results:\n%s\nwant:\n%s", name, got.String(), expected)
+		}
+	}
+}
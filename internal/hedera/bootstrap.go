@@ -11,6 +11,7 @@ type Network interface {
 	CreateAccount(context.Context, AccountSpec) (AccountRecord, error)
 	CreateTopic(context.Context, TopicSpec) (TopicRecord, error)
 	CreateToken(context.Context, TokenSpec) (TokenRecord, error)
+	SubmitMessage(ctx context.Context, topicID string, message []byte) (TopicMessageRecord, error)
 }
 
 // Bootstrapper orchestrates creation of Hedera artefacts before exporting the
@@ -2,8 +2,14 @@ package hedera
 
 import (
 	"context"
+	"net/http/httptest"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/flureelocal"
 )
 
 func TestBootstrapperExecute(t *testing.T) {
@@ -80,3 +86,128 @@ func TestBootstrapTransaction(t *testing.T) {
 		t.Fatalf("expected treasury link, got %+v", tokenNode)
 	}
 }
+
+func TestBootstrapUpsertTransactionsReplaceExportedProperties(t *testing.T) {
+	server, err := flureelocal.New(flureelocal.Options{})
+	if err != nil {
+		t.Fatalf("flureelocal.New returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client := fluree.NewClient(fluree.Config{APIToken: "local", TenantHandle: "bhash", BaseURL: httpServer.URL}, httpServer.Client(), fluree.WithRetryPolicy(fluree.NoRetry()))
+	ctx := context.Background()
+
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	result := BootstrapResult{
+		Network:  "testnet",
+		Accounts: []AccountRecord{{Alias: "treasury", AccountID: "0.0.1001", Memo: "Treasury", Tags: []string{"governance", "ops"}, CreatedAt: now}},
+		Tokens:   []TokenRecord{{Alias: "token", TokenID: "0.0.3001", Name: "Demo", Symbol: "DEM", TreasuryAccountID: "0.0.1001", Decimals: 2, CreatedAt: now}},
+	}
+	if _, err := client.Transact(ctx, result.Transaction("bhash/hedera")); err != nil {
+		t.Fatalf("initial Transact returned error: %v", err)
+	}
+	// Keep an unrelated statement about the account to check it survives.
+	if _, err := client.Transact(ctx, fluree.TransactionRequest{Ledger: "bhash/hedera", Insert: []map[string]any{{"@id": "urn:hedera:account:0.0.1001", "https://example.org/note": "kept"}}}); err != nil {
+		t.Fatalf("Transact returned error: %v", err)
+	}
+
+	result.Accounts[0].Memo = ""
+	result.Accounts[0].Tags = []string{"ops"}
+	result.Tokens[0].Symbol = "DM2"
+	result.Topics = []TopicRecord{{Alias: "consensus", TopicID: "0.0.2001", Memo: "Consensus", CreatedAt: now}}
+	txs := result.UpsertTransactions("bhash/hedera")
+	if len(txs) != 3 {
+		t.Fatalf("expected one transaction per artefact, got %d", len(txs))
+	}
+	// Apply the upsert twice: the second run must leave the ledger unchanged.
+	for run := 0; run < 2; run++ {
+		for _, tx := range txs {
+			if _, err := client.Transact(ctx, tx); err != nil {
+				t.Fatalf("upsert Transact returned error: %v", err)
+			}
+		}
+	}
+
+	results, err := client.QuerySPARQL(ctx, "bhash/hedera", `SELECT ?s ?p ?o FROM <bhash/hedera> WHERE {
+  ?s ?p ?o
+  FILTER(?p IN (<http://schema.org/description>, <http://schema.org/keywords>, <http://schema.org/identifier>, <https://example.org/note>, <https://hashgraph.github.io/bhash/hedera#topicId>))
+} ORDER BY ?s ?p ?o`)
+	if err != nil {
+		t.Fatalf("QuerySPARQL returned error: %v", err)
+	}
+	want := [][]string{
+		{"urn:hedera:account:0.0.1001", "http://schema.org/keywords", "ops"},
+		{"urn:hedera:account:0.0.1001", "https://example.org/note", "kept"},
+		{"urn:hedera:token:0.0.3001", "http://schema.org/identifier", "DM2"},
+		{"urn:hedera:topic:0.0.2001", "http://schema.org/description", "Consensus"},
+		{"urn:hedera:topic:0.0.2001", "https://hashgraph.github.io/bhash/hedera#topicId", "0.0.2001"},
+	}
+	if rows := results.Rows(); !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows after upsert:\n%v", rows)
+	}
+}
+
+// TestUpsertPropertiesCoverExportedKeys fills every field of each record so
+// asJSONLD emits all of its keys, and checks that the upsert retraction list
+// names exactly those keys. A key missing from the list would never be
+// retracted and would gain a value on every upsert.
+func TestUpsertPropertiesCoverExportedKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		account AccountRecord
+		topic   TopicRecord
+		token   TokenRecord
+	)
+	for _, tc := range []struct {
+		name       string
+		record     any
+		node       func() map[string]any
+		properties []string
+	}{
+		{"account", &account, func() map[string]any { return account.asJSONLD("testnet") }, accountProperties},
+		{"topic", &topic, func() map[string]any { return topic.asJSONLD("testnet") }, topicProperties},
+		{"token", &token, func() map[string]any { return token.asJSONLD("testnet") }, tokenProperties},
+	} {
+		fillFields(t, reflect.ValueOf(tc.record).Elem())
+		var emitted []string
+		for key := range tc.node() {
+			if key != "@id" && key != "@type" {
+				emitted = append(emitted, key)
+			}
+		}
+		listed := append([]string(nil), tc.properties...)
+		sort.Strings(emitted)
+		sort.Strings(listed)
+		if !reflect.DeepEqual(emitted, listed) {
+			t.Errorf("%s: asJSONLD emits %v but the upsert retracts %v", tc.name, emitted, listed)
+		}
+	}
+}
+
+// fillFields sets every field of a record struct to a non-zero value.
+func fillFields(t *testing.T, record reflect.Value) {
+	t.Helper()
+	for i := 0; i < record.NumField(); i++ {
+		field := record.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString("x")
+		case reflect.Bool:
+			field.SetBool(true)
+		case reflect.Int, reflect.Int64:
+			field.SetInt(1)
+		case reflect.Uint, reflect.Uint64:
+			field.SetUint(1)
+		case reflect.Slice:
+			field.Set(reflect.Append(reflect.MakeSlice(field.Type(), 0, 1), reflect.New(field.Type().Elem()).Elem()))
+		case reflect.Struct:
+			if field.Type() != reflect.TypeOf(time.Time{}) {
+				t.Fatalf("fillFields: unsupported struct field %s", record.Type().Field(i).Name)
+			}
+			field.Set(reflect.ValueOf(time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)))
+		default:
+			t.Fatalf("fillFields: unsupported field %s of kind %s", record.Type().Field(i).Name, field.Kind())
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package hedera
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/hashgraph/bhash/internal/secrets"
 )
 
 const defaultNetwork = "testnet"
@@ -69,3 +72,16 @@ func (c Config) WithOverrides(network, operatorID, operatorKey, mirrorURL string
 	}
 	return clone
 }
+
+// ResolveSecrets returns a copy of the configuration with the operator key
+// dereferenced through resolver, so HEDERA_OPERATOR_KEY and --operator-key may
+// hold references such as `vault:/etc/bhash/vault.json#operator`.
+func (c Config) ResolveSecrets(ctx context.Context, resolver secrets.Resolver) (Config, error) {
+	clone := c
+	key, err := resolver.Resolve(ctx, c.OperatorPrivateKey)
+	if err != nil {
+		return Config{}, fmt.Errorf("resolve HEDERA_OPERATOR_KEY: %w", err)
+	}
+	clone.OperatorPrivateKey = key
+	return clone, nil
+}
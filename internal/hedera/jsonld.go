@@ -22,15 +22,21 @@ var defaultContext = map[string]any{
 	"schema:keywords":         map[string]any{"@container": "@set"},
 }
 
+// Properties written by the exporter for each artefact kind. Upserts replace
+// all of them, so values dropped from a record are retracted too; other
+// properties and types on the subject are left alone. A record field added
+// to asJSONLD must be listed here too; TestUpsertPropertiesCoverExportedKeys
+// fails until it is.
+var (
+	accountProperties = []string{"hedera:accountId", "hedera:belongsToNetwork", "prov:generatedAtTime", "schema:name", "schema:description", "hedera:publicKey", "schema:keywords", "hedera:deleted"}
+	topicProperties   = []string{"hedera:topicId", "hedera:belongsToNetwork", "prov:generatedAtTime", "schema:description", "schema:keywords", "hedera:initialSequence", "hedera:deleted"}
+	tokenProperties   = []string{"hedera:tokenId", "hedera:belongsToNetwork", "schema:name", "schema:identifier", "prov:generatedAtTime", "schema:description", "hedera:treasuryAccount", "hedera:decimals", "hedera:initialSupply", "hedera:totalSupply", "hedera:maxSupply", "hedera:supplyType", "hedera:tokenType", "schema:keywords", "hedera:deleted"}
+)
+
 // Transaction builds a Fluree transaction that inserts JSON-LD nodes for every
 // artefact recorded in the result.
 func (r BootstrapResult) Transaction(ledger string) fluree.TransactionRequest {
-	ctx := make(map[string]any, len(defaultContext))
-	for k, v := range defaultContext {
-		ctx[k] = v
-	}
-
-	req := fluree.TransactionRequest{Ledger: ledger, Context: ctx}
+	req := fluree.TransactionRequest{Ledger: ledger, Context: jsonldContext()}
 	for _, account := range r.Accounts {
 		req.Insert = append(req.Insert, account.asJSONLD(r.Network))
 	}
@@ -43,6 +49,48 @@ func (r BootstrapResult) Transaction(ledger string) fluree.TransactionRequest {
 	return req
 }
 
+// UpsertTransactions builds one conditional transaction per artefact that
+// replaces the exported properties of its subject, so re-running an export
+// resynchronises the ledger instead of accumulating conflicting values.
+// Each transaction retracts the current values through optional where
+// clauses and inserts the new node; subjects not yet in the ledger are
+// simply inserted. Artefacts are kept in separate transactions because
+// multi-valued properties such as schema:keywords multiply the solutions
+// of a combined where clause.
+func (r BootstrapResult) UpsertTransactions(ledger string) []fluree.TransactionRequest {
+	var txs []fluree.TransactionRequest
+	for _, account := range r.Accounts {
+		txs = append(txs, upsert(ledger, account.asJSONLD(r.Network), accountProperties))
+	}
+	for _, topic := range r.Topics {
+		txs = append(txs, upsert(ledger, topic.asJSONLD(r.Network), topicProperties))
+	}
+	for _, token := range r.Tokens {
+		txs = append(txs, upsert(ledger, token.asJSONLD(r.Network), tokenProperties))
+	}
+	return txs
+}
+
+func upsert(ledger string, node map[string]any, properties []string) fluree.TransactionRequest {
+	req := fluree.TransactionRequest{Ledger: ledger, Context: jsonldContext(), Insert: []map[string]any{node}}
+	for i, property := range properties {
+		// Each property gets its own optional clause so a missing value does
+		// not prevent the others from being retracted.
+		pattern := map[string]any{"@id": node["@id"], property: fmt.Sprintf("?v%d", i)}
+		req.Where = append(req.Where, fluree.Optional(pattern))
+		req.Delete = append(req.Delete, pattern)
+	}
+	return req
+}
+
+func jsonldContext() map[string]any {
+	ctx := make(map[string]any, len(defaultContext))
+	for k, v := range defaultContext {
+		ctx[k] = v
+	}
+	return ctx
+}
+
 func (a AccountRecord) asJSONLD(network string) map[string]any {
 	node := map[string]any{
 		"@id":                     urn("account", a.AccountID),
@@ -65,6 +113,9 @@ func (a AccountRecord) asJSONLD(network string) map[string]any {
 	if len(a.Tags) > 0 {
 		node["schema:keywords"] = append([]string(nil), a.Tags...)
 	}
+	if a.Deleted {
+		node["hedera:deleted"] = true
+	}
 	return node
 }
 
@@ -87,6 +138,9 @@ func (t TopicRecord) asJSONLD(network string) map[string]any {
 	if t.Sequence > 0 {
 		node["hedera:initialSequence"] = t.Sequence
 	}
+	if t.Deleted {
+		node["hedera:deleted"] = true
+	}
 	return node
 }
 
@@ -118,6 +172,9 @@ func (t TokenRecord) asJSONLD(network string) map[string]any {
 	if t.InitialSupply > 0 {
 		node["hedera:initialSupply"] = t.InitialSupply
 	}
+	if t.TotalSupply > 0 {
+		node["hedera:totalSupply"] = t.TotalSupply
+	}
 	if t.MaxSupply != 0 {
 		node["hedera:maxSupply"] = t.MaxSupply
 	}
@@ -130,6 +187,9 @@ func (t TokenRecord) asJSONLD(network string) map[string]any {
 	if len(t.Tags) > 0 {
 		node["schema:keywords"] = append([]string(nil), t.Tags...)
 	}
+	if t.Deleted {
+		node["hedera:deleted"] = true
+	}
 	return node
 }
 
@@ -0,0 +1,236 @@
+package hedera
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by a MirrorState when the network has no record of
+// an artefact.
+var ErrNotFound = errors.New("hedera: artefact not found on the mirror node")
+
+// MirrorState looks up the current state of artefacts on a Hedera network.
+type MirrorState interface {
+	Account(ctx context.Context, id string) (AccountRecord, error)
+	Topic(ctx context.Context, id string) (TopicRecord, error)
+	Token(ctx context.Context, id string) (TokenRecord, error)
+}
+
+// TopicMessages looks up messages submitted to consensus topics.
+type TopicMessages interface {
+	TopicMessage(ctx context.Context, topicID string, sequence uint64) (TopicMessageRecord, error)
+}
+
+// DefaultMirrorRESTURL returns the public mirror node REST endpoint for the
+// named network.
+func DefaultMirrorRESTURL(network string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(network)) {
+	case "", "testnet":
+		return "https://testnet.mirrornode.hedera.com", nil
+	case "mainnet":
+		return "https://mainnet-public.mirrornode.hedera.com", nil
+	case "previewnet":
+		return "https://previewnet.mirrornode.hedera.com", nil
+	default:
+		return "", fmt.Errorf("no default mirror node for hedera network %q; pass a mirror REST URL", network)
+	}
+}
+
+// MirrorClient reads account, topic and token state from the mirror node
+// REST API (/api/v1).
+type MirrorClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewMirrorClient returns a client for the mirror node at baseURL. A nil
+// httpClient uses a client with a 30 second timeout.
+func NewMirrorClient(baseURL string, httpClient *http.Client) *MirrorClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &MirrorClient{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+type mirrorKey struct {
+	Type string `json:"_type"`
+	Key  string `json:"key"`
+}
+
+// Account returns the current state of an account.
+func (m *MirrorClient) Account(ctx context.Context, id string) (AccountRecord, error) {
+	var payload struct {
+		Account          string     `json:"account"`
+		Memo             string     `json:"memo"`
+		Key              *mirrorKey `json:"key"`
+		Deleted          bool       `json:"deleted"`
+		CreatedTimestamp string     `json:"created_timestamp"`
+	}
+	if err := m.get(ctx, "account "+id, "accounts/"+url.PathEscape(id), &payload); err != nil {
+		return AccountRecord{}, err
+	}
+	record := AccountRecord{
+		AccountID: firstNonEmpty(payload.Account, id),
+		Memo:      payload.Memo,
+		Deleted:   payload.Deleted,
+		CreatedAt: parseTimestamp(payload.CreatedTimestamp),
+	}
+	if payload.Key != nil {
+		record.PublicKey = payload.Key.Key
+	}
+	return record, nil
+}
+
+// Topic returns the current state of a consensus topic.
+func (m *MirrorClient) Topic(ctx context.Context, id string) (TopicRecord, error) {
+	var payload struct {
+		TopicID          string `json:"topic_id"`
+		Memo             string `json:"memo"`
+		Deleted          bool   `json:"deleted"`
+		CreatedTimestamp string `json:"created_timestamp"`
+	}
+	if err := m.get(ctx, "topic "+id, "topics/"+url.PathEscape(id), &payload); err != nil {
+		return TopicRecord{}, err
+	}
+	return TopicRecord{
+		TopicID:   firstNonEmpty(payload.TopicID, id),
+		Memo:      payload.Memo,
+		Deleted:   payload.Deleted,
+		CreatedAt: parseTimestamp(payload.CreatedTimestamp),
+	}, nil
+}
+
+// Token returns the current state of a token. The mirror node reports
+// numeric fields as strings.
+func (m *MirrorClient) Token(ctx context.Context, id string) (TokenRecord, error) {
+	var payload struct {
+		TokenID           string      `json:"token_id"`
+		Name              string      `json:"name"`
+		Symbol            string      `json:"symbol"`
+		Memo              string      `json:"memo"`
+		TreasuryAccountID string      `json:"treasury_account_id"`
+		Decimals          json.Number `json:"decimals"`
+		InitialSupply     json.Number `json:"initial_supply"`
+		TotalSupply       json.Number `json:"total_supply"`
+		MaxSupply         json.Number `json:"max_supply"`
+		SupplyType        string      `json:"supply_type"`
+		Type              string      `json:"type"`
+		Deleted           bool        `json:"deleted"`
+		CreatedTimestamp  string      `json:"created_timestamp"`
+	}
+	if err := m.get(ctx, "token "+id, "tokens/"+url.PathEscape(id), &payload); err != nil {
+		return TokenRecord{}, err
+	}
+	record := TokenRecord{
+		TokenID:           firstNonEmpty(payload.TokenID, id),
+		Name:              payload.Name,
+		Symbol:            payload.Symbol,
+		Memo:              payload.Memo,
+		TreasuryAccountID: payload.TreasuryAccountID,
+		SupplyType:        payload.SupplyType,
+		TokenType:         payload.Type,
+		Deleted:           payload.Deleted,
+		CreatedAt:         parseTimestamp(payload.CreatedTimestamp),
+	}
+	var err error
+	parse := func(field string, value json.Number, bits int) uint64 {
+		if err != nil || value == "" {
+			return 0
+		}
+		var n uint64
+		n, err = strconv.ParseUint(value.String(), 10, bits)
+		if err != nil {
+			err = fmt.Errorf("hedera: token %s: invalid %s %q", id, field, value)
+		}
+		return n
+	}
+	record.Decimals = uint(parse("decimals", payload.Decimals, 32))
+	record.InitialSupply = parse("initial_supply", payload.InitialSupply, 64)
+	record.TotalSupply = parse("total_supply", payload.TotalSupply, 64)
+	record.MaxSupply = int64(parse("max_supply", payload.MaxSupply, 63))
+	if err != nil {
+		return TokenRecord{}, err
+	}
+	return record, nil
+}
+
+// TopicMessage returns the message with the given sequence number of a
+// consensus topic.
+func (m *MirrorClient) TopicMessage(ctx context.Context, topicID string, sequence uint64) (TopicMessageRecord, error) {
+	var payload struct {
+		TopicID            string `json:"topic_id"`
+		SequenceNumber     uint64 `json:"sequence_number"`
+		Message            string `json:"message"`
+		ConsensusTimestamp string `json:"consensus_timestamp"`
+	}
+	path := fmt.Sprintf("topics/%s/messages/%d", url.PathEscape(topicID), sequence)
+	if err := m.get(ctx, fmt.Sprintf("topic %s message %d", topicID, sequence), path, &payload); err != nil {
+		return TopicMessageRecord{}, err
+	}
+	message, err := base64.StdEncoding.DecodeString(payload.Message)
+	if err != nil {
+		return TopicMessageRecord{}, fmt.Errorf("hedera: topic %s message %d: decode message: %w", topicID, sequence, err)
+	}
+	return TopicMessageRecord{
+		TopicID:            firstNonEmpty(payload.TopicID, topicID),
+		SequenceNumber:     payload.SequenceNumber,
+		ConsensusTimestamp: parseTimestamp(payload.ConsensusTimestamp),
+		Message:            message,
+	}, nil
+}
+
+// get decodes the JSON response for path below /api/v1. what names the
+// requested artefact in ErrNotFound errors.
+func (m *MirrorClient) get(ctx context.Context, what, path string, out any) error {
+	endpoint := fmt.Sprintf("%s/api/v1/%s", m.baseURL, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("hedera: build mirror request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("hedera: mirror request %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", what, ErrNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("hedera: mirror request %s: status %d: %s", endpoint, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("hedera: decode mirror response %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// parseTimestamp converts a mirror node "seconds.nanoseconds" timestamp.
+func parseTimestamp(value string) time.Time {
+	seconds, nanos, _ := strings.Cut(value, ".")
+	s, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	n, _ := strconv.ParseInt((nanos + "000000000")[:9], 10, 64)
+	return time.Unix(s, n).UTC()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package hedera
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMirrorClientReadsState(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/0.0.1001":
+			_, _ = w.Write([]byte(`{"account":"0.0.1001","memo":"Treasury","deleted":false,"key":{"_type":"ED25519","key":"abcd"},"created_timestamp":"1725192000.000000123"}`))
+		case "/api/v1/topics/0.0.2001":
+			_, _ = w.Write([]byte(`{"topic_id":"0.0.2001","memo":"Consensus","deleted":true,"created_timestamp":"1725192000.5"}`))
+		case "/api/v1/tokens/0.0.3001":
+			_, _ = w.Write([]byte(`{"token_id":"0.0.3001","name":"Demo","symbol":"DEM","memo":"","treasury_account_id":"0.0.1001","decimals":"2","initial_supply":"1000","total_supply":"1500","max_supply":"0","supply_type":"INFINITE","type":"FUNGIBLE_COMMON","deleted":false,"created_timestamp":"1725192000.000000000"}`))
+		case "/api/v1/topics/0.0.2001/messages/3":
+			_, _ = w.Write([]byte(`{"topic_id":"0.0.2001","sequence_number":3,"message":"aGVsbG8=","consensus_timestamp":"1725192001.000000002"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"_status":{"messages":[{"message":"Not found"}]}}`))
+		}
+	}))
+	defer server.Close()
+	client := NewMirrorClient(server.URL+"/", server.Client())
+	ctx := context.Background()
+
+	account, err := client.Account(ctx, "0.0.1001")
+	if err != nil {
+		t.Fatalf("Account returned error: %v", err)
+	}
+	created := time.Date(2024, 9, 1, 12, 0, 0, 123, time.UTC)
+	if account.Memo != "Treasury" || account.PublicKey != "abcd" || !account.CreatedAt.Equal(created) {
+		t.Fatalf("unexpected account: %+v", account)
+	}
+	topic, err := client.Topic(ctx, "0.0.2001")
+	if err != nil || !topic.Deleted || topic.CreatedAt.Nanosecond() != 500000000 {
+		t.Fatalf("unexpected topic %+v (err %v)", topic, err)
+	}
+	token, err := client.Token(ctx, "0.0.3001")
+	if err != nil {
+		t.Fatalf("Token returned error: %v", err)
+	}
+	if token.Decimals != 2 || token.InitialSupply != 1000 || token.TotalSupply != 1500 || token.TreasuryAccountID != "0.0.1001" || token.TokenType != "FUNGIBLE_COMMON" {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+	message, err := client.TopicMessage(ctx, "0.0.2001", 3)
+	if err != nil || string(message.Message) != "hello" || message.SequenceNumber != 3 || message.ConsensusTimestamp.Nanosecond() != 2 {
+		t.Fatalf("unexpected topic message %+v (err %v)", message, err)
+	}
+	if _, err := client.TopicMessage(ctx, "0.0.2001", 4); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing message, got %v", err)
+	}
+	if _, err := client.Account(ctx, "0.0.9"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDefaultMirrorRESTURL(t *testing.T) {
+	t.Parallel()
+
+	if url, err := DefaultMirrorRESTURL("mainnet"); err != nil || url != "https://mainnet-public.mirrornode.hedera.com" {
+		t.Fatalf("unexpected mainnet URL %q (err %v)", url, err)
+	}
+	if _, err := DefaultMirrorRESTURL("localnet"); err == nil {
+		t.Fatalf("expected error for a network without a default mirror node")
+	}
+}
@@ -36,6 +36,7 @@ type MockNetwork struct {
 	nextAccount int64
 	nextTopic   int64
 	nextToken   int64
+	messages    map[string][]TopicMessageRecord
 	now         func() time.Time
 }
 
@@ -46,6 +47,7 @@ func NewMockNetwork(network string, opts ...MockOption) *MockNetwork {
 		nextAccount: 1000,
 		nextTopic:   2000,
 		nextToken:   3000,
+		messages:    make(map[string][]TopicMessageRecord),
 		now: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -104,6 +106,7 @@ func (m *MockNetwork) CreateToken(_ context.Context, spec TokenSpec) (TokenRecor
 		TreasuryAccountID: spec.TreasuryAccountID,
 		Decimals:          spec.Decimals,
 		InitialSupply:     spec.InitialSupply,
+		TotalSupply:       spec.InitialSupply,
 		MaxSupply:         spec.MaxSupply,
 		SupplyType:        spec.SupplyType,
 		TokenType:         spec.TokenType,
@@ -112,3 +115,33 @@ func (m *MockNetwork) CreateToken(_ context.Context, spec TokenSpec) (TokenRecor
 	}
 	return record, nil
 }
+
+// SubmitMessage appends message to the topic. Any topic ID is accepted and
+// sequence numbers start at 1 per topic.
+func (m *MockNetwork) SubmitMessage(_ context.Context, topicID string, message []byte) (TopicMessageRecord, error) {
+	if strings.TrimSpace(topicID) == "" {
+		return TopicMessageRecord{}, fmt.Errorf("topic id is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record := TopicMessageRecord{
+		TopicID:            topicID,
+		SequenceNumber:     uint64(len(m.messages[topicID]) + 1),
+		ConsensusTimestamp: m.now(),
+		Message:            append([]byte(nil), message...),
+	}
+	m.messages[topicID] = append(m.messages[topicID], record)
+	return record, nil
+}
+
+// TopicMessage returns a message submitted with SubmitMessage, so the mock
+// can stand in for the mirror node.
+func (m *MockNetwork) TopicMessage(_ context.Context, topicID string, sequence uint64) (TopicMessageRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	messages := m.messages[topicID]
+	if sequence == 0 || sequence > uint64(len(messages)) {
+		return TopicMessageRecord{}, fmt.Errorf("topic %s message %d: %w", topicID, sequence, ErrNotFound)
+	}
+	return messages[sequence-1], nil
+}
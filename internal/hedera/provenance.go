@@ -0,0 +1,129 @@
+package hedera
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/hashgraph/hedera-sdk-go/v2"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// CanonicalizationURDNA2015 names the canonicalisation applied before a
+// dataset is hashed and signed.
+const CanonicalizationURDNA2015 = "URDNA2015"
+
+// DatasetSignature is a detached signature over a JSON-LD dataset. The
+// operator key signs the SHA-256 digest of the dataset's URDNA2015 canonical
+// N-Quads, so any re-serialisation of the same graph verifies.
+type DatasetSignature struct {
+	Canonicalization string    `json:"canonicalization"`
+	Digest           string    `json:"digest"`
+	AccountID        string    `json:"accountId"`
+	KeyType          string    `json:"keyType"`
+	PublicKey        string    `json:"publicKey"`
+	Signature        string    `json:"signature"`
+	Created          time.Time `json:"created"`
+}
+
+// EvidenceDocument collects the insert nodes of txs into one JSON-LD
+// document with the export context, ready to be signed and archived.
+func EvidenceDocument(txs []fluree.TransactionRequest) map[string]any {
+	graph := []map[string]any{}
+	for _, tx := range txs {
+		graph = append(graph, tx.Insert...)
+	}
+	return map[string]any{"@context": jsonldContext(), "@graph": graph}
+}
+
+// DatasetDigest returns the hex SHA-256 digest of the URDNA2015 canonical
+// form of a JSON-LD document.
+func DatasetDigest(doc any) (string, error) {
+	ctx, err := rdf.ParseJSONLDContext(nil)
+	if err != nil {
+		return "", err
+	}
+	triples, err := rdf.FromJSONLD(doc, ctx, rdf.JSONLDOptions{})
+	if err != nil {
+		return "", fmt.Errorf("hedera: expand dataset: %w", err)
+	}
+	sum := sha256.Sum256([]byte(rdf.Canonicalize(triples)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SignDataset signs doc with the operator's private key.
+func SignDataset(doc any, accountID, privateKey string, now time.Time) (*DatasetSignature, error) {
+	if strings.TrimSpace(accountID) == "" || strings.TrimSpace(privateKey) == "" {
+		return nil, fmt.Errorf("hedera: signing requires the operator account id and private key")
+	}
+	key, err := sdk.PrivateKeyFromString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hedera: parse operator private key: %w", err)
+	}
+	digest, err := DatasetDigest(doc)
+	if err != nil {
+		return nil, err
+	}
+	message, _ := hex.DecodeString(digest)
+	public := key.PublicKey()
+	return &DatasetSignature{
+		Canonicalization: CanonicalizationURDNA2015,
+		Digest:           digest,
+		AccountID:        accountID,
+		KeyType:          keyType(public),
+		PublicKey:        public.StringRaw(),
+		Signature:        hex.EncodeToString(key.Sign(message)),
+		Created:          now.UTC(),
+	}, nil
+}
+
+// VerifyDataset checks sig against doc and publicKey, normally the current
+// key of sig.AccountID as reported by the mirror node. Raw and DER-encoded
+// keys are accepted.
+func VerifyDataset(doc any, sig DatasetSignature, publicKey string) error {
+	if sig.Canonicalization != CanonicalizationURDNA2015 {
+		return fmt.Errorf("hedera: unsupported canonicalization %q", sig.Canonicalization)
+	}
+	digest, err := DatasetDigest(doc)
+	if err != nil {
+		return err
+	}
+	if digest != sig.Digest {
+		return fmt.Errorf("hedera: dataset digest %s does not match the signed digest %s", digest, sig.Digest)
+	}
+	key, err := sdk.PublicKeyFromString(normalizeKey(publicKey))
+	if err != nil {
+		return fmt.Errorf("hedera: parse public key of %s: %w", sig.AccountID, err)
+	}
+	signature, err := hex.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("hedera: decode signature: %w", err)
+	}
+	message, _ := hex.DecodeString(digest)
+	if keyType(key) == "ECDSA_SECP256K1" {
+		// The SDK hashes messages with Keccak-256 before signing with ECDSA
+		// keys but expects the hash when verifying.
+		hash := sha3.NewLegacyKeccak256()
+		hash.Write(message)
+		message = hash.Sum(nil)
+	}
+	if !key.Verify(message, signature) {
+		if sig.PublicKey != "" && normalizeKey(sig.PublicKey) != normalizeKey(publicKey) {
+			return fmt.Errorf("hedera: signature was made with key %s, but %s now has key %s", sig.PublicKey, sig.AccountID, key.StringRaw())
+		}
+		return fmt.Errorf("hedera: signature does not verify against the key of %s", sig.AccountID)
+	}
+	return nil
+}
+
+func keyType(key sdk.PublicKey) string {
+	if len(key.BytesRaw()) == 32 {
+		return "ED25519"
+	}
+	return "ECDSA_SECP256K1"
+}
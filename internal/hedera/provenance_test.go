@@ -0,0 +1,65 @@
+package hedera
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/hashgraph/hedera-sdk-go/v2"
+)
+
+func TestSignAndVerifyDataset(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	result := BootstrapResult{
+		Network:  "testnet",
+		Accounts: []AccountRecord{{Alias: "treasury", AccountID: "0.0.1001", Tags: []string{"ops"}, CreatedAt: now}},
+		Tokens:   []TokenRecord{{TokenID: "0.0.3001", Name: "Demo", TreasuryAccountID: "0.0.1001", Decimals: 2, CreatedAt: now}},
+	}
+	doc := EvidenceDocument(result.UpsertTransactions("tenant/dataset"))
+
+	// Reading the document back from disk must not change its digest.
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var stored map[string]any
+	if err := decoder.Decode(&stored); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	ed25519, err := sdk.PrivateKeyGenerateEd25519()
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	ecdsa, err := sdk.PrivateKeyGenerateEcdsa()
+	if err != nil {
+		t.Fatalf("generate ecdsa key: %v", err)
+	}
+	for _, key := range []sdk.PrivateKey{ed25519, ecdsa} {
+		sig, err := SignDataset(doc, "0.0.2", key.String(), now)
+		if err != nil {
+			t.Fatalf("SignDataset returned error: %v", err)
+		}
+		if err := VerifyDataset(stored, *sig, key.PublicKey().StringDer()); err != nil {
+			t.Fatalf("%s: VerifyDataset returned error: %v", sig.KeyType, err)
+		}
+	}
+
+	sig, err := SignDataset(doc, "0.0.2", ed25519.String(), now)
+	if err != nil {
+		t.Fatalf("SignDataset returned error: %v", err)
+	}
+	if err := VerifyDataset(stored, *sig, ecdsa.PublicKey().StringRaw()); err == nil || !strings.Contains(err.Error(), "now has key") {
+		t.Fatalf("expected a rotated key to be reported, got %v", err)
+	}
+	stored["@graph"].([]any)[0].(map[string]any)["schema:name"] = "tampered"
+	if err := VerifyDataset(stored, *sig, ed25519.PublicKey().StringRaw()); err == nil || !strings.Contains(err.Error(), "digest") {
+		t.Fatalf("expected a digest mismatch, got %v", err)
+	}
+}
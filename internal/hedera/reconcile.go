@@ -0,0 +1,336 @@
+package hedera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+)
+
+const hederaNamespace = "https://hashgraph.github.io/bhash/hedera#"
+
+// LedgerStateQuery selects every property of the accounts, topics and tokens
+// exported to a ledger. Pass its results to LedgerState.
+const LedgerStateQuery = `PREFIX hedera: <` + hederaNamespace + `>
+SELECT ?subject ?type ?property ?value WHERE {
+  VALUES ?type { hedera:Account hedera:ConsensusTopic hedera:Token }
+  ?subject a ?type ;
+    ?property ?value .
+}`
+
+// LedgerState rebuilds the records exported to a ledger from the results of
+// LedgerStateQuery. Records are sorted by identifier and Network is taken
+// from hedera:belongsToNetwork.
+func LedgerState(results *fluree.SPARQLResults) (BootstrapResult, error) {
+	accounts := make(map[string]*AccountRecord)
+	topics := make(map[string]*TopicRecord)
+	tokens := make(map[string]*TokenRecord)
+	var result BootstrapResult
+	for _, row := range results.Results.Bindings {
+		subject := row["subject"].Value
+		property := compactProperty(row["property"].Value)
+		value := row["value"].Value
+		if property == "hedera:belongsToNetwork" {
+			if result.Network == "" || value < result.Network {
+				result.Network = value
+			}
+			continue
+		}
+		var err error
+		switch row["type"].Value {
+		case hederaNamespace + "Account":
+			record, ok := accounts[subject]
+			if !ok {
+				record = &AccountRecord{AccountID: strings.TrimPrefix(subject, "urn:hedera:account:")}
+				accounts[subject] = record
+			}
+			err = record.set(property, value)
+		case hederaNamespace + "ConsensusTopic":
+			record, ok := topics[subject]
+			if !ok {
+				record = &TopicRecord{TopicID: strings.TrimPrefix(subject, "urn:hedera:topic:")}
+				topics[subject] = record
+			}
+			err = record.set(property, value)
+		case hederaNamespace + "Token":
+			record, ok := tokens[subject]
+			if !ok {
+				record = &TokenRecord{TokenID: strings.TrimPrefix(subject, "urn:hedera:token:")}
+				tokens[subject] = record
+			}
+			err = record.set(property, value)
+		}
+		if err != nil {
+			return BootstrapResult{}, fmt.Errorf("hedera: %s %s: %w", subject, property, err)
+		}
+	}
+	for _, record := range accounts {
+		sort.Strings(record.Tags)
+		result.Accounts = append(result.Accounts, *record)
+	}
+	for _, record := range topics {
+		sort.Strings(record.Tags)
+		result.Topics = append(result.Topics, *record)
+	}
+	for _, record := range tokens {
+		sort.Strings(record.Tags)
+		result.Tokens = append(result.Tokens, *record)
+	}
+	sort.Slice(result.Accounts, func(i, j int) bool { return result.Accounts[i].AccountID < result.Accounts[j].AccountID })
+	sort.Slice(result.Topics, func(i, j int) bool { return result.Topics[i].TopicID < result.Topics[j].TopicID })
+	sort.Slice(result.Tokens, func(i, j int) bool { return result.Tokens[i].TokenID < result.Tokens[j].TokenID })
+	return result, nil
+}
+
+// compactProperty abbreviates an IRI with the export context prefixes.
+func compactProperty(iri string) string {
+	for _, prefix := range []string{"hedera", "prov", "schema"} {
+		if ns := defaultContext[prefix].(string); strings.HasPrefix(iri, ns) {
+			return prefix + ":" + strings.TrimPrefix(iri, ns)
+		}
+	}
+	return iri
+}
+
+func (a *AccountRecord) set(property, value string) error {
+	switch property {
+	case "hedera:accountId":
+		a.AccountID = value
+	case "prov:generatedAtTime":
+		return parseTime(value, &a.CreatedAt)
+	case "schema:name":
+		a.Alias = value
+	case "schema:description":
+		a.Memo = value
+	case "hedera:publicKey":
+		a.PublicKey = value
+	case "schema:keywords":
+		a.Tags = append(a.Tags, value)
+	case "hedera:deleted":
+		a.Deleted = value == "true"
+	}
+	return nil
+}
+
+func (t *TopicRecord) set(property, value string) error {
+	switch property {
+	case "hedera:topicId":
+		t.TopicID = value
+	case "prov:generatedAtTime":
+		return parseTime(value, &t.CreatedAt)
+	case "schema:description":
+		t.Memo = value
+	case "schema:keywords":
+		t.Tags = append(t.Tags, value)
+	case "hedera:initialSequence":
+		return parseUint(value, &t.Sequence)
+	case "hedera:deleted":
+		t.Deleted = value == "true"
+	}
+	return nil
+}
+
+func (t *TokenRecord) set(property, value string) error {
+	switch property {
+	case "hedera:tokenId":
+		t.TokenID = value
+	case "schema:name":
+		t.Name = value
+	case "schema:identifier":
+		t.Symbol = value
+	case "prov:generatedAtTime":
+		return parseTime(value, &t.CreatedAt)
+	case "schema:description":
+		t.Memo = value
+	case "hedera:treasuryAccount":
+		t.TreasuryAccountID = strings.TrimPrefix(value, "urn:hedera:account:")
+	case "hedera:decimals":
+		var n uint64
+		if err := parseUint(value, &n); err != nil {
+			return err
+		}
+		t.Decimals = uint(n)
+	case "hedera:initialSupply":
+		return parseUint(value, &t.InitialSupply)
+	case "hedera:totalSupply":
+		return parseUint(value, &t.TotalSupply)
+	case "hedera:maxSupply":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		t.MaxSupply = n
+	case "hedera:supplyType":
+		t.SupplyType = value
+	case "hedera:tokenType":
+		t.TokenType = value
+	case "schema:keywords":
+		t.Tags = append(t.Tags, value)
+	case "hedera:deleted":
+		t.Deleted = value == "true"
+	}
+	return nil
+}
+
+func parseTime(value string, out *time.Time) error {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("invalid dateTime %q", value)
+	}
+	*out = parsed.UTC()
+	return nil
+}
+
+func parseUint(value string, out *uint64) error {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", value)
+	}
+	*out = n
+	return nil
+}
+
+// Drift is one field whose value in the ledger differs from the network.
+type Drift struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Field   string `json:"field"`
+	Ledger  any    `json:"ledger"`
+	Network any    `json:"network"`
+}
+
+// ReconcileReport lists the drift between a ledger and the network.
+// Corrections holds the drifted records with the network values applied;
+// its UpsertTransactions bring the ledger back in line.
+type ReconcileReport struct {
+	Network     string          `json:"network"`
+	Checked     int             `json:"checked"`
+	Drift       []Drift         `json:"drift"`
+	Missing     []string        `json:"missing,omitempty"`
+	Corrections BootstrapResult `json:"-"`
+}
+
+// Reconcile compares the records read from a ledger with their current state
+// on the network. Artefacts the mirror node does not know are listed in
+// Missing and left out of the corrections. Creation times, aliases and tags
+// are ledger-only metadata and are not compared.
+func Reconcile(ctx context.Context, ledger BootstrapResult, mirror MirrorState) (*ReconcileReport, error) {
+	report := &ReconcileReport{Network: ledger.Network, Drift: []Drift{}}
+	report.Corrections.Network = ledger.Network
+
+	for _, stored := range ledger.Accounts {
+		report.Checked++
+		live, err := mirror.Account(ctx, stored.AccountID)
+		if errors.Is(err, ErrNotFound) {
+			report.Missing = append(report.Missing, "account "+stored.AccountID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		corrected := stored
+		drift := report.compare("account", stored.AccountID,
+			field{"memo", stored.Memo, live.Memo, func() { corrected.Memo = live.Memo }},
+			field{"publicKey", normalizeKey(stored.PublicKey), normalizeKey(live.PublicKey), func() { corrected.PublicKey = live.PublicKey }},
+			field{"deleted", stored.Deleted, live.Deleted, func() { corrected.Deleted = live.Deleted }},
+		)
+		if drift {
+			report.Corrections.Accounts = append(report.Corrections.Accounts, corrected)
+		}
+	}
+	for _, stored := range ledger.Topics {
+		report.Checked++
+		live, err := mirror.Topic(ctx, stored.TopicID)
+		if errors.Is(err, ErrNotFound) {
+			report.Missing = append(report.Missing, "topic "+stored.TopicID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		corrected := stored
+		drift := report.compare("topic", stored.TopicID,
+			field{"memo", stored.Memo, live.Memo, func() { corrected.Memo = live.Memo }},
+			field{"deleted", stored.Deleted, live.Deleted, func() { corrected.Deleted = live.Deleted }},
+		)
+		if drift {
+			report.Corrections.Topics = append(report.Corrections.Topics, corrected)
+		}
+	}
+	for _, stored := range ledger.Tokens {
+		report.Checked++
+		live, err := mirror.Token(ctx, stored.TokenID)
+		if errors.Is(err, ErrNotFound) {
+			report.Missing = append(report.Missing, "token "+stored.TokenID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		corrected := stored
+		drift := report.compare("token", stored.TokenID,
+			field{"name", stored.Name, live.Name, func() { corrected.Name = live.Name }},
+			field{"symbol", stored.Symbol, live.Symbol, func() { corrected.Symbol = live.Symbol }},
+			field{"memo", stored.Memo, live.Memo, func() { corrected.Memo = live.Memo }},
+			field{"treasuryAccountId", stored.TreasuryAccountID, live.TreasuryAccountID, func() { corrected.TreasuryAccountID = live.TreasuryAccountID }},
+			field{"decimals", stored.Decimals, live.Decimals, func() { corrected.Decimals = live.Decimals }},
+			field{"totalSupply", stored.TotalSupply, live.TotalSupply, func() { corrected.TotalSupply = live.TotalSupply }},
+			field{"maxSupply", stored.MaxSupply, live.MaxSupply, func() { corrected.MaxSupply = live.MaxSupply }},
+			field{"supplyType", normalizeEnum(stored.SupplyType, "TOKEN_SUPPLY_TYPE_", "INFINITE"), normalizeEnum(live.SupplyType, "TOKEN_SUPPLY_TYPE_", "INFINITE"), func() { corrected.SupplyType = live.SupplyType }},
+			field{"tokenType", normalizeEnum(stored.TokenType, "TOKEN_TYPE_", "FUNGIBLE_COMMON"), normalizeEnum(live.TokenType, "TOKEN_TYPE_", "FUNGIBLE_COMMON"), func() { corrected.TokenType = live.TokenType }},
+			field{"deleted", stored.Deleted, live.Deleted, func() { corrected.Deleted = live.Deleted }},
+		)
+		if drift {
+			report.Corrections.Tokens = append(report.Corrections.Tokens, corrected)
+		}
+	}
+	return report, nil
+}
+
+// field pairs the ledger and network values of one property; apply copies
+// the network value into the correction.
+type field struct {
+	name            string
+	ledger, network any
+	apply           func()
+}
+
+func (r *ReconcileReport) compare(kind, id string, fields ...field) bool {
+	drift := false
+	for _, f := range fields {
+		if f.ledger == f.network {
+			continue
+		}
+		drift = true
+		f.apply()
+		r.Drift = append(r.Drift, Drift{Kind: kind, ID: id, Field: f.name, Ledger: f.ledger, Network: f.network})
+	}
+	return drift
+}
+
+// DER prefixes of ED25519 and ECDSA(secp256k1) public keys. The mirror node
+// reports raw keys while specs usually carry the DER encoding.
+var derKeyPrefixes = []string{"302a300506032b6570032100", "302d300706052b8104000a032200"}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(key), "0x"))
+	for _, prefix := range derKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return strings.TrimPrefix(key, prefix)
+		}
+	}
+	return key
+}
+
+func normalizeEnum(value, prefix, fallback string) string {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), prefix)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
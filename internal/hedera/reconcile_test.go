@@ -0,0 +1,113 @@
+package hedera
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/flureelocal"
+)
+
+type fakeMirror struct {
+	accounts map[string]AccountRecord
+	topics   map[string]TopicRecord
+	tokens   map[string]TokenRecord
+}
+
+func lookup[T any](records map[string]T, kind, id string) (T, error) {
+	record, ok := records[id]
+	if !ok {
+		return record, fmt.Errorf("%s %s: %w", kind, id, ErrNotFound)
+	}
+	return record, nil
+}
+
+func (m fakeMirror) Account(_ context.Context, id string) (AccountRecord, error) {
+	return lookup(m.accounts, "account", id)
+}
+
+func (m fakeMirror) Topic(_ context.Context, id string) (TopicRecord, error) {
+	return lookup(m.topics, "topic", id)
+}
+
+func (m fakeMirror) Token(_ context.Context, id string) (TokenRecord, error) {
+	return lookup(m.tokens, "token", id)
+}
+
+func TestReconcileReportsAndCorrectsDrift(t *testing.T) {
+	t.Parallel()
+
+	server, err := flureelocal.New(flureelocal.Options{})
+	if err != nil {
+		t.Fatalf("flureelocal.New returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client := fluree.NewClient(fluree.Config{APIToken: "local", TenantHandle: "bhash", BaseURL: httpServer.URL}, httpServer.Client(), fluree.WithRetryPolicy(fluree.NoRetry()))
+	ctx := context.Background()
+
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	exported := BootstrapResult{
+		Network:  "testnet",
+		Accounts: []AccountRecord{{Alias: "treasury", AccountID: "0.0.1001", Memo: "Treasury", PublicKey: "302a300506032b6570032100ABCD", Tags: []string{"ops"}, CreatedAt: now}},
+		Topics:   []TopicRecord{{TopicID: "0.0.2001", Memo: "Consensus", CreatedAt: now}, {TopicID: "0.0.2002", CreatedAt: now}},
+		Tokens:   []TokenRecord{{TokenID: "0.0.3001", Name: "Demo", Symbol: "DEM", TreasuryAccountID: "0.0.1001", Decimals: 2, InitialSupply: 1000, TotalSupply: 1000, SupplyType: "INFINITE", CreatedAt: now}},
+	}
+	if _, err := client.Transact(ctx, exported.Transaction("bhash/hedera")); err != nil {
+		t.Fatalf("Transact returned error: %v", err)
+	}
+	readLedger := func() BootstrapResult {
+		t.Helper()
+		results, err := client.QuerySPARQL(ctx, "bhash/hedera", LedgerStateQuery)
+		if err != nil {
+			t.Fatalf("QuerySPARQL returned error: %v", err)
+		}
+		state, err := LedgerState(results)
+		if err != nil {
+			t.Fatalf("LedgerState returned error: %v", err)
+		}
+		return state
+	}
+	// Topic and token aliases are not exported, so the fixture leaves them out.
+	if state := readLedger(); !reflect.DeepEqual(state, exported) {
+		t.Fatalf("ledger state does not round-trip:\n%+v\nwant:\n%+v", state, exported)
+	}
+
+	mirror := fakeMirror{
+		accounts: map[string]AccountRecord{"0.0.1001": {AccountID: "0.0.1001", Memo: "Treasury", PublicKey: "abcd"}},
+		topics:   map[string]TopicRecord{"0.0.2001": {TopicID: "0.0.2001", Memo: "Renamed", Deleted: true}},
+		tokens:   map[string]TokenRecord{"0.0.3001": {TokenID: "0.0.3001", Name: "Demo", Symbol: "DEM", TreasuryAccountID: "0.0.1001", Decimals: 2, InitialSupply: 1000, TotalSupply: 1500, SupplyType: "INFINITE", TokenType: "FUNGIBLE_COMMON"}},
+	}
+	report, err := Reconcile(ctx, readLedger(), mirror)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	want := []Drift{
+		{Kind: "topic", ID: "0.0.2001", Field: "memo", Ledger: "Consensus", Network: "Renamed"},
+		{Kind: "topic", ID: "0.0.2001", Field: "deleted", Ledger: false, Network: true},
+		{Kind: "token", ID: "0.0.3001", Field: "totalSupply", Ledger: uint64(1000), Network: uint64(1500)},
+	}
+	if report.Checked != 4 || !reflect.DeepEqual(report.Drift, want) || !reflect.DeepEqual(report.Missing, []string{"topic 0.0.2002"}) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Corrections.Accounts) != 0 || len(report.Corrections.Topics) != 1 || report.Corrections.Topics[0].Memo != "Renamed" {
+		t.Fatalf("unexpected corrections: %+v", report.Corrections)
+	}
+
+	for _, tx := range report.Corrections.UpsertTransactions("bhash/hedera") {
+		if _, err := client.Transact(ctx, tx); err != nil {
+			t.Fatalf("correcting Transact returned error: %v", err)
+		}
+	}
+	report, err = Reconcile(ctx, readLedger(), mirror)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if len(report.Drift) != 0 {
+		t.Fatalf("expected no drift after correction, got %+v", report.Drift)
+	}
+}
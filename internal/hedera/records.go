@@ -9,6 +9,7 @@ type AccountRecord struct {
 	PublicKey string    `json:"publicKey"`
 	Memo      string    `json:"memo"`
 	Tags      []string  `json:"tags"`
+	Deleted   bool      `json:"deleted,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
@@ -19,9 +20,19 @@ type TopicRecord struct {
 	Memo      string    `json:"memo"`
 	Sequence  uint64    `json:"sequence"`
 	Tags      []string  `json:"tags"`
+	Deleted   bool      `json:"deleted,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// TopicMessageRecord identifies a message submitted to a consensus topic.
+type TopicMessageRecord struct {
+	TopicID            string    `json:"topicId"`
+	SequenceNumber     uint64    `json:"sequenceNumber"`
+	ConsensusTimestamp time.Time `json:"consensusTimestamp"`
+	TransactionID      string    `json:"transactionId,omitempty"`
+	Message            []byte    `json:"-"`
+}
+
 // TokenRecord captures metadata about a token.
 type TokenRecord struct {
 	Alias             string    `json:"alias"`
@@ -32,10 +43,12 @@ type TokenRecord struct {
 	TreasuryAccountID string    `json:"treasuryAccountId"`
 	Decimals          uint      `json:"decimals"`
 	InitialSupply     uint64    `json:"initialSupply"`
+	TotalSupply       uint64    `json:"totalSupply,omitempty"`
 	MaxSupply         int64     `json:"maxSupply"`
 	SupplyType        string    `json:"supplyType"`
 	TokenType         string    `json:"tokenType"`
 	Tags              []string  `json:"tags"`
+	Deleted           bool      `json:"deleted,omitempty"`
 	CreatedAt         time.Time `json:"createdAt"`
 }
 
@@ -231,6 +231,7 @@ func (s *SDKNetwork) CreateToken(ctx context.Context, spec TokenSpec) (TokenReco
 		TreasuryAccountID: spec.TreasuryAccountID,
 		Decimals:          spec.Decimals,
 		InitialSupply:     spec.InitialSupply,
+		TotalSupply:       spec.InitialSupply,
 		MaxSupply:         spec.MaxSupply,
 		SupplyType:        spec.SupplyType,
 		TokenType:         spec.TokenType,
@@ -239,6 +240,35 @@ func (s *SDKNetwork) CreateToken(ctx context.Context, spec TokenSpec) (TokenReco
 	}, nil
 }
 
+func (s *SDKNetwork) SubmitMessage(ctx context.Context, topicID string, message []byte) (TopicMessageRecord, error) {
+	topic, err := sdk.TopicIDFromString(topicID)
+	if err != nil {
+		return TopicMessageRecord{}, fmt.Errorf("parse topic id: %w", err)
+	}
+	resp, err := sdk.NewTopicMessageSubmitTransaction().
+		SetTopicID(topic).
+		SetMessage(message).
+		Execute(s.client)
+	if err != nil {
+		return TopicMessageRecord{}, fmt.Errorf("execute topic message submit: %w", err)
+	}
+	receipt, err := resp.GetReceipt(s.client)
+	if err != nil {
+		return TopicMessageRecord{}, fmt.Errorf("fetch topic message receipt: %w", err)
+	}
+	record, err := resp.GetRecord(s.client)
+	if err != nil {
+		return TopicMessageRecord{}, fmt.Errorf("fetch topic message record: %w", err)
+	}
+	return TopicMessageRecord{
+		TopicID:            topic.String(),
+		SequenceNumber:     receipt.TopicSequenceNumber,
+		ConsensusTimestamp: record.ConsensusTimestamp.UTC(),
+		TransactionID:      resp.TransactionID.String(),
+		Message:            append([]byte(nil), message...),
+	}, nil
+}
+
 func parseTokenType(value string) (sdk.TokenType, error) {
 	switch strings.ToUpper(strings.TrimSpace(value)) {
 	case "", "FUNGIBLE_COMMON", "TOKEN_TYPE_FUNGIBLE_COMMON":
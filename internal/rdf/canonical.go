@@ -0,0 +1,270 @@
+package rdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize returns the URDNA2015 canonical N-Quads serialisation of a
+// default-graph dataset: blank nodes are relabelled _:c14n0, _:c14n1, … so
+// that isomorphic graphs serialise identically, and the lines are sorted.
+// Duplicate triples are dropped.
+func Canonicalize(triples []Triple) string {
+	c := &canonicalizer{
+		quads:     dedupe(triples),
+		bnodes:    make(map[string][]int),
+		canonical: newIssuer("c14n"),
+	}
+	for i, triple := range c.quads {
+		for _, term := range []Term{triple.Subject, triple.Object} {
+			if term.IsBlankNode() {
+				if list := c.bnodes[term.Value]; len(list) == 0 || list[len(list)-1] != i {
+					c.bnodes[term.Value] = append(list, i)
+				}
+			}
+		}
+	}
+
+	// Blank nodes with a unique first-degree hash are labelled in hash order.
+	byHash := make(map[string][]string)
+	for label := range c.bnodes {
+		hash := c.hashFirstDegree(label)
+		byHash[hash] = append(byHash[hash], label)
+	}
+	var shared []string
+	for _, hash := range sortedKeys(byHash) {
+		if labels := byHash[hash]; len(labels) == 1 {
+			c.canonical.issue(labels[0])
+		} else {
+			shared = append(shared, hash)
+		}
+	}
+
+	// The rest are distinguished by the hashes of their neighbourhoods.
+	for _, hash := range shared {
+		var results []nDegreeResult
+		labels := byHash[hash]
+		sort.Strings(labels)
+		for _, label := range labels {
+			if c.canonical.has(label) {
+				continue
+			}
+			issuer := newIssuer("b")
+			issuer.issue(label)
+			results = append(results, c.hashNDegree(label, issuer))
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].hash < results[j].hash })
+		for _, result := range results {
+			for _, label := range result.issuer.order {
+				c.canonical.issue(label)
+			}
+		}
+	}
+
+	lines := make([]string, len(c.quads))
+	for i, triple := range c.quads {
+		lines[i] = c.relabel(triple).String() + "\n"
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
+
+type canonicalizer struct {
+	quads     []Triple
+	bnodes    map[string][]int
+	canonical *idIssuer
+}
+
+type nDegreeResult struct {
+	hash   string
+	issuer *idIssuer
+}
+
+// idIssuer hands out sequential identifiers and remembers the order in which
+// they were issued.
+type idIssuer struct {
+	prefix string
+	ids    map[string]string
+	order  []string
+}
+
+func newIssuer(prefix string) *idIssuer {
+	return &idIssuer{prefix: prefix, ids: make(map[string]string)}
+}
+
+func (i *idIssuer) issue(label string) string {
+	if id, ok := i.ids[label]; ok {
+		return id
+	}
+	id := "_:" + i.prefix + strconv.Itoa(len(i.order))
+	i.ids[label] = id
+	i.order = append(i.order, label)
+	return id
+}
+
+func (i *idIssuer) has(label string) bool {
+	_, ok := i.ids[label]
+	return ok
+}
+
+func (i *idIssuer) clone() *idIssuer {
+	out := &idIssuer{prefix: i.prefix, ids: make(map[string]string, len(i.ids)), order: append([]string(nil), i.order...)}
+	for label, id := range i.ids {
+		out.ids[label] = id
+	}
+	return out
+}
+
+// hashFirstDegree hashes the quads mentioning label, with label written as
+// _:a and every other blank node as _:z.
+func (c *canonicalizer) hashFirstDegree(label string) string {
+	mask := func(term Term) Term {
+		if !term.IsBlankNode() {
+			return term
+		}
+		if term.Value == label {
+			return BlankNode("a")
+		}
+		return BlankNode("z")
+	}
+	var lines []string
+	for _, i := range c.bnodes[label] {
+		triple := c.quads[i]
+		lines = append(lines, Triple{Subject: mask(triple.Subject), Predicate: triple.Predicate, Object: mask(triple.Object)}.String()+"\n")
+	}
+	sort.Strings(lines)
+	return hashString(strings.Join(lines, ""))
+}
+
+func (c *canonicalizer) hashRelated(related string, triple Triple, issuer *idIssuer, position string) string {
+	var id string
+	switch {
+	case c.canonical.has(related):
+		id = c.canonical.ids[related]
+	case issuer.has(related):
+		id = issuer.ids[related]
+	default:
+		id = c.hashFirstDegree(related)
+	}
+	return hashString(position + triple.Predicate.String() + id)
+}
+
+// hashNDegree implements the Hash N-Degree Quads algorithm: it hashes the
+// related blank nodes of label, choosing the lexicographically smallest path
+// over all orderings of nodes that share a related hash.
+func (c *canonicalizer) hashNDegree(label string, issuer *idIssuer) nDegreeResult {
+	related := make(map[string][]string)
+	for _, i := range c.bnodes[label] {
+		triple := c.quads[i]
+		if triple.Subject.IsBlankNode() && triple.Subject.Value != label {
+			hash := c.hashRelated(triple.Subject.Value, triple, issuer, "s")
+			related[hash] = append(related[hash], triple.Subject.Value)
+		}
+		if triple.Object.IsBlankNode() && triple.Object.Value != label {
+			hash := c.hashRelated(triple.Object.Value, triple, issuer, "o")
+			related[hash] = append(related[hash], triple.Object.Value)
+		}
+	}
+
+	var data strings.Builder
+	for _, hash := range sortedKeys(related) {
+		data.WriteString(hash)
+		var chosenPath string
+		var chosenIssuer *idIssuer
+		permute(related[hash], func(permutation []string) {
+			candidate := issuer.clone()
+			var path strings.Builder
+			var recursion []string
+			worse := func() bool {
+				return chosenIssuer != nil && path.Len() >= len(chosenPath) && path.String() > chosenPath
+			}
+			for _, node := range permutation {
+				if c.canonical.has(node) {
+					path.WriteString(c.canonical.ids[node])
+				} else {
+					if !candidate.has(node) {
+						recursion = append(recursion, node)
+					}
+					path.WriteString(candidate.issue(node))
+				}
+				if worse() {
+					return
+				}
+			}
+			for _, node := range recursion {
+				result := c.hashNDegree(node, candidate)
+				path.WriteString(candidate.issue(node))
+				path.WriteString("<" + result.hash + ">")
+				candidate = result.issuer
+				if worse() {
+					return
+				}
+			}
+			if chosenIssuer == nil || path.String() < chosenPath {
+				chosenPath = path.String()
+				chosenIssuer = candidate
+			}
+		})
+		data.WriteString(chosenPath)
+		issuer = chosenIssuer
+	}
+	return nDegreeResult{hash: hashString(data.String()), issuer: issuer}
+}
+
+func (c *canonicalizer) relabel(triple Triple) Triple {
+	rename := func(term Term) Term {
+		if term.IsBlankNode() {
+			return BlankNode(strings.TrimPrefix(c.canonical.ids[term.Value], "_:"))
+		}
+		return term
+	}
+	return Triple{Subject: rename(triple.Subject), Predicate: triple.Predicate, Object: rename(triple.Object)}
+}
+
+// permute calls fn with every ordering of items.
+func permute(items []string, fn func([]string)) {
+	items = append([]string(nil), items...)
+	sort.Strings(items)
+	var walk func(k int)
+	walk = func(k int) {
+		if k == len(items) {
+			fn(append([]string(nil), items...))
+			return
+		}
+		for i := k; i < len(items); i++ {
+			items[k], items[i] = items[i], items[k]
+			walk(k + 1)
+			items[k], items[i] = items[i], items[k]
+		}
+	}
+	walk(0)
+}
+
+func dedupe(triples []Triple) []Triple {
+	seen := make(map[Triple]bool, len(triples))
+	out := make([]Triple, 0, len(triples))
+	for _, triple := range triples {
+		if !seen[triple] {
+			seen[triple] = true
+			out = append(out, triple)
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hashString(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,466 @@
+package rdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONLDContext is a parsed JSON-LD context supporting the subset used by
+// the ontology exports and Fluree transactions: prefixes, @vocab, and term
+// definitions with @id, @type coercion and @container.
+type JSONLDContext struct {
+	Vocab string
+	terms map[string]termDefinition
+}
+
+type termDefinition struct {
+	id        string
+	typ       string
+	container string
+}
+
+// ParseJSONLDContext parses a context value (an object, an array of objects,
+// or nil). Remote context references are not supported.
+func ParseJSONLDContext(raw any) (*JSONLDContext, error) {
+	ctx := &JSONLDContext{terms: make(map[string]termDefinition)}
+	if err := ctx.merge(raw); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+func (c *JSONLDContext) merge(raw any) error {
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case []any:
+		for _, item := range v {
+			if err := c.merge(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Context:
+		for prefix, ns := range v {
+			c.terms[prefix] = termDefinition{id: ns}
+		}
+		return nil
+	case map[string]string:
+		return c.merge(Context(v))
+	case map[string]any:
+		if nested, ok := v["@context"]; ok && len(v) == 1 {
+			return c.merge(nested)
+		}
+		// Resolve plain prefix definitions first so term definitions can use
+		// them regardless of key order.
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			_, iString := v[keys[i]].(string)
+			_, jString := v[keys[j]].(string)
+			if iString != jString {
+				return iString
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			switch value := v[key].(type) {
+			case string:
+				if key == "@vocab" {
+					c.Vocab = c.expand(value, true)
+					continue
+				}
+				if strings.HasPrefix(key, "@") {
+					continue
+				}
+				c.terms[key] = termDefinition{id: c.expand(value, true)}
+			case map[string]any:
+				def := termDefinition{}
+				if id, ok := value["@id"].(string); ok {
+					def.id = c.expand(id, true)
+				}
+				if typ, ok := value["@type"].(string); ok {
+					if strings.HasPrefix(typ, "@") {
+						def.typ = typ
+					} else {
+						def.typ = c.expand(typ, true)
+					}
+				}
+				if container, ok := value["@container"].(string); ok {
+					def.container = container
+				}
+				c.terms[key] = def
+			case nil:
+				delete(c.terms, key)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("rdf: unsupported JSON-LD context %T", raw)
+	}
+}
+
+// expand resolves a term, compact IRI or absolute IRI. vocab selects
+// vocabulary-relative resolution (properties and types) rather than
+// document-relative resolution (node identifiers).
+func (c *JSONLDContext) expand(value string, vocab bool) string {
+	if def, ok := c.terms[value]; ok && def.id != "" && vocab {
+		return def.id
+	}
+	if prefix, local, ok := strings.Cut(value, ":"); ok {
+		if strings.HasPrefix(local, "//") || prefix == "_" {
+			return value
+		}
+		if def, ok := c.terms[prefix]; ok && def.id != "" {
+			return def.id + local
+		}
+		return value
+	}
+	if vocab && c.Vocab != "" {
+		return c.Vocab + value
+	}
+	return value
+}
+
+func (c *JSONLDContext) definition(key string) termDefinition {
+	return c.terms[key]
+}
+
+// JSONLDOptions configures FromJSONLD.
+type JSONLDOptions struct {
+	// BlankNodePrefix is prepended to every blank node label so documents
+	// loaded into the same store do not share blank nodes by accident.
+	BlankNodePrefix string
+	// Variables treats strings starting with "?" in @id positions and
+	// property values as pattern variables, as used in Fluree where clauses.
+	Variables bool
+}
+
+// FromJSONLD converts JSON-LD node objects to triples. doc may be a node
+// object, an array of node objects or an object with "@graph". Values decoded
+// with encoding/json (including json.Number) and plain Go strings, numbers,
+// booleans and slices are accepted.
+func FromJSONLD(doc any, ctx *JSONLDContext, opts JSONLDOptions) ([]Triple, error) {
+	if ctx == nil {
+		ctx = &JSONLDContext{terms: make(map[string]termDefinition)}
+	}
+	e := &jsonldExpander{ctx: ctx, opts: opts}
+	if err := e.document(normalizeJSON(doc)); err != nil {
+		return nil, err
+	}
+	return e.triples, nil
+}
+
+type jsonldExpander struct {
+	ctx     *JSONLDContext
+	opts    JSONLDOptions
+	triples []Triple
+	counter int
+}
+
+func (e *jsonldExpander) document(doc any) error {
+	switch v := doc.(type) {
+	case []any:
+		for _, item := range v {
+			if err := e.document(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		if graph, ok := v["@graph"]; ok {
+			if local, ok := v["@context"]; ok {
+				if err := e.ctx.merge(local); err != nil {
+					return err
+				}
+			}
+			return e.document(graph)
+		}
+		_, err := e.node(v)
+		return err
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("rdf: expected JSON-LD node object, got %T", doc)
+	}
+}
+
+// node emits the triples of a node object and returns its subject.
+func (e *jsonldExpander) node(obj map[string]any) (Term, error) {
+	if local, ok := obj["@context"]; ok {
+		if err := e.ctx.merge(local); err != nil {
+			return Term{}, err
+		}
+	}
+	subject := e.freshBlankNode()
+	if id, ok := obj["@id"]; ok {
+		s, ok := id.(string)
+		if !ok {
+			return Term{}, fmt.Errorf("rdf: @id must be a string, got %T", id)
+		}
+		subject = e.reference(s, false)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := obj[key]
+		switch key {
+		case "@id", "@context":
+			continue
+		case "@type":
+			for _, item := range asList(value) {
+				s, ok := item.(string)
+				if !ok {
+					return Term{}, fmt.Errorf("rdf: @type values must be strings, got %T", item)
+				}
+				e.emit(subject, IRI(RDFType), e.reference(s, true))
+			}
+			continue
+		case "@reverse", "@index", "@graph", "@included", "@nest":
+			return Term{}, fmt.Errorf("rdf: JSON-LD keyword %s is not supported", key)
+		}
+		if strings.HasPrefix(key, "@") {
+			continue
+		}
+		var predicate Term
+		if e.opts.Variables && strings.HasPrefix(key, "?") {
+			predicate = Variable(key[1:])
+		} else {
+			iri := e.ctx.expand(key, true)
+			if !strings.Contains(iri, ":") {
+				// Keys that do not expand to an IRI are dropped, as in JSON-LD.
+				continue
+			}
+			predicate = IRI(iri)
+		}
+		def := e.ctx.definition(key)
+		for _, item := range asList(value) {
+			object, err := e.value(item, def)
+			if err != nil {
+				return Term{}, fmt.Errorf("rdf: %s: %w", key, err)
+			}
+			if object == (Term{}) {
+				continue
+			}
+			e.emit(subject, predicate, object)
+		}
+	}
+	return subject, nil
+}
+
+func (e *jsonldExpander) value(item any, def termDefinition) (Term, error) {
+	switch v := item.(type) {
+	case nil:
+		return Term{}, nil
+	case map[string]any:
+		if raw, ok := v["@value"]; ok {
+			return e.valueObject(v, raw)
+		}
+		if list, ok := v["@list"]; ok {
+			return e.list(asList(list), def)
+		}
+		if set, ok := v["@set"]; ok {
+			// Sets are flattened by the caller only when they appear at the
+			// top level; nested sets keep their first element.
+			items := asList(set)
+			if len(items) == 0 {
+				return Term{}, nil
+			}
+			return e.value(items[0], def)
+		}
+		if id, ok := v["@id"].(string); ok && len(v) == 1 {
+			return e.reference(id, false), nil
+		}
+		return e.node(v)
+	case string:
+		if e.opts.Variables && strings.HasPrefix(v, "?") {
+			return Variable(v[1:]), nil
+		}
+		switch def.typ {
+		case "@id":
+			return e.reference(v, false), nil
+		case "@vocab":
+			return e.reference(v, true), nil
+		case "":
+			return Literal(v, ""), nil
+		default:
+			return Literal(v, def.typ), nil
+		}
+	case bool:
+		return e.coerce(strconv.FormatBool(v), XSDBoolean, def), nil
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return e.coerce(v.String(), XSDInteger, def), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return Term{}, err
+		}
+		return e.coerce(canonicalDouble(f), XSDDouble, def), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e18 {
+			return e.coerce(strconv.FormatInt(int64(v), 10), XSDInteger, def), nil
+		}
+		return e.coerce(canonicalDouble(v), XSDDouble, def), nil
+	case []any:
+		return e.list(v, def)
+	default:
+		return Term{}, fmt.Errorf("unsupported JSON-LD value %T", item)
+	}
+}
+
+// coerce applies a term definition's datatype to a native value.
+func (e *jsonldExpander) coerce(lexical, native string, def termDefinition) Term {
+	if def.typ != "" && !strings.HasPrefix(def.typ, "@") {
+		return Literal(lexical, def.typ)
+	}
+	return Literal(lexical, native)
+}
+
+func (e *jsonldExpander) valueObject(obj map[string]any, raw any) (Term, error) {
+	var lexical string
+	native := XSDString
+	switch v := raw.(type) {
+	case string:
+		lexical = v
+	case bool:
+		lexical, native = strconv.FormatBool(v), XSDBoolean
+	case json.Number:
+		lexical, native = v.String(), XSDInteger
+		if _, err := strconv.ParseInt(lexical, 10, 64); err != nil {
+			native = XSDDouble
+		}
+	case float64:
+		if v == math.Trunc(v) {
+			lexical, native = strconv.FormatInt(int64(v), 10), XSDInteger
+		} else {
+			lexical, native = canonicalDouble(v), XSDDouble
+		}
+	default:
+		return Term{}, fmt.Errorf("unsupported @value %T", raw)
+	}
+	if lang, ok := obj["@language"].(string); ok && lang != "" {
+		return LangLiteral(lexical, lang), nil
+	}
+	if typ, ok := obj["@type"].(string); ok && typ != "" {
+		return Literal(lexical, e.ctx.expand(typ, true)), nil
+	}
+	return Literal(lexical, native), nil
+}
+
+func (e *jsonldExpander) list(items []any, def termDefinition) (Term, error) {
+	if len(items) == 0 {
+		return IRI(RDFNil), nil
+	}
+	head := e.freshBlankNode()
+	current := head
+	for i, item := range items {
+		value, err := e.value(item, def)
+		if err != nil {
+			return Term{}, err
+		}
+		e.emit(current, IRI(RDFFirst), value)
+		next := IRI(RDFNil)
+		if i < len(items)-1 {
+			next = e.freshBlankNode()
+		}
+		e.emit(current, IRI(RDFRest), next)
+		current = next
+	}
+	return head, nil
+}
+
+func (e *jsonldExpander) reference(value string, vocab bool) Term {
+	if e.opts.Variables && strings.HasPrefix(value, "?") {
+		return Variable(value[1:])
+	}
+	if label, ok := strings.CutPrefix(value, "_:"); ok {
+		return BlankNode(e.opts.BlankNodePrefix + label)
+	}
+	return IRI(e.ctx.expand(value, vocab))
+}
+
+func (e *jsonldExpander) freshBlankNode() Term {
+	e.counter++
+	return BlankNode(fmt.Sprintf("%sjld%d", e.opts.BlankNodePrefix, e.counter))
+}
+
+func (e *jsonldExpander) emit(subject, predicate, object Term) {
+	e.triples = append(e.triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+}
+
+// asList returns value as a slice, wrapping scalars and flattening @set.
+func asList(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		if set, ok := v["@set"]; ok && len(v) == 1 {
+			return asList(set)
+		}
+		return []any{v}
+	default:
+		return []any{v}
+	}
+}
+
+// normalizeJSON converts Go values such as []string, []map[string]any and
+// integer types into the shapes produced by encoding/json with UseNumber.
+func normalizeJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSON(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSON(item)
+		}
+		return out
+	case []string:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = item
+		}
+		return out
+	case int:
+		return json.Number(strconv.FormatInt(int64(v), 10))
+	case int32:
+		return json.Number(strconv.FormatInt(int64(v), 10))
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		return json.Number(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		return json.Number(strconv.FormatUint(v, 10))
+	case float32:
+		return float64(v)
+	default:
+		return value
+	}
+}
+
+func canonicalDouble(f float64) string {
+	return strconv.FormatFloat(f, 'E', -1, 64)
+}
@@ -0,0 +1,286 @@
+package rdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Context maps JSON-LD prefixes to namespace IRIs.
+type Context map[string]string
+
+// DefaultContext returns the shared prefixes used when exporting ontology
+// graphs to JSON-LD.
+func DefaultContext() Context {
+	return Context{
+		"hedera":  "https://bhash.dev/hedera/core/",
+		"rdf":     RDFNamespace,
+		"rdfs":    RDFSNamespace,
+		"owl":     OWLNamespace,
+		"xsd":     XSDNamespace,
+		"skos":    "http://www.w3.org/2004/02/skos/core#",
+		"dcterms": "http://purl.org/dc/terms/",
+		"prov":    "http://www.w3.org/ns/prov#",
+		"dcat":    "http://www.w3.org/ns/dcat#",
+		"sh":      "http://www.w3.org/ns/shacl#",
+		"schema":  "http://schema.org/",
+	}
+}
+
+// Clone returns a copy of the context.
+func (c Context) Clone() Context {
+	clone := make(Context, len(c))
+	for k, v := range c {
+		clone[k] = v
+	}
+	return clone
+}
+
+// AddPrefixes adds the prefixes that are not yet defined. The empty prefix is
+// skipped because JSON-LD cannot express it as a term definition, and prefixes
+// whose namespace is already mapped are ignored to keep compaction stable.
+func (c Context) AddPrefixes(prefixes map[string]string) {
+	namespaces := make(map[string]bool, len(c))
+	for _, ns := range c {
+		namespaces[ns] = true
+	}
+	keys := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		keys = append(keys, prefix)
+	}
+	sort.Strings(keys)
+	for _, prefix := range keys {
+		ns := prefixes[prefix]
+		if prefix == "" || namespaces[ns] {
+			continue
+		}
+		if _, ok := c[prefix]; ok {
+			continue
+		}
+		c[prefix] = ns
+		namespaces[ns] = true
+	}
+}
+
+// Object returns the context in the form expected under "@context".
+func (c Context) Object() map[string]any {
+	object := make(map[string]any, len(c))
+	for prefix, ns := range c {
+		object[prefix] = ns
+	}
+	return object
+}
+
+// CompactIRI returns iri as "prefix:local" using the longest matching
+// namespace, or iri unchanged when no prefix applies.
+func (c Context) CompactIRI(iri string) string {
+	best, bestNS := "", ""
+	for prefix, ns := range c {
+		if len(ns) > len(bestNS) && strings.HasPrefix(iri, ns) && len(iri) > len(ns) {
+			best, bestNS = prefix, ns
+		}
+	}
+	if bestNS == "" {
+		return iri
+	}
+	local := iri[len(bestNS):]
+	if strings.HasPrefix(local, "//") {
+		return iri
+	}
+	return best + ":" + local
+}
+
+// ExpandIRI reverses CompactIRI.
+func (c Context) ExpandIRI(value string) string {
+	prefix, local, ok := strings.Cut(value, ":")
+	if !ok || strings.HasPrefix(local, "//") {
+		return value
+	}
+	if ns, ok := c[prefix]; ok {
+		return ns + local
+	}
+	return value
+}
+
+// ToJSONLD converts triples into compacted JSON-LD node objects in
+// first-seen subject order. Blank nodes that are referenced exactly once are
+// embedded in their parent node and well-formed RDF collections become
+// "@list" values. Shared blank nodes, and blank nodes that only reference
+// each other in a cycle, are emitted as top-level "_:" nodes; blank node
+// labels are scoped to a transaction, so such a node and the nodes that
+// reference it must be transacted together.
+func ToJSONLD(triples []Triple, ctx Context) []map[string]any {
+	c := &jsonldConverter{
+		ctx:        ctx,
+		properties: make(map[Term][]Triple),
+		references: make(map[Term]int),
+		emitted:    make(map[Term]bool),
+	}
+	var order []Term
+	for _, triple := range triples {
+		if _, ok := c.properties[triple.Subject]; !ok {
+			order = append(order, triple.Subject)
+		}
+		c.properties[triple.Subject] = append(c.properties[triple.Subject], triple)
+		if triple.Object.IsBlankNode() {
+			c.references[triple.Object]++
+		}
+	}
+
+	nodes := make([]map[string]any, 0, len(order))
+	for _, subject := range order {
+		if c.embeddable(subject) {
+			continue
+		}
+		nodes = append(nodes, c.node(subject, map[Term]bool{}))
+	}
+	// Blank nodes that only reference each other in a cycle have no root to
+	// be embedded under; emit them as top-level nodes instead.
+	for _, subject := range order {
+		if c.emitted[subject] {
+			continue
+		}
+		node := c.node(subject, map[Term]bool{})
+		node["@id"] = "_:" + subject.Value
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+type jsonldConverter struct {
+	ctx        Context
+	properties map[Term][]Triple
+	references map[Term]int
+	emitted    map[Term]bool
+}
+
+func (c *jsonldConverter) embeddable(term Term) bool {
+	return term.IsBlankNode() && c.references[term] == 1
+}
+
+func (c *jsonldConverter) node(subject Term, visiting map[Term]bool) map[string]any {
+	visiting[subject] = true
+	c.emitted[subject] = true
+	defer delete(visiting, subject)
+
+	node := make(map[string]any)
+	if subject.IsBlankNode() {
+		if !c.embeddable(subject) {
+			node["@id"] = "_:" + subject.Value
+		}
+	} else {
+		node["@id"] = c.ctx.CompactIRI(subject.Value)
+	}
+
+	values := make(map[string][]any)
+	var keys []string
+	for _, triple := range c.properties[subject] {
+		var (
+			key   string
+			value any
+		)
+		if triple.Predicate.Value == RDFType && triple.Object.IsIRI() {
+			key = "@type"
+			value = c.ctx.CompactIRI(triple.Object.Value)
+		} else {
+			key = c.ctx.CompactIRI(triple.Predicate.Value)
+			value = c.value(triple.Object, visiting)
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], value)
+	}
+	for _, key := range keys {
+		if len(values[key]) == 1 && key != "@type" {
+			node[key] = values[key][0]
+		} else {
+			node[key] = values[key]
+		}
+	}
+	return node
+}
+
+func (c *jsonldConverter) value(term Term, visiting map[Term]bool) any {
+	switch term.Kind {
+	case KindIRI:
+		if term.Value == RDFNil {
+			return map[string]any{"@list": []any{}}
+		}
+		return map[string]any{"@id": c.ctx.CompactIRI(term.Value)}
+	case KindBlankNode:
+		if c.embeddable(term) && !visiting[term] {
+			if items, ok := c.list(term, visiting); ok {
+				return map[string]any{"@list": items}
+			}
+			return c.node(term, visiting)
+		}
+		return map[string]any{"@id": "_:" + term.Value}
+	default:
+		return literalValue(term, c.ctx)
+	}
+}
+
+// list returns the items of the collection starting at head when every cell
+// is an unshared blank node with exactly one rdf:first and rdf:rest.
+func (c *jsonldConverter) list(head Term, visiting map[Term]bool) ([]any, bool) {
+	var cells []Term
+	var items []Term
+	for current := head; ; {
+		props := c.properties[current]
+		if len(props) != 2 || !c.embeddable(current) && current != head {
+			return nil, false
+		}
+		var first, rest *Term
+		for i := range props {
+			switch props[i].Predicate.Value {
+			case RDFFirst:
+				first = &props[i].Object
+			case RDFRest:
+				rest = &props[i].Object
+			}
+		}
+		if first == nil || rest == nil {
+			return nil, false
+		}
+		cells = append(cells, current)
+		items = append(items, *first)
+		if rest.IsIRI() && rest.Value == RDFNil {
+			break
+		}
+		if !rest.IsBlankNode() || visiting[*rest] {
+			return nil, false
+		}
+		current = *rest
+	}
+	for _, cell := range cells {
+		visiting[cell] = true
+		c.emitted[cell] = true
+	}
+	defer func() {
+		for _, cell := range cells {
+			delete(visiting, cell)
+		}
+	}()
+	values := make([]any, 0, len(items))
+	for _, item := range items {
+		values = append(values, c.value(item, visiting))
+	}
+	return values, true
+}
+
+func literalValue(term Term, ctx Context) any {
+	switch {
+	case term.Lang != "":
+		return map[string]any{"@value": term.Value, "@language": term.Lang}
+	case term.Datatype == "" || term.Datatype == XSDString:
+		return term.Value
+	case term.Datatype == XSDBoolean && (term.Value == "true" || term.Value == "false"):
+		return term.Value == "true"
+	case term.Datatype == XSDInteger:
+		if n, err := strconv.ParseInt(term.Value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return map[string]any{"@value": term.Value, "@type": ctx.CompactIRI(term.Datatype)}
+}
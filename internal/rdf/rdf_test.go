@@ -0,0 +1,424 @@
+package rdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseTurtle(t *testing.T) {
+	t.Parallel()
+
+	src := `@prefix ex: <https://example.org/> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+
+ex:token a ex:Token ;
+    rdfs:label "Token"@en, """Multi
+line""" ;
+    ex:decimals 8 ;
+    ex:ratio 1.5 ;
+    ex:frozen false ;
+    ex:supply "100"^^xsd:integer ;
+    ex:owner [ a ex:Account ; ex:id "0.0.1001" ] ;
+    ex:keys ( ex:admin ex:kyc ) .
+`
+	graph, err := ParseTurtle(src, ParseOptions{Source: "inline.ttl"})
+	if err != nil {
+		t.Fatalf("ParseTurtle returned error: %v", err)
+	}
+	if graph.Prefixes["ex"] != "https://example.org/" || graph.Prefixes["xsd"] != XSDNamespace {
+		t.Fatalf("unexpected prefixes: %v", graph.Prefixes)
+	}
+
+	token := IRI("https://example.org/token")
+	if types := graph.Objects(token, RDFType); !reflect.DeepEqual(types, []Term{IRI("https://example.org/Token")}) {
+		t.Fatalf("unexpected types: %v", types)
+	}
+	labels := graph.Objects(token, RDFSNamespace+"label")
+	if len(labels) != 2 || labels[0] != LangLiteral("Token", "en") || labels[1] != Literal("Multi\nline", "") {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+	checks := map[string]Term{
+		"decimals": Literal("8", XSDInteger),
+		"ratio":    Literal("1.5", XSDDecimal),
+		"frozen":   Literal("false", XSDBoolean),
+		"supply":   Literal("100", XSDInteger),
+	}
+	for local, want := range checks {
+		got := graph.Objects(token, "https://example.org/"+local)
+		if len(got) != 1 || got[0] != want {
+			t.Fatalf("unexpected %s: %v", local, got)
+		}
+	}
+
+	owner := graph.Objects(token, "https://example.org/owner")
+	if len(owner) != 1 || !owner[0].IsBlankNode() {
+		t.Fatalf("expected blank node owner, got %v", owner)
+	}
+	if ids := graph.Objects(owner[0], "https://example.org/id"); len(ids) != 1 || ids[0].Value != "0.0.1001" {
+		t.Fatalf("unexpected owner id: %v", ids)
+	}
+
+	keys := graph.Objects(token, "https://example.org/keys")
+	if len(keys) != 1 || !keys[0].IsBlankNode() {
+		t.Fatalf("expected list head, got %v", keys)
+	}
+	if first := graph.Objects(keys[0], RDFFirst); len(first) != 1 || first[0] != IRI("https://example.org/admin") {
+		t.Fatalf("unexpected list head: %v", first)
+	}
+}
+
+func TestParseTurtleReportsPosition(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseTurtle("@prefix ex: <https://example.org/> .\nex:a ex:b missing:c .\n", ParseOptions{Source: "broken.ttl"})
+	if err == nil {
+		t.Fatalf("expected parse error")
+	}
+	if !strings.Contains(err.Error(), "broken.ttl:2:") || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToJSONLDCompactsAndEmbeds(t *testing.T) {
+	t.Parallel()
+
+	src := `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+
+hedera:Token a owl:Class ;
+    rdfs:label "Token"@en ;
+    rdfs:subClassOf [ a owl:Restriction ; owl:onProperty hedera:hasTreasury ] ;
+    owl:unionOf ( hedera:Fungible hedera:NonFungible ) ;
+    hedera:decimals 8 .
+`
+	graph, err := ParseTurtle(src, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtle returned error: %v", err)
+	}
+	nodes := ToJSONLD(graph.Triples, DefaultContext())
+	if len(nodes) != 1 {
+		t.Fatalf("expected one top-level node, got %d: %v", len(nodes), nodes)
+	}
+	want := map[string]any{
+		"@id":             "hedera:Token",
+		"@type":           []any{"owl:Class"},
+		"rdfs:label":      map[string]any{"@value": "Token", "@language": "en"},
+		"rdfs:subClassOf": map[string]any{"@type": []any{"owl:Restriction"}, "owl:onProperty": map[string]any{"@id": "hedera:hasTreasury"}},
+		"owl:unionOf":     map[string]any{"@list": []any{map[string]any{"@id": "hedera:Fungible"}, map[string]any{"@id": "hedera:NonFungible"}}},
+		"hedera:decimals": int64(8),
+	}
+	if !reflect.DeepEqual(nodes[0], want) {
+		t.Fatalf("unexpected node:\n got %#v\nwant %#v", nodes[0], want)
+	}
+}
+
+func TestContextAddPrefixes(t *testing.T) {
+	t.Parallel()
+
+	ctx := DefaultContext()
+	ctx.AddPrefixes(map[string]string{
+		"":       "https://example.org/base/",
+		"ex":     "https://example.org/",
+		"core":   "https://bhash.dev/hedera/core/",
+		"hedera": "https://example.org/other/",
+	})
+	if ctx["ex"] != "https://example.org/" {
+		t.Fatalf("expected ex prefix, got %v", ctx)
+	}
+	if _, ok := ctx[""]; ok {
+		t.Fatalf("empty prefix should be skipped")
+	}
+	if _, ok := ctx["core"]; ok {
+		t.Fatalf("duplicate namespace should be skipped")
+	}
+	if ctx["hedera"] != "https://bhash.dev/hedera/core/" {
+		t.Fatalf("existing prefix overwritten: %v", ctx["hedera"])
+	}
+	if got := ctx.CompactIRI("https://example.org/token"); got != "ex:token" {
+		t.Fatalf("unexpected compact IRI: %s", got)
+	}
+	if got := ctx.ExpandIRI("ex:token"); got != "https://example.org/token" {
+		t.Fatalf("unexpected expanded IRI: %s", got)
+	}
+}
+
+func TestFromJSONLDRoundTripsToJSONLD(t *testing.T) {
+	t.Parallel()
+
+	src := `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+
+hedera:Token a owl:Class ;
+    rdfs:label "Token"@en ;
+    owl:unionOf ( hedera:Fungible hedera:NonFungible ) ;
+    hedera:decimals 8 ;
+    hedera:ratio 1.5 ;
+    hedera:frozen false .
+`
+	graph, err := ParseTurtle(src, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtle returned error: %v", err)
+	}
+	doc := map[string]any{
+		"@context": DefaultContext().Object(),
+		"@graph":   ToJSONLD(graph.Triples, DefaultContext()),
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	triples, err := FromJSONLD(decoded, nil, JSONLDOptions{BlankNodePrefix: "rt"})
+	if err != nil {
+		t.Fatalf("FromJSONLD returned error: %v", err)
+	}
+	got, want := NewStore(), NewStore()
+	got.Add(triples...)
+	want.Add(graph.Triples...)
+	if got.Len() != want.Len() {
+		t.Fatalf("expected %d triples, got %d: %v", want.Len(), got.Len(), triples)
+	}
+	for _, triple := range want.Triples() {
+		if triple.Subject.IsBlankNode() || triple.Object.IsBlankNode() {
+			continue
+		}
+		if len(got.Match(triple.Subject, triple.Predicate, triple.Object)) != 1 {
+			t.Fatalf("missing triple %v in %v", triple, triples)
+		}
+	}
+}
+
+func TestFromJSONLDVariablesAndCoercion(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := ParseJSONLDContext(map[string]any{
+		"ex":       "https://example.org/",
+		"@vocab":   "https://example.org/vocab#",
+		"treasury": map[string]any{"@id": "ex:treasury", "@type": "@id"},
+	})
+	if err != nil {
+		t.Fatalf("ParseJSONLDContext returned error: %v", err)
+	}
+	triples, err := FromJSONLD(map[string]any{
+		"@id":      "?token",
+		"treasury": "ex:account",
+		"symbol":   "?symbol",
+	}, ctx, JSONLDOptions{Variables: true})
+	if err != nil {
+		t.Fatalf("FromJSONLD returned error: %v", err)
+	}
+	want := []Triple{
+		{Subject: Variable("token"), Predicate: IRI("https://example.org/vocab#symbol"), Object: Variable("symbol")},
+		{Subject: Variable("token"), Predicate: IRI("https://example.org/treasury"), Object: IRI("https://example.org/account")},
+	}
+	sort.Slice(triples, func(i, j int) bool { return triples[i].Predicate.Value > triples[j].Predicate.Value })
+	if !reflect.DeepEqual(triples, want) {
+		t.Fatalf("unexpected triples:\n got %v\nwant %v", triples, want)
+	}
+}
+
+func TestEvaluateSPARQL(t *testing.T) {
+	t.Parallel()
+
+	graph, err := ParseTurtle(`@prefix ex: <https://example.org/> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+
+ex:alice a ex:Account ; rdfs:label "Alice" ; ex:balance 30 ; ex:required 3 ; ex:collected 1 .
+ex:bob a ex:Account ; ex:balance 120 ; ex:required 2 ; ex:collected 2 .
+ex:carol a ex:Account ; rdfs:label "Carol" ; ex:balance 75 ; ex:frozen true .
+`, ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseTurtle returned error: %v", err)
+	}
+	store := NewStore()
+	store.Add(graph.Triples...)
+
+	query, err := ParseSPARQL(`PREFIX ex: <https://example.org/>
+PREFIX rdfs: <http://www.w3.org/2000/01/rdf-schema#>
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+SELECT ?account ?label ?missing
+WHERE {
+  ?account a ex:Account ; ex:balance ?balance .
+  OPTIONAL { ?account rdfs:label ?label }
+  OPTIONAL { ?account ex:required ?required ; ex:collected ?collected .
+             BIND(xsd:integer(?required - ?collected) AS ?missing) }
+  FILTER(?balance >= 50 || BOUND(?label))
+  FILTER NOT EXISTS { ?account ex:frozen true }
+}
+ORDER BY DESC(?missing) ?account`)
+	if err != nil {
+		t.Fatalf("ParseSPARQL returned error: %v", err)
+	}
+	results, err := query.Evaluate(store)
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	if !reflect.DeepEqual(results.Vars, []string{"account", "label", "missing"}) {
+		t.Fatalf("unexpected vars: %v", results.Vars)
+	}
+	want := []Solution{
+		{"account": IRI("https://example.org/alice"), "label": Literal("Alice", ""), "missing": Literal("2", XSDInteger)},
+		{"account": IRI("https://example.org/bob"), "missing": Literal("0", XSDInteger)},
+	}
+	if !reflect.DeepEqual(results.Solutions, want) {
+		t.Fatalf("unexpected solutions:\n got %v\nwant %v", results.Solutions, want)
+	}
+
+	ask, err := ParseSPARQL(`ASK { <https://example.org/carol> <https://example.org/frozen> ?frozen FILTER(?frozen) }`)
+	if err != nil {
+		t.Fatalf("ParseSPARQL returned error: %v", err)
+	}
+	results, err = ask.Evaluate(store)
+	if err != nil || results.Boolean == nil || !*results.Boolean {
+		t.Fatalf("expected ASK to be true, got %+v (err %v)", results, err)
+	}
+}
+
+func TestParseSPARQLRejectsUnsupportedFeatures(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		`SELECT ?s WHERE { ?s <https://example.org/p>+ ?o }`,
+		`SELECT (COUNT(?s) AS ?n) WHERE { ?s ?p ?o }`,
+		`SELECT ?s WHERE { ?s ?p ?o } GROUP BY ?s`,
+		`CONSTRUCT { ?s ?p ?o } WHERE { ?s ?p ?o }`,
+		`SELECT ?s WHERE { GRAPH ?g { ?s ?p ?o } }`,
+		`SELECT ?s WHERE { ?s ?p ?o `,
+	} {
+		if _, err := ParseSPARQL(query); err == nil {
+			t.Errorf("expected error for %q", query)
+		}
+	}
+}
+
+func TestCanonicalizeRelabelsBlankNodes(t *testing.T) {
+	t.Parallel()
+
+	p := IRI("https://example.org/p")
+	cycle := func(labels ...string) []Triple {
+		var triples []Triple
+		for i, label := range labels {
+			triples = append(triples, Triple{Subject: BlankNode(label), Predicate: p, Object: BlankNode(labels[(i+1)%len(labels)])})
+		}
+		return triples
+	}
+	graph := append(cycle("a", "b", "c"), cycle("d", "e", "f")...)
+	graph = append(graph,
+		Triple{Subject: IRI("https://example.org/s"), Predicate: p, Object: BlankNode("a")},
+		Triple{Subject: BlankNode("e"), Predicate: IRI("https://example.org/name"), Object: LangLiteral("x \"y\"", "en")},
+	)
+	want := Canonicalize(graph)
+
+	// Renaming blank nodes and reordering triples must not change the result.
+	renamed := append(cycle("n5", "n1", "n3"), cycle("n2", "n4", "n6")...)
+	renamed = append(renamed,
+		Triple{Subject: BlankNode("n4"), Predicate: IRI("https://example.org/name"), Object: LangLiteral("x \"y\"", "en")},
+		Triple{Subject: IRI("https://example.org/s"), Predicate: p, Object: BlankNode("n3")},
+		Triple{Subject: IRI("https://example.org/s"), Predicate: p, Object: BlankNode("n3")},
+	)
+	sort.Slice(renamed, func(i, j int) bool { return renamed[i].String() > renamed[j].String() })
+	if got := Canonicalize(renamed); got != want {
+		t.Fatalf("canonical form changed under relabelling:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Count(want, "\n") != 8 || !strings.Contains(want, `<https://example.org/s> <https://example.org/p> _:c14n`) || strings.Contains(want, "_:a ") {
+		t.Fatalf("unexpected canonical form:\n%s", want)
+	}
+
+	// A single six-node cycle has the same first-degree hashes as two
+	// three-node cycles but is not isomorphic to them.
+	if Canonicalize(cycle("a", "b", "c", "d", "e", "f")) == Canonicalize(append(cycle("a", "b", "c"), cycle("d", "e", "f")...)) {
+		t.Fatalf("non-isomorphic graphs share a canonical form")
+	}
+	if got := Canonicalize([]Triple{{Subject: IRI("https://example.org/s"), Predicate: p, Object: BlankNode("x")}}); got != "<https://example.org/s> <https://example.org/p> _:c14n0 .\n" {
+		t.Fatalf("unexpected canonical form: %q", got)
+	}
+}
+
+// TestCanonicalizeMatchesW3CExamples checks the worked examples of the W3C
+// RDF Dataset Canonicalization recommendation (RDFC-1.0, which produces the
+// same output as URDNA2015), including their published first-degree hashes.
+// In the second example _:e0 and _:e1 share a first-degree hash, so their
+// labels come from the Hash N-Degree Quads step.
+func TestCanonicalizeMatchesW3CExamples(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		input  string
+		hashes map[string]string
+		want   string
+	}{
+		{
+			name: "unique hashes",
+			input: `<http://example.com/#p> <http://example.com/#q> _:e0 .
+<http://example.com/#p> <http://example.com/#r> _:e1 .
+_:e0 <http://example.com/#s> <http://example.com/#u> .
+_:e1 <http://example.com/#t> <http://example.com/#u> .
+`,
+			hashes: map[string]string{
+				"e0": "21d1dd5ba21f3dee9d76c0c00c260fa6f5d5d65315099e553026f4828d0dc77a",
+				"e1": "6fa0b9bdb376852b5743ff39ca4cbf7ea14d34966b2828478fbf222e7c764473",
+			},
+			want: `<http://example.com/#p> <http://example.com/#q> _:c14n0 .
+<http://example.com/#p> <http://example.com/#r> _:c14n1 .
+_:c14n0 <http://example.com/#s> <http://example.com/#u> .
+_:c14n1 <http://example.com/#t> <http://example.com/#u> .
+`,
+		},
+		{
+			name: "shared hashes",
+			input: `<http://example.com/#p> <http://example.com/#q> _:e0 .
+<http://example.com/#p> <http://example.com/#q> _:e1 .
+_:e0 <http://example.com/#p> _:e2 .
+_:e1 <http://example.com/#p> _:e3 .
+_:e2 <http://example.com/#r> _:e3 .
+`,
+			hashes: map[string]string{
+				"e0": "3b26142829b8887d011d779079a243bd61ab53c3990d550320a17b59ade6ba36",
+				"e1": "3b26142829b8887d011d779079a243bd61ab53c3990d550320a17b59ade6ba36",
+				"e2": "15973d39de079913dac841ac4fa8c4781c0febfba5e83e5c6e250869587f8659",
+				"e3": "7e790a99273eed1dc57e43205d37ce232252c85b26ca4a6ff74ff3b5aea7bccd",
+			},
+			want: `<http://example.com/#p> <http://example.com/#q> _:c14n2 .
+<http://example.com/#p> <http://example.com/#q> _:c14n3 .
+_:c14n0 <http://example.com/#r> _:c14n1 .
+_:c14n2 <http://example.com/#p> _:c14n1 .
+_:c14n3 <http://example.com/#p> _:c14n0 .
+`,
+		},
+	} {
+		graph, err := ParseTurtle(tc.input, ParseOptions{})
+		if err != nil {
+			t.Fatalf("%s: ParseTurtle: %v", tc.name, err)
+		}
+		c := &canonicalizer{quads: graph.Triples, bnodes: make(map[string][]int)}
+		for i, triple := range c.quads {
+			for _, term := range []Term{triple.Subject, triple.Object} {
+				if term.IsBlankNode() {
+					c.bnodes[term.Value] = append(c.bnodes[term.Value], i)
+				}
+			}
+		}
+		for label, want := range tc.hashes {
+			if got := c.hashFirstDegree(label); got != want {
+				t.Errorf("%s: first-degree hash of _:%s = %s, want %s", tc.name, label, got, want)
+			}
+		}
+		if got := Canonicalize(graph.Triples); got != tc.want {
+			t.Errorf("%s: canonical form:\n%s\nwant:\n%s", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,1377 @@
+package rdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QueryForm distinguishes SELECT and ASK queries.
+type QueryForm int
+
+const (
+	SelectQuery QueryForm = iota
+	AskQuery
+)
+
+// Query is a parsed SPARQL query. The supported subset covers SELECT and ASK
+// with basic graph patterns, OPTIONAL, UNION, MINUS, FILTER, BIND, VALUES,
+// ORDER BY, LIMIT and OFFSET. Property paths, aggregates, sub-queries and
+// named graphs are rejected with a parse error.
+type Query struct {
+	Form       QueryForm
+	Distinct   bool
+	Projection []Projection
+	From       []string
+	Where      *GroupPattern
+	OrderBy    []OrderCondition
+	Limit      int
+	Offset     int
+	Prefixes   map[string]string
+}
+
+// Projection is a projected variable, optionally computed from an expression.
+type Projection struct {
+	Var  string
+	Expr Expression
+}
+
+// OrderCondition is one ORDER BY key.
+type OrderCondition struct {
+	Expr       Expression
+	Descending bool
+}
+
+// GroupPattern is a "{ ... }" block evaluated left to right.
+type GroupPattern struct {
+	Elements []PatternElement
+}
+
+// PatternElement is one component of a group graph pattern.
+type PatternElement interface {
+	patternElement()
+}
+
+// TriplesBlock is a basic graph pattern. Blank nodes in queries are
+// represented as variables whose names start with "_:".
+type TriplesBlock struct{ Patterns []Triple }
+
+// OptionalPattern is an OPTIONAL { ... } block.
+type OptionalPattern struct{ Group *GroupPattern }
+
+// MinusPattern is a MINUS { ... } block.
+type MinusPattern struct{ Group *GroupPattern }
+
+// UnionPattern is one or more groups joined with UNION. A nested group
+// without UNION is a UnionPattern with a single branch.
+type UnionPattern struct{ Branches []*GroupPattern }
+
+// FilterPattern is a FILTER constraint applied to the whole enclosing group.
+type FilterPattern struct{ Expr Expression }
+
+// BindPattern is a BIND(expr AS ?var) assignment.
+type BindPattern struct {
+	Expr Expression
+	Var  string
+}
+
+// ValuesPattern is an inline VALUES block. Unbound cells hold the zero Term.
+type ValuesPattern struct {
+	Vars []string
+	Rows [][]Term
+}
+
+func (TriplesBlock) patternElement()    {}
+func (OptionalPattern) patternElement() {}
+func (MinusPattern) patternElement()    {}
+func (UnionPattern) patternElement()    {}
+func (FilterPattern) patternElement()   {}
+func (BindPattern) patternElement()     {}
+func (ValuesPattern) patternElement()   {}
+
+// ParseSPARQL parses a SPARQL query.
+func ParseSPARQL(query string) (*Query, error) {
+	p := &sparqlParser{lex: newSPARQLLexer(query), prefixes: make(map[string]string)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+	q.Prefixes = p.prefixes
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIRI
+	tokPName
+	tokVar
+	tokBlank
+	tokString
+	tokLang
+	tokNumber
+	tokWord
+	tokPunct
+)
+
+type sparqlToken struct {
+	kind  tokenKind
+	text  string
+	line  int
+	col   int
+	extra string // numeric datatype
+}
+
+type sparqlLexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func newSPARQLLexer(src string) *sparqlLexer {
+	return &sparqlLexer{src: src, line: 1, col: 1}
+}
+
+func (l *sparqlLexer) peekRune(offset int) rune {
+	pos := l.pos
+	for i := 0; i < offset; i++ {
+		if pos >= len(l.src) {
+			return 0
+		}
+		_, size := utf8.DecodeRuneInString(l.src[pos:])
+		pos += size
+	}
+	if pos >= len(l.src) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[pos:])
+	return r
+}
+
+func (l *sparqlLexer) next() rune {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+func (l *sparqlLexer) errorf(line, col int, format string, args ...any) error {
+	return fmt.Errorf("sparql:%d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+func (l *sparqlLexer) token() (sparqlToken, error) {
+	for {
+		r := l.peekRune(0)
+		if r == '#' {
+			for r != '\n' && r != 0 {
+				l.next()
+				r = l.peekRune(0)
+			}
+			continue
+		}
+		if unicode.IsSpace(r) {
+			l.next()
+			continue
+		}
+		break
+	}
+	line, col := l.line, l.col
+	tok := sparqlToken{line: line, col: col}
+	r := l.peekRune(0)
+	switch {
+	case r == 0:
+		tok.kind = tokEOF
+		return tok, nil
+	case r == '<':
+		if iri, ok := l.scanIRI(); ok {
+			tok.kind, tok.text = tokIRI, iri
+			return tok, nil
+		}
+		l.next()
+		if l.peekRune(0) == '=' {
+			l.next()
+			tok.kind, tok.text = tokPunct, "<="
+			return tok, nil
+		}
+		tok.kind, tok.text = tokPunct, "<"
+		return tok, nil
+	case r == '?' || r == '$':
+		l.next()
+		name := l.scanName()
+		if name == "" {
+			return tok, l.errorf(line, col, "empty variable name")
+		}
+		tok.kind, tok.text = tokVar, name
+		return tok, nil
+	case r == '"' || r == '\'':
+		value, err := l.scanString()
+		if err != nil {
+			return tok, err
+		}
+		tok.kind, tok.text = tokString, value
+		return tok, nil
+	case r == '@':
+		l.next()
+		var b strings.Builder
+		for {
+			c := l.peekRune(0)
+			if c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+				b.WriteRune(l.next())
+				continue
+			}
+			break
+		}
+		tok.kind, tok.text = tokLang, b.String()
+		return tok, nil
+	case r == '_' && l.peekRune(1) == ':':
+		l.next()
+		l.next()
+		tok.kind, tok.text = tokBlank, l.scanName()
+		return tok, nil
+	case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(l.peekRune(1))):
+		tok.kind = tokNumber
+		tok.text, tok.extra = l.scanNumber()
+		return tok, nil
+	case unicode.IsLetter(r) || r == ':':
+		word := l.scanName()
+		if l.peekRune(0) == ':' {
+			l.next()
+			local := l.scanLocal()
+			tok.kind, tok.text = tokPName, word+":"+local
+			return tok, nil
+		}
+		tok.kind, tok.text = tokWord, word
+		return tok, nil
+	}
+	l.next()
+	two := string(r) + string(l.peekRune(0))
+	switch two {
+	case "!=", ">=", "&&", "||", "^^":
+		l.next()
+		tok.kind, tok.text = tokPunct, two
+		return tok, nil
+	}
+	if strings.ContainsRune("{}()[].;,*=>!+-/|^", r) {
+		tok.kind, tok.text = tokPunct, string(r)
+		return tok, nil
+	}
+	return tok, l.errorf(line, col, "unexpected character %q", r)
+}
+
+func (l *sparqlLexer) scanIRI() (string, bool) {
+	end := l.pos + 1
+	for end < len(l.src) {
+		c := l.src[end]
+		if c == '>' {
+			iri := l.src[l.pos+1 : end]
+			for l.pos <= end {
+				l.next()
+			}
+			return iri, true
+		}
+		if c <= ' ' || strings.IndexByte("<\"{}|^`\\", c) >= 0 {
+			return "", false
+		}
+		end++
+	}
+	return "", false
+}
+
+func (l *sparqlLexer) scanName() string {
+	var b strings.Builder
+	for {
+		c := l.peekRune(0)
+		if c == '_' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+			b.WriteRune(l.next())
+			continue
+		}
+		if c == '.' && b.Len() > 0 && isNameRune(l.peekRune(1)) {
+			b.WriteRune(l.next())
+			continue
+		}
+		return b.String()
+	}
+}
+
+func (l *sparqlLexer) scanLocal() string {
+	var b strings.Builder
+	for {
+		c := l.peekRune(0)
+		switch {
+		case c == '_' || c == '-' || c == ':' || c == '%' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			b.WriteRune(l.next())
+		case c == '\\':
+			l.next()
+			b.WriteRune(l.next())
+		case c == '.' && isNameRune(l.peekRune(1)):
+			b.WriteRune(l.next())
+		default:
+			return b.String()
+		}
+	}
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || r == '-' || r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (l *sparqlLexer) scanNumber() (string, string) {
+	var b strings.Builder
+	datatype := XSDInteger
+	for unicode.IsDigit(l.peekRune(0)) {
+		b.WriteRune(l.next())
+	}
+	if l.peekRune(0) == '.' && unicode.IsDigit(l.peekRune(1)) {
+		datatype = XSDDecimal
+		b.WriteRune(l.next())
+		for unicode.IsDigit(l.peekRune(0)) {
+			b.WriteRune(l.next())
+		}
+	}
+	if c := l.peekRune(0); c == 'e' || c == 'E' {
+		next := l.peekRune(1)
+		if unicode.IsDigit(next) || ((next == '+' || next == '-') && unicode.IsDigit(l.peekRune(2))) {
+			datatype = XSDDouble
+			b.WriteRune(l.next())
+			b.WriteRune(l.next())
+			for unicode.IsDigit(l.peekRune(0)) {
+				b.WriteRune(l.next())
+			}
+		}
+	}
+	return b.String(), datatype
+}
+
+func (l *sparqlLexer) scanString() (string, error) {
+	line, col := l.line, l.col
+	quote := l.next()
+	long := l.peekRune(0) == quote && l.peekRune(1) == quote
+	if long {
+		l.next()
+		l.next()
+	}
+	var b strings.Builder
+	for {
+		r := l.peekRune(0)
+		switch {
+		case r == 0:
+			return "", l.errorf(line, col, "unterminated string")
+		case r == quote && (!long || (l.peekRune(1) == quote && l.peekRune(2) == quote)):
+			l.next()
+			if long {
+				l.next()
+				l.next()
+			}
+			return b.String(), nil
+		case r == '\n' && !long:
+			return "", l.errorf(line, col, "unterminated string")
+		case r == '\\':
+			l.next()
+			esc := l.next()
+			switch esc {
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case '"', '\'', '\\':
+				b.WriteRune(esc)
+			case 'u', 'U':
+				n := 4
+				if esc == 'U' {
+					n = 8
+				}
+				var hex strings.Builder
+				for i := 0; i < n; i++ {
+					hex.WriteRune(l.next())
+				}
+				code, err := strconv.ParseUint(hex.String(), 16, 32)
+				if err != nil {
+					return "", l.errorf(line, col, "invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+			default:
+				return "", l.errorf(line, col, "invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteRune(l.next())
+		}
+	}
+}
+
+type sparqlParser struct {
+	lex      *sparqlLexer
+	tok      sparqlToken
+	prefixes map[string]string
+	base     string
+	blanks   int
+}
+
+func (p *sparqlParser) advance() error {
+	tok, err := p.lex.token()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *sparqlParser) errorf(format string, args ...any) error {
+	return p.lex.errorf(p.tok.line, p.tok.col, format, args...)
+}
+
+func (p *sparqlParser) isWord(word string) bool {
+	return p.tok.kind == tokWord && strings.EqualFold(p.tok.text, word)
+}
+
+func (p *sparqlParser) isPunct(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.text == punct
+}
+
+func (p *sparqlParser) expectPunct(punct string) error {
+	if !p.isPunct(punct) {
+		return p.errorf("expected %q, found %q", punct, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *sparqlParser) expectWord(word string) error {
+	if !p.isWord(word) {
+		return p.errorf("expected %s, found %q", word, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *sparqlParser) query() (*Query, error) {
+	for {
+		switch {
+		case p.isWord("PREFIX"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokPName || !strings.HasSuffix(p.tok.text, ":") {
+				return nil, p.errorf("expected prefix name, found %q", p.tok.text)
+			}
+			prefix := strings.TrimSuffix(p.tok.text, ":")
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokIRI {
+				return nil, p.errorf("expected IRI for prefix %s", prefix)
+			}
+			p.prefixes[prefix] = p.resolve(p.tok.text)
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		case p.isWord("BASE"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokIRI {
+				return nil, p.errorf("expected IRI after BASE")
+			}
+			p.base = p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+
+	q := &Query{Limit: -1}
+	switch {
+	case p.isWord("SELECT"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.selectClause(q); err != nil {
+			return nil, err
+		}
+	case p.isWord("ASK"):
+		q.Form = AskQuery
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	case p.isWord("CONSTRUCT"), p.isWord("DESCRIBE"):
+		return nil, p.errorf("%s queries are not supported", strings.ToUpper(p.tok.text))
+	default:
+		return nil, p.errorf("expected SELECT or ASK, found %q", p.tok.text)
+	}
+
+	for p.isWord("FROM") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.isWord("NAMED") {
+			return nil, p.errorf("FROM NAMED is not supported")
+		}
+		iri, err := p.iri()
+		if err != nil {
+			return nil, err
+		}
+		q.From = append(q.From, iri)
+	}
+	if p.isWord("WHERE") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	where, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	q.Where = where
+
+	if p.isWord("GROUP") || p.isWord("HAVING") {
+		return nil, p.errorf("GROUP BY and HAVING are not supported")
+	}
+	if p.isWord("ORDER") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			cond, ok, err := p.orderCondition()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			q.OrderBy = append(q.OrderBy, cond)
+		}
+		if len(q.OrderBy) == 0 {
+			return nil, p.errorf("expected ORDER BY condition")
+		}
+	}
+	for p.isWord("LIMIT") || p.isWord("OFFSET") {
+		isLimit := p.isWord("LIMIT")
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokNumber || p.tok.extra != XSDInteger {
+			return nil, p.errorf("expected integer, found %q", p.tok.text)
+		}
+		n, _ := strconv.Atoi(p.tok.text)
+		if isLimit {
+			q.Limit = n
+		} else {
+			q.Offset = n
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q after query", p.tok.text)
+	}
+	return q, nil
+}
+
+func (p *sparqlParser) selectClause(q *Query) error {
+	if p.isWord("DISTINCT") || p.isWord("REDUCED") {
+		q.Distinct = true
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	if p.isPunct("*") {
+		return p.advance()
+	}
+	for {
+		switch {
+		case p.tok.kind == tokVar:
+			q.Projection = append(q.Projection, Projection{Var: p.tok.text})
+			if err := p.advance(); err != nil {
+				return err
+			}
+		case p.isPunct("("):
+			if err := p.advance(); err != nil {
+				return err
+			}
+			expr, err := p.expression()
+			if err != nil {
+				return err
+			}
+			if err := p.expectWord("AS"); err != nil {
+				return err
+			}
+			if p.tok.kind != tokVar {
+				return p.errorf("expected variable after AS")
+			}
+			q.Projection = append(q.Projection, Projection{Var: p.tok.text, Expr: expr})
+			if err := p.advance(); err != nil {
+				return err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return err
+			}
+		default:
+			if len(q.Projection) == 0 {
+				return p.errorf("expected projection, found %q", p.tok.text)
+			}
+			return nil
+		}
+	}
+}
+
+func (p *sparqlParser) orderCondition() (OrderCondition, bool, error) {
+	switch {
+	case p.isWord("ASC"), p.isWord("DESC"):
+		desc := p.isWord("DESC")
+		if err := p.advance(); err != nil {
+			return OrderCondition{}, false, err
+		}
+		if !p.isPunct("(") {
+			return OrderCondition{}, false, p.errorf("expected ( after ASC/DESC")
+		}
+		expr, err := p.primary()
+		if err != nil {
+			return OrderCondition{}, false, err
+		}
+		return OrderCondition{Expr: expr, Descending: desc}, true, nil
+	case p.tok.kind == tokVar, p.isPunct("("):
+		expr, err := p.primary()
+		if err != nil {
+			return OrderCondition{}, false, err
+		}
+		return OrderCondition{Expr: expr}, true, nil
+	case p.tok.kind == tokWord && builtinArity(p.tok.text) != nil:
+		expr, err := p.primary()
+		if err != nil {
+			return OrderCondition{}, false, err
+		}
+		return OrderCondition{Expr: expr}, true, nil
+	}
+	return OrderCondition{}, false, nil
+}
+
+func (p *sparqlParser) group() (*GroupPattern, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	g := &GroupPattern{}
+	for {
+		switch {
+		case p.isPunct("}"):
+			return g, p.advance()
+		case p.tok.kind == tokEOF:
+			return nil, p.errorf("unterminated group pattern")
+		case p.isPunct("."):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case p.isWord("OPTIONAL"), p.isWord("MINUS"):
+			optional := p.isWord("OPTIONAL")
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			if optional {
+				g.Elements = append(g.Elements, OptionalPattern{Group: inner})
+			} else {
+				g.Elements = append(g.Elements, MinusPattern{Group: inner})
+			}
+		case p.isWord("FILTER"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			expr, err := p.constraint()
+			if err != nil {
+				return nil, err
+			}
+			g.Elements = append(g.Elements, FilterPattern{Expr: expr})
+		case p.isWord("BIND"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("("); err != nil {
+				return nil, err
+			}
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectWord("AS"); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokVar {
+				return nil, p.errorf("expected variable after AS")
+			}
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			g.Elements = append(g.Elements, BindPattern{Expr: expr, Var: name})
+		case p.isWord("VALUES"):
+			values, err := p.values()
+			if err != nil {
+				return nil, err
+			}
+			g.Elements = append(g.Elements, values)
+		case p.isWord("GRAPH"), p.isWord("SERVICE"):
+			return nil, p.errorf("%s patterns are not supported", strings.ToUpper(p.tok.text))
+		case p.isPunct("{"):
+			if p.lex.peekSelect() {
+				return nil, p.errorf("sub-queries are not supported")
+			}
+			union := UnionPattern{}
+			for {
+				inner, err := p.group()
+				if err != nil {
+					return nil, err
+				}
+				union.Branches = append(union.Branches, inner)
+				if !p.isWord("UNION") {
+					break
+				}
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			g.Elements = append(g.Elements, union)
+		default:
+			patterns, err := p.triplesSameSubject()
+			if err != nil {
+				return nil, err
+			}
+			if n := len(g.Elements); n > 0 {
+				if block, ok := g.Elements[n-1].(TriplesBlock); ok {
+					block.Patterns = append(block.Patterns, patterns...)
+					g.Elements[n-1] = block
+					continue
+				}
+			}
+			g.Elements = append(g.Elements, TriplesBlock{Patterns: patterns})
+		}
+	}
+}
+
+// peekSelect reports whether the next keyword after the current "{" is
+// SELECT, which introduces a sub-query.
+func (l *sparqlLexer) peekSelect() bool {
+	rest := strings.TrimLeftFunc(l.src[l.pos:], unicode.IsSpace)
+	return len(rest) >= 6 && strings.EqualFold(rest[:6], "SELECT")
+}
+
+func (p *sparqlParser) values() (ValuesPattern, error) {
+	if err := p.advance(); err != nil {
+		return ValuesPattern{}, err
+	}
+	var v ValuesPattern
+	single := p.tok.kind == tokVar
+	if single {
+		v.Vars = []string{p.tok.text}
+		if err := p.advance(); err != nil {
+			return v, err
+		}
+	} else {
+		if err := p.expectPunct("("); err != nil {
+			return v, err
+		}
+		for p.tok.kind == tokVar {
+			v.Vars = append(v.Vars, p.tok.text)
+			if err := p.advance(); err != nil {
+				return v, err
+			}
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return v, err
+		}
+	}
+	if err := p.expectPunct("{"); err != nil {
+		return v, err
+	}
+	for !p.isPunct("}") {
+		if single {
+			term, err := p.dataValue()
+			if err != nil {
+				return v, err
+			}
+			v.Rows = append(v.Rows, []Term{term})
+			continue
+		}
+		if err := p.expectPunct("("); err != nil {
+			return v, err
+		}
+		var row []Term
+		for !p.isPunct(")") {
+			term, err := p.dataValue()
+			if err != nil {
+				return v, err
+			}
+			row = append(row, term)
+		}
+		if len(row) != len(v.Vars) {
+			return v, p.errorf("VALUES row has %d values for %d variables", len(row), len(v.Vars))
+		}
+		v.Rows = append(v.Rows, row)
+		if err := p.advance(); err != nil {
+			return v, err
+		}
+	}
+	return v, p.advance()
+}
+
+func (p *sparqlParser) dataValue() (Term, error) {
+	if p.isWord("UNDEF") {
+		return Term{}, p.advance()
+	}
+	return p.term(false)
+}
+
+func (p *sparqlParser) triplesSameSubject() ([]Triple, error) {
+	var patterns []Triple
+	var subject Term
+	var err error
+	switch {
+	case p.isPunct("["):
+		subject, patterns, err = p.blankNodePropertyList()
+		if err != nil {
+			return nil, err
+		}
+		if p.isPunct(".") || p.isPunct("}") {
+			return patterns, nil
+		}
+	case p.isPunct("("):
+		subject, patterns, err = p.collection()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		subject, err = p.term(true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	more, err := p.propertyList(subject)
+	if err != nil {
+		return nil, err
+	}
+	return append(patterns, more...), nil
+}
+
+func (p *sparqlParser) propertyList(subject Term) ([]Triple, error) {
+	var patterns []Triple
+	for {
+		predicate, err := p.verb()
+		if err != nil {
+			return nil, err
+		}
+		for {
+			object, extra, err := p.object()
+			if err != nil {
+				return nil, err
+			}
+			patterns = append(patterns, extra...)
+			patterns = append(patterns, Triple{Subject: subject, Predicate: predicate, Object: object})
+			if !p.isPunct(",") {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if !p.isPunct(";") {
+			break
+		}
+		for p.isPunct(";") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if p.isPunct(".") || p.isPunct("}") || p.isPunct("]") {
+			break
+		}
+	}
+	if p.isPunct("/") || p.isPunct("|") || p.isPunct("*") || p.isPunct("+") || p.isPunct("^") {
+		return nil, p.errorf("property paths are not supported")
+	}
+	return patterns, nil
+}
+
+func (p *sparqlParser) verb() (Term, error) {
+	if p.tok.kind == tokWord && p.tok.text == "a" {
+		return IRI(RDFType), p.advance()
+	}
+	if p.isPunct("^") || p.isPunct("(") || p.isPunct("!") {
+		return Term{}, p.errorf("property paths are not supported")
+	}
+	term, err := p.term(true)
+	if err != nil {
+		return Term{}, err
+	}
+	if term.IsLiteral() || term.IsBlankNode() {
+		return Term{}, p.errorf("invalid predicate %s", term)
+	}
+	if p.isPunct("/") || p.isPunct("|") || p.isPunct("*") || p.isPunct("+") || p.isPunct("?") {
+		return Term{}, p.errorf("property paths are not supported")
+	}
+	return term, nil
+}
+
+func (p *sparqlParser) object() (Term, []Triple, error) {
+	switch {
+	case p.isPunct("["):
+		return p.blankNodePropertyList()
+	case p.isPunct("("):
+		return p.collection()
+	}
+	term, err := p.term(true)
+	return term, nil, err
+}
+
+func (p *sparqlParser) blankNodePropertyList() (Term, []Triple, error) {
+	if err := p.advance(); err != nil {
+		return Term{}, nil, err
+	}
+	node := p.freshBlank()
+	if p.isPunct("]") {
+		return node, nil, p.advance()
+	}
+	patterns, err := p.propertyList(node)
+	if err != nil {
+		return Term{}, nil, err
+	}
+	return node, patterns, p.expectPunct("]")
+}
+
+func (p *sparqlParser) collection() (Term, []Triple, error) {
+	if err := p.advance(); err != nil {
+		return Term{}, nil, err
+	}
+	var items []Term
+	var patterns []Triple
+	for !p.isPunct(")") {
+		item, extra, err := p.object()
+		if err != nil {
+			return Term{}, nil, err
+		}
+		patterns = append(patterns, extra...)
+		items = append(items, item)
+	}
+	if err := p.advance(); err != nil {
+		return Term{}, nil, err
+	}
+	if len(items) == 0 {
+		return IRI(RDFNil), patterns, nil
+	}
+	head := p.freshBlank()
+	current := head
+	for i, item := range items {
+		patterns = append(patterns, Triple{Subject: current, Predicate: IRI(RDFFirst), Object: item})
+		next := IRI(RDFNil)
+		if i < len(items)-1 {
+			next = p.freshBlank()
+		}
+		patterns = append(patterns, Triple{Subject: current, Predicate: IRI(RDFRest), Object: next})
+		current = next
+	}
+	return head, patterns, nil
+}
+
+func (p *sparqlParser) freshBlank() Term {
+	p.blanks++
+	return Variable(fmt.Sprintf("_:b%d", p.blanks))
+}
+
+// term parses an IRI, prefixed name, literal or (when pattern is true) a
+// variable or blank node label.
+func (p *sparqlParser) term(pattern bool) (Term, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokVar:
+		if !pattern {
+			return Term{}, p.errorf("variables are not allowed here")
+		}
+		return Variable(tok.text), p.advance()
+	case tokBlank:
+		if !pattern {
+			return Term{}, p.errorf("blank nodes are not allowed here")
+		}
+		return Variable("_:" + tok.text), p.advance()
+	case tokIRI, tokPName:
+		iri, err := p.iri()
+		return IRI(iri), err
+	case tokString:
+		return p.literal()
+	case tokNumber:
+		return Literal(tok.text, tok.extra), p.advance()
+	case tokPunct:
+		if tok.text == "-" || tok.text == "+" {
+			if err := p.advance(); err != nil {
+				return Term{}, err
+			}
+			if p.tok.kind != tokNumber {
+				return Term{}, p.errorf("expected number after %s", tok.text)
+			}
+			lexical := p.tok.text
+			if tok.text == "-" {
+				lexical = "-" + lexical
+			}
+			return Literal(lexical, p.tok.extra), p.advance()
+		}
+	case tokWord:
+		if tok.text == "true" || tok.text == "false" {
+			return Literal(tok.text, XSDBoolean), p.advance()
+		}
+	}
+	return Term{}, p.errorf("unexpected %q", tok.text)
+}
+
+func (p *sparqlParser) literal() (Term, error) {
+	value := p.tok.text
+	if err := p.advance(); err != nil {
+		return Term{}, err
+	}
+	switch {
+	case p.tok.kind == tokLang:
+		lang := p.tok.text
+		return LangLiteral(value, lang), p.advance()
+	case p.isPunct("^^"):
+		if err := p.advance(); err != nil {
+			return Term{}, err
+		}
+		datatype, err := p.iri()
+		if err != nil {
+			return Term{}, err
+		}
+		return Literal(value, datatype), nil
+	}
+	return Literal(value, ""), nil
+}
+
+func (p *sparqlParser) iri() (string, error) {
+	switch p.tok.kind {
+	case tokIRI:
+		iri := p.resolve(p.tok.text)
+		return iri, p.advance()
+	case tokPName:
+		prefix, local, _ := strings.Cut(p.tok.text, ":")
+		ns, ok := p.prefixes[prefix]
+		if !ok {
+			return "", p.errorf("undefined prefix %q", prefix)
+		}
+		return ns + local, p.advance()
+	}
+	return "", p.errorf("expected IRI, found %q", p.tok.text)
+}
+
+func (p *sparqlParser) resolve(iri string) string {
+	if p.base == "" || strings.Contains(iri, ":") {
+		return iri
+	}
+	return p.base + iri
+}
+
+func (p *sparqlParser) constraint() (Expression, error) {
+	if p.isPunct("(") {
+		return p.primary()
+	}
+	switch p.tok.kind {
+	case tokWord, tokIRI, tokPName:
+		return p.primary()
+	}
+	return nil, p.errorf("expected FILTER constraint, found %q", p.tok.text)
+}
+
+func (p *sparqlParser) expression() (Expression, error) {
+	left, err := p.andExpression()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("||") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.andExpression()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) andExpression() (Expression, error) {
+	left, err := p.relational()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("&&") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.relational()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) relational() (Expression, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokPunct {
+		switch op := p.tok.text; op {
+		case "=", "!=", "<", ">", "<=", ">=":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			right, err := p.additive()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	negated := false
+	if p.isWord("NOT") {
+		negated = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.isWord("IN") {
+			return nil, p.errorf("expected IN after NOT")
+		}
+	}
+	if p.isWord("IN") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		args, err := p.argList()
+		if err != nil {
+			return nil, err
+		}
+		return inExpr{value: left, list: args, negated: negated}, nil
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) additive() (Expression, error) {
+	left, err := p.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) multiplicative() (Expression, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") {
+		op := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpr{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *sparqlParser) unary() (Expression, error) {
+	switch {
+	case p.isPunct("!"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	case p.isPunct("-"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return arithmeticExpr{op: "-", left: constantExpr{term: Literal("0", XSDInteger)}, right: operand}, nil
+	case p.isPunct("+"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.unary()
+	}
+	return p.primary()
+}
+
+func (p *sparqlParser) primary() (Expression, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokPunct:
+		if tok.text == "(" {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectPunct(")")
+		}
+	case tokVar:
+		return variableExpr{name: tok.text}, p.advance()
+	case tokString, tokNumber:
+		term, err := p.term(false)
+		return constantExpr{term: term}, err
+	case tokIRI, tokPName:
+		iri, err := p.iri()
+		if err != nil {
+			return nil, err
+		}
+		if p.isPunct("(") {
+			args, err := p.argList()
+			if err != nil {
+				return nil, err
+			}
+			return functionExpr{name: iri, args: args}, nil
+		}
+		return constantExpr{term: IRI(iri)}, nil
+	case tokWord:
+		word := strings.ToUpper(tok.text)
+		switch word {
+		case "TRUE", "FALSE":
+			return constantExpr{term: Literal(strings.ToLower(word), XSDBoolean)}, p.advance()
+		case "EXISTS", "NOT":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			negated := word == "NOT"
+			if negated {
+				if err := p.expectWord("EXISTS"); err != nil {
+					return nil, err
+				}
+			}
+			group, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			return existsExpr{group: group, negated: negated}, nil
+		case "COUNT", "SUM", "MIN", "MAX", "AVG", "SAMPLE", "GROUP_CONCAT":
+			return nil, p.errorf("aggregate %s is not supported", word)
+		}
+		arity := builtinArity(word)
+		if arity == nil {
+			return nil, p.errorf("unknown function %s", tok.text)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		args, err := p.argList()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) < arity[0] || (arity[1] >= 0 && len(args) > arity[1]) {
+			return nil, p.errorf("%s: wrong number of arguments (%d)", word, len(args))
+		}
+		if word == "BOUND" {
+			if _, ok := args[0].(variableExpr); !ok {
+				return nil, p.errorf("BOUND requires a variable")
+			}
+		}
+		return functionExpr{name: word, args: args}, nil
+	}
+	return nil, p.errorf("unexpected %q in expression", tok.text)
+}
+
+func (p *sparqlParser) argList() ([]Expression, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var args []Expression
+	if p.isPunct(")") {
+		return args, p.advance()
+	}
+	for {
+		if p.isWord("DISTINCT") {
+			return nil, p.errorf("DISTINCT arguments are not supported")
+		}
+		arg, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.isPunct(")") {
+			return args, p.advance()
+		}
+		if err := p.expectPunct(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// builtinArity returns the minimum and maximum (-1 for variadic) argument
+// counts of a built-in function, or nil when name is not a built-in.
+func builtinArity(name string) []int {
+	switch strings.ToUpper(name) {
+	case "BOUND", "STR", "LANG", "DATATYPE", "IRI", "URI", "ISIRI", "ISURI", "ISBLANK",
+		"ISLITERAL", "ISNUMERIC", "STRLEN", "UCASE", "LCASE", "ABS", "ROUND", "CEIL", "FLOOR":
+		return []int{1, 1}
+	case "CONTAINS", "STRSTARTS", "STRENDS", "SAMETERM", "LANGMATCHES", "STRDT", "STRLANG",
+		"STRBEFORE", "STRAFTER":
+		return []int{2, 2}
+	case "REGEX":
+		return []int{2, 3}
+	case "SUBSTR":
+		return []int{2, 3}
+	case "IF":
+		return []int{3, 3}
+	case "COALESCE", "CONCAT":
+		return []int{1, -1}
+	}
+	return nil
+}
@@ -0,0 +1,1057 @@
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Solution maps variable names to bound terms.
+type Solution map[string]Term
+
+// Results holds the outcome of evaluating a query.
+type Results struct {
+	Vars      []string
+	Solutions []Solution
+	// Boolean is set for ASK queries.
+	Boolean *bool
+}
+
+// Evaluate runs q against store. FROM clauses are ignored; callers select
+// the store that represents the dataset.
+func (q *Query) Evaluate(store *Store) (*Results, error) {
+	ev := &evaluator{store: store}
+	solutions, err := ev.group(q.Where, []Solution{{}})
+	if err != nil {
+		return nil, err
+	}
+	if q.Form == AskQuery {
+		answer := len(solutions) > 0
+		return &Results{Boolean: &answer}, nil
+	}
+
+	for _, projection := range q.Projection {
+		if projection.Expr == nil {
+			continue
+		}
+		for _, solution := range solutions {
+			if value, err := projection.Expr.eval(ev, solution); err == nil {
+				solution[projection.Var] = value
+			}
+		}
+	}
+	if len(q.OrderBy) > 0 {
+		keys := make([][]Term, len(solutions))
+		for i, solution := range solutions {
+			keys[i] = make([]Term, len(q.OrderBy))
+			for j, cond := range q.OrderBy {
+				if value, err := cond.Expr.eval(ev, solution); err == nil {
+					keys[i][j] = value
+				}
+			}
+		}
+		index := make([]int, len(solutions))
+		for i := range index {
+			index[i] = i
+		}
+		sort.SliceStable(index, func(a, b int) bool {
+			for j, cond := range q.OrderBy {
+				c := orderCompare(keys[index[a]][j], keys[index[b]][j])
+				if c == 0 {
+					continue
+				}
+				if cond.Descending {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+		sorted := make([]Solution, len(solutions))
+		for i, idx := range index {
+			sorted[i] = solutions[idx]
+		}
+		solutions = sorted
+	}
+
+	vars := q.Variables()
+	projected := make([]Solution, 0, len(solutions))
+	seen := make(map[string]bool)
+	for _, solution := range solutions {
+		row := make(Solution, len(vars))
+		for _, name := range vars {
+			if value, ok := solution[name]; ok {
+				row[name] = value
+			}
+		}
+		if q.Distinct {
+			key := solutionKey(row, vars)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		projected = append(projected, row)
+	}
+	if q.Offset > 0 {
+		if q.Offset >= len(projected) {
+			projected = nil
+		} else {
+			projected = projected[q.Offset:]
+		}
+	}
+	if q.Limit >= 0 && q.Limit < len(projected) {
+		projected = projected[:q.Limit]
+	}
+	return &Results{Vars: vars, Solutions: projected}, nil
+}
+
+// Variables returns the projected variables, or every visible variable of
+// the WHERE clause in order of appearance for SELECT *.
+func (q *Query) Variables() []string {
+	if len(q.Projection) > 0 {
+		vars := make([]string, len(q.Projection))
+		for i, projection := range q.Projection {
+			vars[i] = projection.Var
+		}
+		return vars
+	}
+	var vars []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] && !strings.HasPrefix(name, "_:") {
+			seen[name] = true
+			vars = append(vars, name)
+		}
+	}
+	var walk func(g *GroupPattern)
+	walk = func(g *GroupPattern) {
+		for _, element := range g.Elements {
+			switch e := element.(type) {
+			case TriplesBlock:
+				for _, pattern := range e.Patterns {
+					for _, term := range []Term{pattern.Subject, pattern.Predicate, pattern.Object} {
+						if term.IsVariable() {
+							add(term.Value)
+						}
+					}
+				}
+			case OptionalPattern:
+				walk(e.Group)
+			case UnionPattern:
+				for _, branch := range e.Branches {
+					walk(branch)
+				}
+			case BindPattern:
+				add(e.Var)
+			case ValuesPattern:
+				for _, name := range e.Vars {
+					add(name)
+				}
+			}
+		}
+	}
+	walk(q.Where)
+	return vars
+}
+
+func solutionKey(solution Solution, vars []string) string {
+	var b strings.Builder
+	for _, name := range vars {
+		if value, ok := solution[name]; ok {
+			b.WriteString(value.String())
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+type evaluator struct {
+	store *Store
+}
+
+func (ev *evaluator) group(g *GroupPattern, input []Solution) ([]Solution, error) {
+	solutions := input
+	var filters []Expression
+	for _, element := range g.Elements {
+		var err error
+		switch e := element.(type) {
+		case TriplesBlock:
+			solutions = ev.bgp(e.Patterns, solutions)
+		case OptionalPattern:
+			var out []Solution
+			for _, solution := range solutions {
+				extended, err := ev.group(e.Group, []Solution{solution})
+				if err != nil {
+					return nil, err
+				}
+				if len(extended) == 0 {
+					out = append(out, solution)
+				} else {
+					out = append(out, extended...)
+				}
+			}
+			solutions = out
+		case MinusPattern:
+			var removed []Solution
+			removed, err = ev.group(e.Group, []Solution{{}})
+			if err != nil {
+				return nil, err
+			}
+			var out []Solution
+			for _, solution := range solutions {
+				if !minusMatches(solution, removed) {
+					out = append(out, solution)
+				}
+			}
+			solutions = out
+		case UnionPattern:
+			var out []Solution
+			for _, solution := range solutions {
+				for _, branch := range e.Branches {
+					extended, err := ev.group(branch, []Solution{solution})
+					if err != nil {
+						return nil, err
+					}
+					out = append(out, extended...)
+				}
+			}
+			solutions = out
+		case FilterPattern:
+			filters = append(filters, e.Expr)
+		case BindPattern:
+			out := make([]Solution, 0, len(solutions))
+			for _, solution := range solutions {
+				extended := solution.clone()
+				if value, err := e.Expr.eval(ev, solution); err == nil {
+					if _, bound := solution[e.Var]; bound {
+						return nil, fmt.Errorf("sparql: BIND variable ?%s is already bound", e.Var)
+					}
+					extended[e.Var] = value
+				}
+				out = append(out, extended)
+			}
+			solutions = out
+		case ValuesPattern:
+			solutions = joinValues(solutions, e)
+		}
+	}
+	if len(filters) == 0 {
+		return solutions, nil
+	}
+	out := make([]Solution, 0, len(solutions))
+	for _, solution := range solutions {
+		keep := true
+		for _, filter := range filters {
+			value, err := filter.eval(ev, solution)
+			if err != nil {
+				keep = false
+				break
+			}
+			ok, err := effectiveBoolean(value)
+			if err != nil || !ok {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, solution)
+		}
+	}
+	return out, nil
+}
+
+func (s Solution) clone() Solution {
+	out := make(Solution, len(s)+1)
+	for k, v := range s {
+		out[k] = v
+	}
+	return out
+}
+
+func minusMatches(solution Solution, removed []Solution) bool {
+	for _, candidate := range removed {
+		shared := false
+		compatible := true
+		for name, value := range candidate {
+			if current, ok := solution[name]; ok {
+				shared = true
+				if current != value {
+					compatible = false
+					break
+				}
+			}
+		}
+		if shared && compatible {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValues(solutions []Solution, values ValuesPattern) []Solution {
+	var out []Solution
+	for _, solution := range solutions {
+	rows:
+		for _, row := range values.Rows {
+			extended := solution.clone()
+			for i, name := range values.Vars {
+				value := row[i]
+				if value == (Term{}) {
+					continue
+				}
+				if current, ok := extended[name]; ok {
+					if current != value {
+						continue rows
+					}
+					continue
+				}
+				extended[name] = value
+			}
+			out = append(out, extended)
+		}
+	}
+	return out
+}
+
+// bgp matches patterns against the store for each input solution, choosing
+// the most selective remaining pattern at each step.
+func (ev *evaluator) bgp(patterns []Triple, input []Solution) []Solution {
+	var out []Solution
+	for _, solution := range input {
+		out = append(out, ev.match(patterns, solution)...)
+	}
+	return out
+}
+
+func (ev *evaluator) match(patterns []Triple, solution Solution) []Solution {
+	if len(patterns) == 0 {
+		return []Solution{solution}
+	}
+	best, bestScore := 0, -1
+	for i, pattern := range patterns {
+		score := 0
+		for weight, term := range []Term{pattern.Subject, pattern.Object, pattern.Predicate} {
+			if !substitute(term, solution).IsVariable() {
+				score += 3 - weight
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	pattern := patterns[best]
+	rest := make([]Triple, 0, len(patterns)-1)
+	rest = append(rest, patterns[:best]...)
+	rest = append(rest, patterns[best+1:]...)
+
+	s := substitute(pattern.Subject, solution)
+	p := substitute(pattern.Predicate, solution)
+	o := substitute(pattern.Object, solution)
+	var out []Solution
+	for _, triple := range ev.store.Match(s, p, o) {
+		extended := solution
+		ok := true
+		for _, pair := range [][2]Term{{s, triple.Subject}, {p, triple.Predicate}, {o, triple.Object}} {
+			if !pair[0].IsVariable() {
+				continue
+			}
+			if current, bound := extended[pair[0].Value]; bound {
+				if current != pair[1] {
+					ok = false
+					break
+				}
+				continue
+			}
+			if len(extended) == len(solution) {
+				extended = solution.clone()
+			}
+			extended[pair[0].Value] = pair[1]
+		}
+		if ok {
+			out = append(out, ev.match(rest, extended)...)
+		}
+	}
+	return out
+}
+
+func substitute(term Term, solution Solution) Term {
+	if term.IsVariable() {
+		if value, ok := solution[term.Value]; ok {
+			return value
+		}
+	}
+	return term
+}
+
+// Expression is a SPARQL expression.
+type Expression interface {
+	eval(ev *evaluator, solution Solution) (Term, error)
+}
+
+// VariableExpression returns an expression evaluating to the binding of the
+// named variable, for building queries programmatically.
+func VariableExpression(name string) Expression {
+	return variableExpr{name: name}
+}
+
+var errUnbound = errors.New("sparql: unbound variable")
+
+type constantExpr struct{ term Term }
+type variableExpr struct{ name string }
+type notExpr struct{ operand Expression }
+
+type logicalExpr struct {
+	op          string
+	left, right Expression
+}
+
+type compareExpr struct {
+	op          string
+	left, right Expression
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right Expression
+}
+
+type inExpr struct {
+	value   Expression
+	list    []Expression
+	negated bool
+}
+
+type existsExpr struct {
+	group   *GroupPattern
+	negated bool
+}
+
+type functionExpr struct {
+	name string
+	args []Expression
+}
+
+func (e constantExpr) eval(*evaluator, Solution) (Term, error) { return e.term, nil }
+
+func (e variableExpr) eval(_ *evaluator, solution Solution) (Term, error) {
+	if value, ok := solution[e.name]; ok {
+		return value, nil
+	}
+	return Term{}, errUnbound
+}
+
+func (e notExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	value, err := e.operand.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	b, err := effectiveBoolean(value)
+	if err != nil {
+		return Term{}, err
+	}
+	return booleanTerm(!b), nil
+}
+
+// eval implements SPARQL's three-valued logic: an error on one side is
+// absorbed when the other side decides the result.
+func (e logicalExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	left, leftErr := evalBoolean(e.left, ev, solution)
+	right, rightErr := evalBoolean(e.right, ev, solution)
+	if e.op == "||" {
+		switch {
+		case leftErr == nil && left, rightErr == nil && right:
+			return booleanTerm(true), nil
+		case leftErr != nil:
+			return Term{}, leftErr
+		case rightErr != nil:
+			return Term{}, rightErr
+		}
+		return booleanTerm(false), nil
+	}
+	switch {
+	case leftErr == nil && !left, rightErr == nil && !right:
+		return booleanTerm(false), nil
+	case leftErr != nil:
+		return Term{}, leftErr
+	case rightErr != nil:
+		return Term{}, rightErr
+	}
+	return booleanTerm(true), nil
+}
+
+func evalBoolean(expr Expression, ev *evaluator, solution Solution) (bool, error) {
+	value, err := expr.eval(ev, solution)
+	if err != nil {
+		return false, err
+	}
+	return effectiveBoolean(value)
+}
+
+func (e compareExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	left, err := e.left.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	right, err := e.right.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	if e.op == "=" || e.op == "!=" {
+		equal, err := termsEqual(left, right)
+		if err != nil {
+			return Term{}, err
+		}
+		return booleanTerm(equal == (e.op == "=")), nil
+	}
+	c, err := compareValues(left, right)
+	if err != nil {
+		return Term{}, err
+	}
+	switch e.op {
+	case "<":
+		return booleanTerm(c < 0), nil
+	case ">":
+		return booleanTerm(c > 0), nil
+	case "<=":
+		return booleanTerm(c <= 0), nil
+	default:
+		return booleanTerm(c >= 0), nil
+	}
+}
+
+func (e inExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	value, err := e.value.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	var firstErr error
+	for _, item := range e.list {
+		candidate, err := item.eval(ev, solution)
+		if err == nil {
+			var equal bool
+			equal, err = termsEqual(value, candidate)
+			if err == nil && equal {
+				return booleanTerm(!e.negated), nil
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return Term{}, firstErr
+	}
+	return booleanTerm(e.negated), nil
+}
+
+func (e existsExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	matches, err := ev.group(e.group, []Solution{solution})
+	if err != nil {
+		return Term{}, err
+	}
+	return booleanTerm((len(matches) > 0) != e.negated), nil
+}
+
+func (e arithmeticExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	left, err := e.left.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	right, err := e.right.eval(ev, solution)
+	if err != nil {
+		return Term{}, err
+	}
+	a, ok := numericValue(left)
+	if !ok {
+		return Term{}, fmt.Errorf("sparql: %s is not numeric", left)
+	}
+	b, ok := numericValue(right)
+	if !ok {
+		return Term{}, fmt.Errorf("sparql: %s is not numeric", right)
+	}
+	kind := a.kind
+	if b.kind > kind {
+		kind = b.kind
+	}
+	if kind == numInteger && e.op != "/" {
+		switch e.op {
+		case "+":
+			return integerTerm(a.i + b.i), nil
+		case "-":
+			return integerTerm(a.i - b.i), nil
+		case "*":
+			return integerTerm(a.i * b.i), nil
+		}
+	}
+	if e.op == "/" && kind == numInteger {
+		kind = numDecimal
+	}
+	var result float64
+	switch e.op {
+	case "+":
+		result = a.f + b.f
+	case "-":
+		result = a.f - b.f
+	case "*":
+		result = a.f * b.f
+	case "/":
+		if b.f == 0 && kind == numDecimal {
+			return Term{}, errors.New("sparql: division by zero")
+		}
+		result = a.f / b.f
+	}
+	return numericTerm(result, kind), nil
+}
+
+func (e functionExpr) eval(ev *evaluator, solution Solution) (Term, error) {
+	switch e.name {
+	case "BOUND":
+		_, ok := solution[e.args[0].(variableExpr).name]
+		return booleanTerm(ok), nil
+	case "IF":
+		cond, err := evalBoolean(e.args[0], ev, solution)
+		if err != nil {
+			return Term{}, err
+		}
+		if cond {
+			return e.args[1].eval(ev, solution)
+		}
+		return e.args[2].eval(ev, solution)
+	case "COALESCE":
+		for _, arg := range e.args {
+			if value, err := arg.eval(ev, solution); err == nil {
+				return value, nil
+			}
+		}
+		return Term{}, errUnbound
+	}
+
+	args := make([]Term, len(e.args))
+	for i, arg := range e.args {
+		value, err := arg.eval(ev, solution)
+		if err != nil {
+			return Term{}, err
+		}
+		args[i] = value
+	}
+	if strings.HasPrefix(e.name, XSDNamespace) {
+		return castTerm(args[0], e.name)
+	}
+	switch e.name {
+	case "STR":
+		if args[0].IsBlankNode() {
+			return Term{}, errors.New("sparql: STR of blank node")
+		}
+		return Literal(args[0].Value, ""), nil
+	case "LANG":
+		if !args[0].IsLiteral() {
+			return Term{}, errors.New("sparql: LANG of non-literal")
+		}
+		return Literal(args[0].Lang, ""), nil
+	case "DATATYPE":
+		if !args[0].IsLiteral() {
+			return Term{}, errors.New("sparql: DATATYPE of non-literal")
+		}
+		return IRI(args[0].Datatype), nil
+	case "IRI", "URI":
+		if args[0].IsIRI() {
+			return args[0], nil
+		}
+		return IRI(args[0].Value), nil
+	case "ISIRI", "ISURI":
+		return booleanTerm(args[0].IsIRI()), nil
+	case "ISBLANK":
+		return booleanTerm(args[0].IsBlankNode()), nil
+	case "ISLITERAL":
+		return booleanTerm(args[0].IsLiteral()), nil
+	case "ISNUMERIC":
+		_, ok := numericValue(args[0])
+		return booleanTerm(ok), nil
+	case "SAMETERM":
+		return booleanTerm(args[0] == args[1]), nil
+	case "LANGMATCHES":
+		tag, rangeTag := strings.ToLower(args[0].Value), strings.ToLower(args[1].Value)
+		if rangeTag == "*" {
+			return booleanTerm(tag != ""), nil
+		}
+		return booleanTerm(tag == rangeTag || strings.HasPrefix(tag, rangeTag+"-")), nil
+	case "STRDT":
+		return Literal(args[0].Value, args[1].Value), nil
+	case "STRLANG":
+		return LangLiteral(args[0].Value, args[1].Value), nil
+	case "ABS", "ROUND", "CEIL", "FLOOR":
+		n, ok := numericValue(args[0])
+		if !ok {
+			return Term{}, fmt.Errorf("sparql: %s requires a number", e.name)
+		}
+		if n.kind == numInteger {
+			if e.name == "ABS" && n.i < 0 {
+				return integerTerm(-n.i), nil
+			}
+			return args[0], nil
+		}
+		fn := map[string]func(float64) float64{"ABS": math.Abs, "ROUND": roundHalfUp, "CEIL": math.Ceil, "FLOOR": math.Floor}[e.name]
+		return numericTerm(fn(n.f), n.kind), nil
+	}
+
+	// The remaining functions operate on string literals and preserve the
+	// language tag of their first argument.
+	for _, arg := range args {
+		if !arg.IsLiteral() && e.name != "CONCAT" {
+			return Term{}, fmt.Errorf("sparql: %s requires string arguments", e.name)
+		}
+	}
+	str := args[0].Value
+	result := func(value string) Term {
+		if args[0].Lang != "" {
+			return LangLiteral(value, args[0].Lang)
+		}
+		return Literal(value, "")
+	}
+	switch e.name {
+	case "STRLEN":
+		return integerTerm(int64(len([]rune(str)))), nil
+	case "UCASE":
+		return result(strings.ToUpper(str)), nil
+	case "LCASE":
+		return result(strings.ToLower(str)), nil
+	case "CONTAINS":
+		return booleanTerm(strings.Contains(str, args[1].Value)), nil
+	case "STRSTARTS":
+		return booleanTerm(strings.HasPrefix(str, args[1].Value)), nil
+	case "STRENDS":
+		return booleanTerm(strings.HasSuffix(str, args[1].Value)), nil
+	case "STRBEFORE":
+		before, _, ok := strings.Cut(str, args[1].Value)
+		if !ok {
+			return Literal("", ""), nil
+		}
+		return result(before), nil
+	case "STRAFTER":
+		_, after, ok := strings.Cut(str, args[1].Value)
+		if !ok {
+			return Literal("", ""), nil
+		}
+		return result(after), nil
+	case "SUBSTR":
+		runes := []rune(str)
+		start, ok := numericValue(args[1])
+		if !ok {
+			return Term{}, errors.New("sparql: SUBSTR start must be numeric")
+		}
+		from := int(math.Round(start.f)) - 1
+		to := len(runes)
+		if len(args) == 3 {
+			length, ok := numericValue(args[2])
+			if !ok {
+				return Term{}, errors.New("sparql: SUBSTR length must be numeric")
+			}
+			to = from + int(math.Round(length.f))
+		}
+		from = max(from, 0)
+		to = min(to, len(runes))
+		if from >= to {
+			return result(""), nil
+		}
+		return result(string(runes[from:to])), nil
+	case "CONCAT":
+		var b strings.Builder
+		for _, arg := range args {
+			b.WriteString(arg.Value)
+		}
+		return Literal(b.String(), ""), nil
+	case "REGEX":
+		pattern := args[1].Value
+		if len(args) == 3 && strings.Contains(args[2].Value, "i") {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Term{}, fmt.Errorf("sparql: invalid regex: %w", err)
+		}
+		return booleanTerm(re.MatchString(str)), nil
+	}
+	return Term{}, fmt.Errorf("sparql: unsupported function %s", e.name)
+}
+
+func roundHalfUp(f float64) float64 {
+	return math.Floor(f + 0.5)
+}
+
+type numericKind int
+
+const (
+	numInteger numericKind = iota
+	numDecimal
+	numFloat
+	numDouble
+)
+
+type numeric struct {
+	kind numericKind
+	i    int64
+	f    float64
+}
+
+var integerDatatypes = map[string]bool{
+	XSDInteger: true, XSDNamespace + "int": true, XSDNamespace + "long": true,
+	XSDNamespace + "short": true, XSDNamespace + "byte": true,
+	XSDNamespace + "nonNegativeInteger": true, XSDNamespace + "positiveInteger": true,
+	XSDNamespace + "negativeInteger": true, XSDNamespace + "nonPositiveInteger": true,
+	XSDNamespace + "unsignedInt": true, XSDNamespace + "unsignedLong": true,
+	XSDNamespace + "unsignedShort": true, XSDNamespace + "unsignedByte": true,
+}
+
+func numericValue(term Term) (numeric, bool) {
+	if !term.IsLiteral() {
+		return numeric{}, false
+	}
+	lexical := strings.TrimSpace(term.Value)
+	switch {
+	case integerDatatypes[term.Datatype]:
+		i, err := strconv.ParseInt(lexical, 10, 64)
+		if err != nil {
+			return numeric{}, false
+		}
+		return numeric{kind: numInteger, i: i, f: float64(i)}, true
+	case term.Datatype == XSDDecimal:
+		f, err := strconv.ParseFloat(lexical, 64)
+		return numeric{kind: numDecimal, f: f}, err == nil
+	case term.Datatype == XSDNamespace+"float":
+		f, err := strconv.ParseFloat(lexical, 64)
+		return numeric{kind: numFloat, f: f}, err == nil
+	case term.Datatype == XSDDouble:
+		f, err := strconv.ParseFloat(lexical, 64)
+		return numeric{kind: numDouble, f: f}, err == nil
+	}
+	return numeric{}, false
+}
+
+func integerTerm(i int64) Term {
+	return Literal(strconv.FormatInt(i, 10), XSDInteger)
+}
+
+func numericTerm(f float64, kind numericKind) Term {
+	switch kind {
+	case numInteger:
+		return integerTerm(int64(f))
+	case numDecimal:
+		lexical := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(lexical, ".") {
+			lexical += ".0"
+		}
+		return Literal(lexical, XSDDecimal)
+	case numFloat:
+		return Literal(strconv.FormatFloat(f, 'E', -1, 32), XSDNamespace+"float")
+	default:
+		return Literal(canonicalDouble(f), XSDDouble)
+	}
+}
+
+func booleanTerm(b bool) Term {
+	return Literal(strconv.FormatBool(b), XSDBoolean)
+}
+
+func isStringLiteral(term Term) bool {
+	return term.IsLiteral() && (term.Datatype == XSDString || term.Datatype == "") && term.Lang == ""
+}
+
+// effectiveBoolean computes the SPARQL effective boolean value of term.
+func effectiveBoolean(term Term) (bool, error) {
+	if !term.IsLiteral() {
+		return false, fmt.Errorf("sparql: no boolean value for %s", term)
+	}
+	switch {
+	case term.Datatype == XSDBoolean:
+		return term.Value == "true" || term.Value == "1", nil
+	case isStringLiteral(term), term.Lang != "":
+		return term.Value != "", nil
+	}
+	if n, ok := numericValue(term); ok {
+		return n.f != 0 && !math.IsNaN(n.f), nil
+	}
+	return false, fmt.Errorf("sparql: no boolean value for %s", term)
+}
+
+// termsEqual implements the "=" operator: numeric and boolean values compare
+// by value, other literals and nodes by term identity.
+func termsEqual(a, b Term) (bool, error) {
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			if x.kind == numInteger && y.kind == numInteger {
+				return x.i == y.i, nil
+			}
+			return x.f == y.f, nil
+		}
+	}
+	if a.IsLiteral() && b.IsLiteral() && a.Datatype == XSDBoolean && b.Datatype == XSDBoolean {
+		x, _ := effectiveBoolean(a)
+		y, _ := effectiveBoolean(b)
+		return x == y, nil
+	}
+	if a.IsLiteral() && b.IsLiteral() && a.Datatype == XSDNamespace+"dateTime" && b.Datatype == XSDNamespace+"dateTime" {
+		c, err := compareValues(a, b)
+		return c == 0, err
+	}
+	return a == b, nil
+}
+
+// compareValues orders two comparable literals for <, >, <= and >=.
+func compareValues(a, b Term) (int, error) {
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			if x.kind == numInteger && y.kind == numInteger {
+				return compareInts(x.i, y.i), nil
+			}
+			return compareFloats(x.f, y.f), nil
+		}
+	}
+	if isStringLiteral(a) && isStringLiteral(b) {
+		return strings.Compare(a.Value, b.Value), nil
+	}
+	if a.IsLiteral() && b.IsLiteral() && a.Datatype == b.Datatype {
+		switch a.Datatype {
+		case XSDBoolean:
+			x, _ := effectiveBoolean(a)
+			y, _ := effectiveBoolean(b)
+			return compareInts(boolInt(x), boolInt(y)), nil
+		case XSDNamespace + "dateTime", XSDNamespace + "date":
+			x, errA := parseDateTime(a.Value)
+			y, errB := parseDateTime(b.Value)
+			if errA == nil && errB == nil {
+				return x.Compare(y), nil
+			}
+			return strings.Compare(a.Value, b.Value), nil
+		}
+		if a.Lang == b.Lang {
+			return strings.Compare(a.Value, b.Value), nil
+		}
+	}
+	return 0, fmt.Errorf("sparql: cannot compare %s and %s", a, b)
+}
+
+func parseDateTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid dateTime %q", value)
+}
+
+// orderCompare implements ORDER BY ordering: unbound values first, then
+// blank nodes, IRIs and literals.
+func orderCompare(a, b Term) int {
+	rank := func(t Term) int {
+		switch {
+		case t == (Term{}):
+			return 0
+		case t.IsBlankNode():
+			return 1
+		case t.IsIRI():
+			return 2
+		default:
+			return 3
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return compareInts(int64(ra), int64(rb))
+	}
+	if a.IsLiteral() {
+		if c, err := compareValues(a, b); err == nil {
+			return c
+		}
+		if c := strings.Compare(a.Value, b.Value); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Datatype+"@"+a.Lang, b.Datatype+"@"+b.Lang)
+	}
+	return strings.Compare(a.Value, b.Value)
+}
+
+func castTerm(term Term, datatype string) (Term, error) {
+	if term.IsBlankNode() {
+		return Term{}, errors.New("sparql: cannot cast blank node")
+	}
+	lexical := strings.TrimSpace(term.Value)
+	switch datatype {
+	case XSDString:
+		return Literal(term.Value, XSDString), nil
+	case XSDInteger:
+		if n, ok := numericValue(term); ok {
+			if n.kind == numInteger {
+				return integerTerm(n.i), nil
+			}
+			return integerTerm(int64(math.Trunc(n.f))), nil
+		}
+		if term.Datatype == XSDBoolean {
+			b, _ := effectiveBoolean(term)
+			return integerTerm(boolInt(b)), nil
+		}
+		i, err := strconv.ParseInt(lexical, 10, 64)
+		if err != nil {
+			return Term{}, fmt.Errorf("sparql: cannot cast %q to xsd:integer", term.Value)
+		}
+		return integerTerm(i), nil
+	case XSDDecimal, XSDDouble, XSDNamespace + "float":
+		kind := map[string]numericKind{XSDDecimal: numDecimal, XSDDouble: numDouble, XSDNamespace + "float": numFloat}[datatype]
+		if n, ok := numericValue(term); ok {
+			return numericTerm(n.f, kind), nil
+		}
+		f, err := strconv.ParseFloat(lexical, 64)
+		if err != nil {
+			return Term{}, fmt.Errorf("sparql: cannot cast %q to %s", term.Value, datatype)
+		}
+		return numericTerm(f, kind), nil
+	case XSDBoolean:
+		switch lexical {
+		case "true", "1":
+			return booleanTerm(true), nil
+		case "false", "0":
+			return booleanTerm(false), nil
+		}
+		if n, ok := numericValue(term); ok {
+			return booleanTerm(n.f != 0), nil
+		}
+		return Term{}, fmt.Errorf("sparql: cannot cast %q to xsd:boolean", term.Value)
+	case XSDNamespace + "dateTime":
+		if _, err := parseDateTime(lexical); err != nil {
+			return Term{}, err
+		}
+		return Literal(lexical, datatype), nil
+	}
+	return Term{}, fmt.Errorf("sparql: unsupported cast to %s", datatype)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package rdf
+
+import "sync"
+
+// Store is an in-memory set of triples indexed by subject and predicate. It
+// is safe for concurrent use.
+type Store struct {
+	mu          sync.RWMutex
+	triples     map[Triple]struct{}
+	order       []Triple
+	bySubject   map[Term][]Triple
+	byPredicate map[Term][]Triple
+	dirty       bool
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{triples: make(map[Triple]struct{})}
+}
+
+// Len returns the number of triples in the store.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.triples)
+}
+
+// Add inserts triples, ignoring duplicates, and returns how many were new.
+func (s *Store) Add(triples ...Triple) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := 0
+	for _, triple := range triples {
+		if _, ok := s.triples[triple]; ok {
+			continue
+		}
+		s.triples[triple] = struct{}{}
+		s.order = append(s.order, triple)
+		added++
+	}
+	if added > 0 {
+		s.dirty = true
+	}
+	return added
+}
+
+// Remove deletes triples and returns how many were present.
+func (s *Store) Remove(triples ...Triple) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, triple := range triples {
+		if _, ok := s.triples[triple]; ok {
+			delete(s.triples, triple)
+			removed++
+		}
+	}
+	if removed > 0 {
+		kept := s.order[:0]
+		for _, triple := range s.order {
+			if _, ok := s.triples[triple]; ok {
+				kept = append(kept, triple)
+			}
+		}
+		s.order = kept
+		s.dirty = true
+	}
+	return removed
+}
+
+// Triples returns the stored triples in insertion order.
+func (s *Store) Triples() []Triple {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Triple(nil), s.order...)
+}
+
+// Match returns the triples matching the pattern. Variables and the zero
+// Term act as wildcards.
+func (s *Store) Match(subject, predicate, object Term) []Triple {
+	s.mu.Lock()
+	if s.dirty || s.bySubject == nil {
+		s.reindex()
+	}
+	s.mu.Unlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	candidates := s.order
+	switch {
+	case bound(subject):
+		candidates = s.bySubject[subject]
+	case bound(predicate):
+		candidates = s.byPredicate[predicate]
+	}
+	var matches []Triple
+	for _, triple := range candidates {
+		if bound(subject) && triple.Subject != subject {
+			continue
+		}
+		if bound(predicate) && triple.Predicate != predicate {
+			continue
+		}
+		if bound(object) && triple.Object != object {
+			continue
+		}
+		matches = append(matches, triple)
+	}
+	return matches
+}
+
+func (s *Store) reindex() {
+	s.bySubject = make(map[Term][]Triple)
+	s.byPredicate = make(map[Term][]Triple)
+	for _, triple := range s.order {
+		s.bySubject[triple.Subject] = append(s.bySubject[triple.Subject], triple)
+		s.byPredicate[triple.Predicate] = append(s.byPredicate[triple.Predicate], triple)
+	}
+	s.dirty = false
+}
+
+func bound(term Term) bool {
+	return term != (Term{}) && !term.IsVariable()
+}
@@ -0,0 +1,194 @@
+// Package rdf provides a small RDF model, a Turtle parser and JSON-LD
+// conversion helpers used by the ontology automation in bhashctl.
+package rdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Well-known namespace and datatype IRIs.
+const (
+	RDFNamespace  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	RDFSNamespace = "http://www.w3.org/2000/01/rdf-schema#"
+	OWLNamespace  = "http://www.w3.org/2002/07/owl#"
+	XSDNamespace  = "http://www.w3.org/2001/XMLSchema#"
+
+	RDFType       = RDFNamespace + "type"
+	RDFFirst      = RDFNamespace + "first"
+	RDFRest       = RDFNamespace + "rest"
+	RDFNil        = RDFNamespace + "nil"
+	RDFLangString = RDFNamespace + "langString"
+
+	XSDString  = XSDNamespace + "string"
+	XSDBoolean = XSDNamespace + "boolean"
+	XSDInteger = XSDNamespace + "integer"
+	XSDDecimal = XSDNamespace + "decimal"
+	XSDDouble  = XSDNamespace + "double"
+)
+
+// TermKind distinguishes IRIs, blank nodes, literals and query variables.
+type TermKind int
+
+const (
+	KindIRI TermKind = iota
+	KindBlankNode
+	KindLiteral
+	// KindVariable only appears in query and update patterns.
+	KindVariable
+)
+
+// Term is an RDF term. Blank node values hold the label without the "_:"
+// prefix. Literals always carry a datatype; language-tagged literals use
+// rdf:langString.
+type Term struct {
+	Kind     TermKind
+	Value    string
+	Datatype string
+	Lang     string
+}
+
+// IRI returns an IRI term.
+func IRI(value string) Term {
+	return Term{Kind: KindIRI, Value: value}
+}
+
+// BlankNode returns a blank node term with the given label.
+func BlankNode(label string) Term {
+	return Term{Kind: KindBlankNode, Value: label}
+}
+
+// Literal returns a typed literal. An empty datatype defaults to xsd:string.
+func Literal(value, datatype string) Term {
+	if datatype == "" {
+		datatype = XSDString
+	}
+	return Term{Kind: KindLiteral, Value: value, Datatype: datatype}
+}
+
+// LangLiteral returns a language-tagged string literal.
+func LangLiteral(value, lang string) Term {
+	return Term{Kind: KindLiteral, Value: value, Datatype: RDFLangString, Lang: strings.ToLower(lang)}
+}
+
+// Variable returns a pattern variable named name (without the "?").
+func Variable(name string) Term {
+	return Term{Kind: KindVariable, Value: name}
+}
+
+func (t Term) IsIRI() bool       { return t.Kind == KindIRI }
+func (t Term) IsBlankNode() bool { return t.Kind == KindBlankNode }
+func (t Term) IsLiteral() bool   { return t.Kind == KindLiteral }
+func (t Term) IsVariable() bool  { return t.Kind == KindVariable }
+
+// String renders the term in N-Triples syntax.
+func (t Term) String() string {
+	switch t.Kind {
+	case KindIRI:
+		return "<" + escapeIRI(t.Value) + ">"
+	case KindBlankNode:
+		return "_:" + t.Value
+	case KindLiteral:
+		lexical := `"` + escapeLiteral(t.Value) + `"`
+		switch {
+		case t.Lang != "":
+			return lexical + "@" + t.Lang
+		case t.Datatype == "" || t.Datatype == XSDString:
+			return lexical
+		default:
+			return lexical + "^^<" + escapeIRI(t.Datatype) + ">"
+		}
+	case KindVariable:
+		return "?" + t.Value
+	default:
+		return fmt.Sprintf("<invalid term %d>", t.Kind)
+	}
+}
+
+// Triple is a single RDF statement.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// String renders the triple as an N-Triples line without a trailing newline.
+func (t Triple) String() string {
+	return t.Subject.String() + " " + t.Predicate.String() + " " + t.Object.String() + " ."
+}
+
+// Graph is a parsed RDF document together with the prefixes it declared.
+type Graph struct {
+	Triples  []Triple
+	Prefixes map[string]string
+}
+
+// Subjects returns the distinct subjects of the graph in first-seen order.
+func (g *Graph) Subjects() []Term {
+	seen := make(map[Term]bool)
+	var subjects []Term
+	for _, triple := range g.Triples {
+		if !seen[triple.Subject] {
+			seen[triple.Subject] = true
+			subjects = append(subjects, triple.Subject)
+		}
+	}
+	return subjects
+}
+
+// Objects returns the objects of every triple matching subject and predicate.
+func (g *Graph) Objects(subject Term, predicate string) []Term {
+	var objects []Term
+	for _, triple := range g.Triples {
+		if triple.Subject == subject && triple.Predicate.Value == predicate {
+			objects = append(objects, triple.Object)
+		}
+	}
+	return objects
+}
+
+// Merge appends the triples and prefixes of other to g. Prefixes already
+// declared in g take precedence.
+func (g *Graph) Merge(other *Graph) {
+	g.Triples = append(g.Triples, other.Triples...)
+	if g.Prefixes == nil {
+		g.Prefixes = make(map[string]string)
+	}
+	for prefix, ns := range other.Prefixes {
+		if _, ok := g.Prefixes[prefix]; !ok {
+			g.Prefixes[prefix] = ns
+		}
+	}
+}
+
+func escapeIRI(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r <= 0x20, r == '<', r == '>', r == '"', r == '{', r == '}', r == '|', r == '^', r == '`', r == '\\':
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeLiteral(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
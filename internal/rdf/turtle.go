@@ -0,0 +1,732 @@
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseError reports a syntax error with its position in the source.
+type ParseError struct {
+	Source string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	source := e.Source
+	if source == "" {
+		source = "turtle"
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", source, e.Line, e.Column, e.Msg)
+}
+
+// ParseTurtleFile parses the Turtle document at path.
+func ParseTurtleFile(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTurtle(string(data), ParseOptions{Source: path})
+}
+
+// ParseOptions configures ParseTurtle.
+type ParseOptions struct {
+	// Source names the document in error messages.
+	Source string
+	// Base is the base IRI used to resolve relative IRI references.
+	Base string
+	// BlankNodePrefix is prepended to every blank node label so that labels
+	// stay unique when several documents are merged.
+	BlankNodePrefix string
+}
+
+// ParseTurtleReader parses a Turtle document from r.
+func ParseTurtleReader(r io.Reader, opts ParseOptions) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTurtle(string(data), opts)
+}
+
+// ParseTurtle parses a Turtle 1.1 document. The parser supports the subset
+// of the grammar used across the ontology, shapes and example graphs:
+// directives, prefixed names, blank node property lists, collections and all
+// literal forms.
+func ParseTurtle(src string, opts ParseOptions) (*Graph, error) {
+	p := &turtleParser{
+		src:      src,
+		line:     1,
+		col:      1,
+		opts:     opts,
+		base:     opts.Base,
+		prefixes: make(map[string]string),
+	}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+	return &Graph{Triples: p.triples, Prefixes: p.prefixes}, nil
+}
+
+type turtleParser struct {
+	src      string
+	pos      int
+	line     int
+	col      int
+	opts     ParseOptions
+	base     string
+	prefixes map[string]string
+	triples  []Triple
+	bnodes   int
+}
+
+func (p *turtleParser) errorf(format string, args ...any) error {
+	return &ParseError{Source: p.opts.Source, Line: p.line, Column: p.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *turtleParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *turtleParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+func (p *turtleParser) peekAt(offset int) byte {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func (p *turtleParser) next() rune {
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	if r == '\n' {
+		p.line++
+		p.col = 1
+	} else {
+		p.col++
+	}
+	return r
+}
+
+func (p *turtleParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *turtleParser) hasKeyword(keyword string) bool {
+	if len(p.src)-p.pos < len(keyword) || !strings.EqualFold(p.src[p.pos:p.pos+len(keyword)], keyword) {
+		return false
+	}
+	after := p.peekAt(len(keyword))
+	return after == 0 || isSpace(rune(after)) || after == '<' || after == '#'
+}
+
+func (p *turtleParser) advance(n int) {
+	for i := 0; i < n && !p.eof(); i++ {
+		p.next()
+	}
+}
+
+func (p *turtleParser) skipSpace() {
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case isSpace(r):
+			p.next()
+		case r == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *turtleParser) expect(r rune) error {
+	p.skipSpace()
+	if p.eof() || p.peek() != r {
+		return p.errorf("expected %q", r)
+	}
+	p.next()
+	return nil
+}
+
+func (p *turtleParser) parseDocument() error {
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *turtleParser) parseStatement() error {
+	switch {
+	case p.hasPrefix("@prefix"):
+		p.advance(len("@prefix"))
+		if err := p.parsePrefixDirective(); err != nil {
+			return err
+		}
+		return p.expect('.')
+	case p.hasPrefix("@base"):
+		p.advance(len("@base"))
+		if err := p.parseBaseDirective(); err != nil {
+			return err
+		}
+		return p.expect('.')
+	case p.hasKeyword("PREFIX"):
+		p.advance(len("PREFIX"))
+		return p.parsePrefixDirective()
+	case p.hasKeyword("BASE"):
+		p.advance(len("BASE"))
+		return p.parseBaseDirective()
+	}
+	if err := p.parseTriples(); err != nil {
+		return err
+	}
+	return p.expect('.')
+}
+
+func (p *turtleParser) parsePrefixDirective() error {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && p.peek() != ':' {
+		if isSpace(p.peek()) {
+			return p.errorf("invalid prefix name")
+		}
+		p.next()
+	}
+	if p.eof() {
+		return p.errorf("unterminated prefix declaration")
+	}
+	prefix := p.src[start:p.pos]
+	p.next()
+	p.skipSpace()
+	iri, err := p.parseIRIRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[prefix] = iri
+	return nil
+}
+
+func (p *turtleParser) parseBaseDirective() error {
+	p.skipSpace()
+	iri, err := p.parseIRIRef()
+	if err != nil {
+		return err
+	}
+	p.base = iri
+	return nil
+}
+
+func (p *turtleParser) parseTriples() error {
+	p.skipSpace()
+	if p.peek() == '[' {
+		subject, err := p.parseBlankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() == '.' {
+			return nil
+		}
+		return p.parsePredicateObjectList(subject)
+	}
+	subject, err := p.parseSubject()
+	if err != nil {
+		return err
+	}
+	return p.parsePredicateObjectList(subject)
+}
+
+func (p *turtleParser) parseSubject() (Term, error) {
+	p.skipSpace()
+	switch p.peek() {
+	case '<':
+		iri, err := p.parseIRIRef()
+		return IRI(iri), err
+	case '(':
+		return p.parseCollection()
+	case '_':
+		return p.parseBlankNodeLabel()
+	default:
+		return p.parsePrefixedName()
+	}
+}
+
+func (p *turtleParser) parsePredicateObjectList(subject Term) error {
+	for {
+		p.skipSpace()
+		predicate, err := p.parseVerb()
+		if err != nil {
+			return err
+		}
+		if err := p.parseObjectList(subject, predicate); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() != ';' {
+			return nil
+		}
+		// Consume one or more semicolons; a trailing semicolon before the
+		// closing '.' or ']' is allowed.
+		for p.peek() == ';' {
+			p.next()
+			p.skipSpace()
+		}
+		if r := p.peek(); r == '.' || r == ']' || p.eof() {
+			return nil
+		}
+	}
+}
+
+func (p *turtleParser) parseVerb() (Term, error) {
+	if p.peek() == 'a' {
+		after := p.peekAt(1)
+		if after == 0 || isSpace(rune(after)) || after == '<' || after == '[' || after == '"' || after == '(' {
+			p.next()
+			return IRI(RDFType), nil
+		}
+	}
+	if p.peek() == '<' {
+		iri, err := p.parseIRIRef()
+		return IRI(iri), err
+	}
+	return p.parsePrefixedName()
+}
+
+func (p *turtleParser) parseObjectList(subject, predicate Term) error {
+	for {
+		object, err := p.parseObject()
+		if err != nil {
+			return err
+		}
+		p.triples = append(p.triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+		p.skipSpace()
+		if p.peek() != ',' {
+			return nil
+		}
+		p.next()
+	}
+}
+
+func (p *turtleParser) parseObject() (Term, error) {
+	p.skipSpace()
+	switch r := p.peek(); {
+	case r == '<':
+		iri, err := p.parseIRIRef()
+		return IRI(iri), err
+	case r == '[':
+		return p.parseBlankNodePropertyList()
+	case r == '(':
+		return p.parseCollection()
+	case r == '_' && p.peekAt(1) == ':':
+		return p.parseBlankNodeLabel()
+	case r == '"' || r == '\'':
+		return p.parseRDFLiteral()
+	case r == '+' || r == '-' || r == '.' || (r >= '0' && r <= '9'):
+		return p.parseNumericLiteral()
+	case p.hasBoolean("true"):
+		p.advance(4)
+		return Literal("true", XSDBoolean), nil
+	case p.hasBoolean("false"):
+		p.advance(5)
+		return Literal("false", XSDBoolean), nil
+	case p.eof():
+		return Term{}, p.errorf("unexpected end of input")
+	default:
+		return p.parsePrefixedName()
+	}
+}
+
+// hasBoolean reports whether the input continues with the boolean keyword
+// word rather than a prefixed name that happens to start with it.
+func (p *turtleParser) hasBoolean(word string) bool {
+	if !p.hasPrefix(word) {
+		return false
+	}
+	switch after := p.peekAt(len(word)); after {
+	case 0, ';', ',', '.', ']', ')', '#':
+		return true
+	default:
+		return isSpace(rune(after))
+	}
+}
+
+func (p *turtleParser) newBlankNode() Term {
+	label := fmt.Sprintf("%sgenid%d", p.opts.BlankNodePrefix, p.bnodes)
+	p.bnodes++
+	return BlankNode(label)
+}
+
+func (p *turtleParser) parseBlankNodePropertyList() (Term, error) {
+	if err := p.expect('['); err != nil {
+		return Term{}, err
+	}
+	node := p.newBlankNode()
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.next()
+		return node, nil
+	}
+	if err := p.parsePredicateObjectList(node); err != nil {
+		return Term{}, err
+	}
+	if err := p.expect(']'); err != nil {
+		return Term{}, err
+	}
+	return node, nil
+}
+
+func (p *turtleParser) parseCollection() (Term, error) {
+	if err := p.expect('('); err != nil {
+		return Term{}, err
+	}
+	var items []Term
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return Term{}, p.errorf("unterminated collection")
+		}
+		if p.peek() == ')' {
+			p.next()
+			break
+		}
+		item, err := p.parseObject()
+		if err != nil {
+			return Term{}, err
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return IRI(RDFNil), nil
+	}
+	head := p.newBlankNode()
+	current := head
+	for i, item := range items {
+		p.triples = append(p.triples, Triple{Subject: current, Predicate: IRI(RDFFirst), Object: item})
+		rest := IRI(RDFNil)
+		if i < len(items)-1 {
+			rest = p.newBlankNode()
+		}
+		p.triples = append(p.triples, Triple{Subject: current, Predicate: IRI(RDFRest), Object: rest})
+		current = rest
+	}
+	return head, nil
+}
+
+func (p *turtleParser) parseBlankNodeLabel() (Term, error) {
+	if !p.hasPrefix("_:") {
+		return Term{}, p.errorf("expected blank node label")
+	}
+	p.advance(2)
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.next()
+	}
+	// A trailing '.' terminates the statement rather than the label.
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+		p.col--
+	}
+	if p.pos == start {
+		return Term{}, p.errorf("empty blank node label")
+	}
+	return BlankNode(p.opts.BlankNodePrefix + p.src[start:p.pos]), nil
+}
+
+func (p *turtleParser) parseIRIRef() (string, error) {
+	if p.peek() != '<' {
+		return "", p.errorf("expected IRI")
+	}
+	p.next()
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated IRI")
+		}
+		r := p.next()
+		switch r {
+		case '>':
+			return p.resolve(b.String())
+		case '\\':
+			decoded, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(decoded)
+		case '\n', ' ':
+			return "", p.errorf("invalid character in IRI")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func (p *turtleParser) resolve(iri string) (string, error) {
+	if p.base == "" {
+		return iri, nil
+	}
+	ref, err := url.Parse(iri)
+	if err != nil || ref.IsAbs() {
+		return iri, nil
+	}
+	base, err := url.Parse(p.base)
+	if err != nil {
+		return "", p.errorf("invalid base IRI %q", p.base)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+func (p *turtleParser) parsePrefixedName() (Term, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != ':' {
+		r := p.peek()
+		if !isNameChar(r) || r == '.' && p.pos == start {
+			return Term{}, p.errorf("unexpected %q", r)
+		}
+		p.next()
+	}
+	if p.eof() {
+		return Term{}, p.errorf("unexpected end of input")
+	}
+	prefix := p.src[start:p.pos]
+	ns, ok := p.prefixes[prefix]
+	if !ok {
+		return Term{}, p.errorf("undefined prefix %q", prefix)
+	}
+	p.next()
+
+	var local strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case r == '\\':
+			p.next()
+			if p.eof() {
+				return Term{}, p.errorf("unterminated escape")
+			}
+			local.WriteRune(p.next())
+		case r == '%':
+			if p.pos+2 >= len(p.src) || !isHex(rune(p.src[p.pos+1])) || !isHex(rune(p.src[p.pos+2])) {
+				return Term{}, p.errorf("invalid percent escape")
+			}
+			local.WriteString(p.src[p.pos : p.pos+3])
+			p.advance(3)
+		case r == '.':
+			// Dots are only part of the local name when followed by another
+			// name character.
+			after, _ := utf8.DecodeRuneInString(p.src[p.pos+1:])
+			if !isNameChar(after) && after != ':' && after != '%' && after != '\\' {
+				return IRI(ns + local.String()), nil
+			}
+			local.WriteRune(p.next())
+		case isNameChar(r) || r == ':':
+			local.WriteRune(p.next())
+		default:
+			return IRI(ns + local.String()), nil
+		}
+	}
+	return IRI(ns + local.String()), nil
+}
+
+func (p *turtleParser) parseRDFLiteral() (Term, error) {
+	value, err := p.parseString()
+	if err != nil {
+		return Term{}, err
+	}
+	if p.peek() == '@' {
+		p.next()
+		start := p.pos
+		for !p.eof() && (isAlphaNum(p.peek()) || p.peek() == '-') {
+			p.next()
+		}
+		if p.pos == start {
+			return Term{}, p.errorf("empty language tag")
+		}
+		return LangLiteral(value, p.src[start:p.pos]), nil
+	}
+	if p.hasPrefix("^^") {
+		p.advance(2)
+		var datatype Term
+		if p.peek() == '<' {
+			iri, err := p.parseIRIRef()
+			if err != nil {
+				return Term{}, err
+			}
+			datatype = IRI(iri)
+		} else {
+			datatype, err = p.parsePrefixedName()
+			if err != nil {
+				return Term{}, err
+			}
+		}
+		return Literal(value, datatype.Value), nil
+	}
+	return Literal(value, XSDString), nil
+}
+
+func (p *turtleParser) parseString() (string, error) {
+	quote := p.next()
+	long := false
+	if p.peek() == quote && rune(p.peekAt(1)) == quote {
+		p.advance(2)
+		long = true
+	} else if p.peek() == quote {
+		p.next()
+		return "", nil
+	}
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string literal")
+		}
+		r := p.next()
+		switch {
+		case r == '\\':
+			decoded, err := p.parseStringEscape()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(decoded)
+		case r == quote && !long:
+			return b.String(), nil
+		case r == quote && long && p.peek() == quote && rune(p.peekAt(1)) == quote:
+			p.advance(2)
+			// Quotes immediately preceding the closing delimiter belong to
+			// the literal.
+			for p.peek() == quote {
+				b.WriteRune(p.next())
+			}
+			return b.String(), nil
+		case (r == '\n' || r == '\r') && !long:
+			return "", p.errorf("newline in short string literal")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func (p *turtleParser) parseStringEscape() (rune, error) {
+	if p.eof() {
+		return 0, p.errorf("unterminated escape")
+	}
+	switch r := p.peek(); r {
+	case 't':
+		p.next()
+		return '\t', nil
+	case 'b':
+		p.next()
+		return '\b', nil
+	case 'n':
+		p.next()
+		return '\n', nil
+	case 'r':
+		p.next()
+		return '\r', nil
+	case 'f':
+		p.next()
+		return '\f', nil
+	case '"', '\'', '\\':
+		p.next()
+		return r, nil
+	case 'u', 'U':
+		return p.parseUnicodeEscape()
+	default:
+		return 0, p.errorf("invalid escape \\%c", r)
+	}
+}
+
+// parseUnicodeEscape decodes \uXXXX or \UXXXXXXXX; the backslash has already
+// been consumed.
+func (p *turtleParser) parseUnicodeEscape() (rune, error) {
+	width := 0
+	switch p.peek() {
+	case 'u':
+		width = 4
+	case 'U':
+		width = 8
+	default:
+		return 0, p.errorf("invalid escape")
+	}
+	p.next()
+	if p.pos+width > len(p.src) {
+		return 0, p.errorf("truncated unicode escape")
+	}
+	value, err := strconv.ParseUint(p.src[p.pos:p.pos+width], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	p.advance(width)
+	return rune(value), nil
+}
+
+func (p *turtleParser) parseNumericLiteral() (Term, error) {
+	start := p.pos
+	if r := p.peek(); r == '+' || r == '-' {
+		p.next()
+	}
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.next()
+			n++
+		}
+		return n
+	}
+	intDigits := digits()
+	datatype := XSDInteger
+	if p.peek() == '.' && p.peekAt(1) >= '0' && p.peekAt(1) <= '9' {
+		p.next()
+		digits()
+		datatype = XSDDecimal
+	}
+	if r := p.peek(); r == 'e' || r == 'E' {
+		p.next()
+		if r := p.peek(); r == '+' || r == '-' {
+			p.next()
+		}
+		if digits() == 0 {
+			return Term{}, p.errorf("invalid exponent")
+		}
+		datatype = XSDDouble
+	}
+	if intDigits == 0 && datatype == XSDInteger {
+		return Term{}, p.errorf("invalid numeric literal")
+	}
+	return Literal(p.src[start:p.pos], datatype), nil
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func isHex(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+func isAlphaNum(r rune) bool {
+	return r < utf8.RuneSelf && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+}
+
+func isNameChar(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || r == 0xB7 || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
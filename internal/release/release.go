@@ -0,0 +1,293 @@
+// Package release builds tamper-evident manifests of ontology releases and
+// anchors their Merkle root to a Hedera consensus topic.
+package release
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/hedera"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// Algorithm describes how file digests and the Merkle root are computed.
+const Algorithm = "sha256-merkle/URDNA2015"
+
+// MessageType marks the HCS messages written by AnchorTo.
+const MessageType = "bhash-release-anchor"
+
+// DefaultPaths are the directories, relative to the repository root, whose
+// Turtle files make up a release: the ontology modules and the shapes.
+var DefaultPaths = []string{"ontology/src", "ontology/shapes"}
+
+// FileDigest is the SHA-256 digest of the URDNA2015 canonical form of one
+// Turtle file.
+type FileDigest struct {
+	Path    string `json:"path"`
+	Digest  string `json:"digest"`
+	Triples int    `json:"triples"`
+}
+
+// Anchor records where a release root was submitted on Hedera. Simulated
+// marks anchors made on the mock network, whose sequence number and
+// timestamp exist nowhere else; Verify rejects them.
+type Anchor struct {
+	Network            string    `json:"network"`
+	TopicID            string    `json:"topicId"`
+	SequenceNumber     uint64    `json:"sequenceNumber"`
+	ConsensusTimestamp time.Time `json:"consensusTimestamp"`
+	TransactionID      string    `json:"transactionId,omitempty"`
+	Simulated          bool      `json:"simulated,omitempty"`
+}
+
+// Manifest lists the files of a release and their Merkle root. Anchor is set
+// once the root has been submitted to a topic.
+type Manifest struct {
+	Version   string       `json:"version,omitempty"`
+	Algorithm string       `json:"algorithm"`
+	Root      string       `json:"root"`
+	Files     []FileDigest `json:"files"`
+	Anchor    *Anchor      `json:"anchor,omitempty"`
+}
+
+// Build canonicalises the Turtle files found under paths, which may be files
+// or directories relative to repoRoot, and computes their Merkle root.
+func Build(repoRoot string, paths []string, version string) (*Manifest, error) {
+	files, err := collect(repoRoot, paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("release: no Turtle files under %s", strings.Join(paths, ", "))
+	}
+	digests, err := digestFiles(repoRoot, files)
+	if err != nil {
+		return nil, err
+	}
+	root, err := MerkleRoot(digests)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{Version: version, Algorithm: Algorithm, Root: root, Files: digests}, nil
+}
+
+func collect(repoRoot string, paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) error {
+		rel, err := filepath.Rel(repoRoot, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !seen[rel] {
+			seen[rel] = true
+			files = append(files, rel)
+		}
+		return nil
+	}
+	for _, path := range paths {
+		full := filepath.Join(repoRoot, filepath.FromSlash(path))
+		err := filepath.WalkDir(full, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || filepath.Ext(path) != ".ttl" {
+				return nil
+			}
+			return add(path)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("release: %w", err)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func digestFiles(repoRoot string, files []string) ([]FileDigest, error) {
+	digests := make([]FileDigest, 0, len(files))
+	for _, file := range files {
+		graph, err := rdf.ParseTurtleFile(filepath.Join(repoRoot, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("release: %w", err)
+		}
+		sum := sha256.Sum256([]byte(rdf.Canonicalize(graph.Triples)))
+		digests = append(digests, FileDigest{Path: file, Digest: hex.EncodeToString(sum[:]), Triples: len(graph.Triples)})
+	}
+	return digests, nil
+}
+
+// MerkleRoot returns the hex Merkle root of files in the order given. Leaves
+// hash 0x00, the path, a newline and the raw file digest; inner nodes hash
+// 0x01 and their two children. An unpaired node is promoted unchanged.
+func MerkleRoot(files []FileDigest) (string, error) {
+	if len(files) == 0 {
+		return "", errors.New("release: no files to hash")
+	}
+	level := make([][]byte, len(files))
+	for i, file := range files {
+		digest, err := hex.DecodeString(file.Digest)
+		if err != nil {
+			return "", fmt.Errorf("release: %s: invalid digest %q", file.Path, file.Digest)
+		}
+		level[i] = hash([]byte{0}, []byte(file.Path+"\n"), digest)
+	}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hash([]byte{1}, level[i], level[i+1]))
+		}
+		level = next
+	}
+	return hex.EncodeToString(level[0]), nil
+}
+
+func hash(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+type anchorMessage struct {
+	Type      string `json:"type"`
+	Version   string `json:"version,omitempty"`
+	Algorithm string `json:"algorithm"`
+	Root      string `json:"root"`
+}
+
+// Message returns the HCS message that anchors the manifest root.
+func (m *Manifest) Message() ([]byte, error) {
+	return json.Marshal(anchorMessage{Type: MessageType, Version: m.Version, Algorithm: m.Algorithm, Root: m.Root})
+}
+
+// AnchorTo submits the manifest root to topicID and records the resulting
+// sequence number and consensus timestamp in m.Anchor.
+func (m *Manifest) AnchorTo(ctx context.Context, network hedera.Network, networkName, topicID string) error {
+	message, err := m.Message()
+	if err != nil {
+		return err
+	}
+	record, err := network.SubmitMessage(ctx, topicID, message)
+	if err != nil {
+		return fmt.Errorf("release: anchor root to topic %s: %w", topicID, err)
+	}
+	m.Anchor = &Anchor{
+		Network:            networkName,
+		TopicID:            record.TopicID,
+		SequenceNumber:     record.SequenceNumber,
+		ConsensusTimestamp: record.ConsensusTimestamp.UTC(),
+		TransactionID:      record.TransactionID,
+	}
+	return nil
+}
+
+// Verification is the outcome of Verify. Changed lists the files whose
+// canonical digest differs from the manifest and Problems explains every
+// reason Verified is false.
+type Verification struct {
+	Root         string   `json:"root"`
+	Recomputed   string   `json:"recomputed"`
+	AnchoredRoot string   `json:"anchoredRoot,omitempty"`
+	Changed      []string `json:"changed,omitempty"`
+	Problems     []string `json:"problems,omitempty"`
+	Verified     bool     `json:"verified"`
+}
+
+// Verify recomputes the root of the files listed in m from repoRoot and
+// checks it against the manifest and the message anchored on the topic.
+func Verify(ctx context.Context, repoRoot string, m *Manifest, messages hedera.TopicMessages) (*Verification, error) {
+	if m.Algorithm != Algorithm {
+		return nil, fmt.Errorf("release: unsupported algorithm %q", m.Algorithm)
+	}
+	result := &Verification{Root: m.Root}
+	files := make([]string, len(m.Files))
+	for i, file := range m.Files {
+		files[i] = file.Path
+	}
+	digests, err := digestFiles(repoRoot, files)
+	if err != nil {
+		return nil, err
+	}
+	for i, digest := range digests {
+		if digest.Digest != m.Files[i].Digest {
+			result.Changed = append(result.Changed, digest.Path)
+		}
+	}
+	if result.Recomputed, err = MerkleRoot(digests); err != nil {
+		return nil, err
+	}
+	if result.Recomputed != m.Root {
+		result.Problems = append(result.Problems, fmt.Sprintf("recomputed root %s does not match the manifest root", result.Recomputed))
+	}
+
+	if m.Anchor == nil {
+		result.Problems = append(result.Problems, "manifest has not been anchored")
+	} else if m.Anchor.Simulated {
+		result.Problems = append(result.Problems, "manifest was anchored on the simulated network; anchor it with --simulate=false")
+	} else {
+		record, err := messages.TopicMessage(ctx, m.Anchor.TopicID, m.Anchor.SequenceNumber)
+		if err != nil {
+			return nil, fmt.Errorf("release: read anchor: %w", err)
+		}
+		var anchored anchorMessage
+		if err := json.NewDecoder(bytes.NewReader(record.Message)).Decode(&anchored); err != nil || anchored.Type != MessageType {
+			result.Problems = append(result.Problems, fmt.Sprintf("topic %s message %d is not a release anchor", m.Anchor.TopicID, m.Anchor.SequenceNumber))
+		} else {
+			result.AnchoredRoot = anchored.Root
+			if anchored.Root != m.Root || anchored.Algorithm != m.Algorithm {
+				result.Problems = append(result.Problems, fmt.Sprintf("topic %s message %d anchors root %s", m.Anchor.TopicID, m.Anchor.SequenceNumber, anchored.Root))
+			}
+		}
+		if !record.ConsensusTimestamp.IsZero() && !record.ConsensusTimestamp.Equal(m.Anchor.ConsensusTimestamp) {
+			result.Problems = append(result.Problems, fmt.Sprintf("anchor reached consensus at %s, not %s", record.ConsensusTimestamp.Format(time.RFC3339Nano), m.Anchor.ConsensusTimestamp.Format(time.RFC3339Nano)))
+		}
+	}
+	result.Verified = len(result.Problems) == 0
+	return result, nil
+}
+
+// LoadManifest reads a manifest written by Write.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("release: read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("release: decode manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Write stores the manifest as indented JSON.
+func (m *Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("release: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("release: write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package release
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/hedera"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestAnchorAndVerifyRelease(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "ontology", "src", "core.ttl"), `@prefix ex: <https://example.org/> .
+ex:Account a ex:Class ; ex:label "Account" .
+`)
+	writeFile(t, filepath.Join(root, "ontology", "src", "alignment", "aiao.ttl"), `@prefix ex: <https://example.org/> .
+ex:Claim ex:restriction [ ex:onProperty ex:about ] .
+`)
+	writeFile(t, filepath.Join(root, "ontology", "shapes", "core.shacl.ttl"), `@prefix ex: <https://example.org/> .
+ex:AccountShape ex:targetClass ex:Account .
+`)
+	writeFile(t, filepath.Join(root, "ontology", "shapes", "README.md"), "not part of the release\n")
+
+	manifest, err := Build(root, DefaultPaths, "v1.0.0")
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	var paths []string
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	want := []string{"ontology/shapes/core.shacl.ttl", "ontology/src/alignment/aiao.ttl", "ontology/src/core.ttl"}
+	if len(paths) != len(want) {
+		t.Fatalf("unexpected files %v", paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("unexpected files %v", paths)
+		}
+	}
+
+	consensus := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	network := hedera.NewMockNetwork("testnet", hedera.WithNowFunc(func() time.Time { return consensus }))
+	ctx := context.Background()
+	if _, err := network.SubmitMessage(ctx, "0.0.5005", []byte("earlier message")); err != nil {
+		t.Fatalf("SubmitMessage returned error: %v", err)
+	}
+	if err := manifest.AnchorTo(ctx, network, "testnet", "0.0.5005"); err != nil {
+		t.Fatalf("AnchorTo returned error: %v", err)
+	}
+	if manifest.Anchor.SequenceNumber != 2 || !manifest.Anchor.ConsensusTimestamp.Equal(consensus) {
+		t.Fatalf("unexpected anchor %+v", manifest.Anchor)
+	}
+	manifestPath := filepath.Join(root, "release", "manifest.json")
+	if err := manifest.Write(manifestPath); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	loaded, err := LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest returned error: %v", err)
+	}
+
+	// Reformatting a file and renaming its blank nodes keeps the root.
+	writeFile(t, filepath.Join(root, "ontology", "src", "alignment", "aiao.ttl"), `@prefix ex: <https://example.org/> .
+ex:Claim
+    ex:restriction _:r .
+_:r ex:onProperty ex:about .
+`)
+	result, err := Verify(ctx, root, loaded, network)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !result.Verified || result.AnchoredRoot != manifest.Root {
+		t.Fatalf("expected verified release, got %+v", result)
+	}
+
+	writeFile(t, filepath.Join(root, "ontology", "src", "core.ttl"), `@prefix ex: <https://example.org/> .
+ex:Account a ex:Class ; ex:label "Account holder" .
+`)
+	result, err = Verify(ctx, root, loaded, network)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if result.Verified || len(result.Changed) != 1 || result.Changed[0] != "ontology/src/core.ttl" {
+		t.Fatalf("expected core.ttl to be reported as changed, got %+v", result)
+	}
+
+	// A manifest pointing at a message that is not an anchor fails.
+	loaded.Anchor.SequenceNumber = 1
+	writeFile(t, filepath.Join(root, "ontology", "src", "core.ttl"), `@prefix ex: <https://example.org/> .
+ex:Account a ex:Class ; ex:label "Account" .
+`)
+	result, err = Verify(ctx, root, loaded, network)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if result.Verified || len(result.Problems) != 1 {
+		t.Fatalf("expected a single anchor problem, got %+v", result)
+	}
+
+	// Anchors made on the simulated network never verify.
+	loaded.Anchor.SequenceNumber = 2
+	loaded.Anchor.Simulated = true
+	result, err = Verify(ctx, root, loaded, network)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if result.Verified || len(result.Problems) != 1 || !strings.Contains(result.Problems[0], "simulated") {
+		t.Fatalf("expected the simulated anchor to be rejected, got %+v", result)
+	}
+}
+
+func TestMerkleRootDependsOnOrderAndPaths(t *testing.T) {
+	t.Parallel()
+
+	a := FileDigest{Path: "a.ttl", Digest: "00"}
+	b := FileDigest{Path: "b.ttl", Digest: "01"}
+	c := FileDigest{Path: "c.ttl", Digest: "02"}
+	abc, err := MerkleRoot([]FileDigest{a, b, c})
+	if err != nil {
+		t.Fatalf("MerkleRoot returned error: %v", err)
+	}
+	bac, _ := MerkleRoot([]FileDigest{b, a, c})
+	renamed, _ := MerkleRoot([]FileDigest{a, b, {Path: "d.ttl", Digest: "02"}})
+	if abc == bac || abc == renamed {
+		t.Fatalf("expected distinct roots, got %s %s %s", abc, bac, renamed)
+	}
+	single, _ := MerkleRoot([]FileDigest{a})
+	if single == abc || len(single) != 64 {
+		t.Fatalf("unexpected single-leaf root %s", single)
+	}
+	if _, err := MerkleRoot(nil); err == nil {
+		t.Fatalf("expected an error for an empty release")
+	}
+}
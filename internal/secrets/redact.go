@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces secret values in redacted output.
+const Placeholder = "[REDACTED]"
+
+// minRedactLength guards against scrubbing short, common substrings such as
+// single characters from unrelated output.
+const minRedactLength = 4
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]struct{})
+)
+
+// Register records value as sensitive so that Redact removes it from output.
+func Register(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minRedactLength {
+		return
+	}
+	registryMu.Lock()
+	registry[value] = struct{}{}
+	registryMu.Unlock()
+}
+
+// Redact replaces every registered secret in s with Placeholder.
+func Redact(s string) string {
+	registryMu.RLock()
+	values := make([]string, 0, len(registry))
+	for value := range registry {
+		values = append(values, value)
+	}
+	registryMu.RUnlock()
+	if len(values) == 0 {
+		return s
+	}
+	// Replace longer secrets first so that a secret containing another one is
+	// scrubbed in full.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		s = strings.ReplaceAll(s, value, Placeholder)
+	}
+	return s
+}
+
+// RedactingWriter scrubs registered secrets from every write before passing
+// the data to the underlying writer.
+type RedactingWriter struct {
+	w io.Writer
+}
+
+// NewRedactingWriter wraps w so that secrets are removed from written output.
+func NewRedactingWriter(w io.Writer) *RedactingWriter {
+	return &RedactingWriter{w: w}
+}
+
+func (r *RedactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
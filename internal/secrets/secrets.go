@@ -0,0 +1,205 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Reference schemes understood by Resolver. Values without one of these
+// prefixes are treated as literal secrets for backwards compatibility with
+// plain environment variables and CLI flags.
+const (
+	SchemeFile  = "file:"
+	SchemeEnv   = "env:"
+	SchemeCmd   = "cmd:"
+	SchemeVault = "vault:"
+)
+
+// VaultPassphraseEnv names the environment variable holding the passphrase
+// used to decrypt vault references.
+const VaultPassphraseEnv = "BHASH_VAULT_PASSPHRASE"
+
+const defaultCommandTimeout = 30 * time.Second
+
+// Resolver dereferences secret references such as `file:/run/secrets/token`,
+// `env:NAME`, `cmd:pass show fluree` or `vault:/path/vault.json#name`.
+// Every resolved value is registered for redaction.
+type Resolver struct {
+	LookupEnv  func(string) (string, bool)
+	ReadFile   func(string) ([]byte, error)
+	RunCommand func(context.Context, string) ([]byte, error)
+	Passphrase func() (string, error)
+}
+
+// DefaultResolver returns a Resolver backed by the process environment, the
+// local filesystem and `sh -c` for command references.
+func DefaultResolver() Resolver {
+	return Resolver{
+		LookupEnv:  os.LookupEnv,
+		ReadFile:   os.ReadFile,
+		RunCommand: runShell,
+		Passphrase: func() (string, error) {
+			value, ok := os.LookupEnv(VaultPassphraseEnv)
+			if !ok || value == "" {
+				return "", fmt.Errorf("secrets: %s is required to open the vault", VaultPassphraseEnv)
+			}
+			return value, nil
+		},
+	}
+}
+
+// IsReference reports whether value uses one of the supported reference schemes.
+func IsReference(value string) bool {
+	for _, scheme := range []string{SchemeFile, SchemeEnv, SchemeCmd, SchemeVault} {
+		if strings.HasPrefix(value, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the secret identified by ref. Literal values are returned
+// unchanged. Resolved values are trimmed of surrounding whitespace and
+// registered with Register so that they are scrubbed from CLI output.
+func (r Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", nil
+	}
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case strings.HasPrefix(ref, SchemeFile):
+		value, err = r.resolveFile(strings.TrimPrefix(ref, SchemeFile))
+	case strings.HasPrefix(ref, SchemeEnv):
+		value, err = r.resolveEnv(strings.TrimPrefix(ref, SchemeEnv))
+	case strings.HasPrefix(ref, SchemeCmd):
+		value, err = r.resolveCommand(ctx, strings.TrimPrefix(ref, SchemeCmd))
+	case strings.HasPrefix(ref, SchemeVault):
+		value, err = r.resolveVault(strings.TrimPrefix(ref, SchemeVault))
+	default:
+		value = ref
+	}
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	Register(value)
+	return value, nil
+}
+
+func (r Resolver) resolveFile(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("secrets: file reference requires a path")
+	}
+	read := r.ReadFile
+	if read == nil {
+		read = os.ReadFile
+	}
+	data, err := read(path)
+	if err != nil {
+		return "", fmt.Errorf("secrets: read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+func (r Resolver) resolveEnv(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("secrets: env reference requires a variable name")
+	}
+	lookup := r.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	value, ok := lookup(name)
+	if !ok {
+		return "", fmt.Errorf("secrets: environment variable %s is not set", name)
+	}
+	if IsReference(value) {
+		return "", fmt.Errorf("secrets: environment variable %s must hold a literal value", name)
+	}
+	return value, nil
+}
+
+func (r Resolver) resolveCommand(ctx context.Context, command string) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", fmt.Errorf("secrets: cmd reference requires a command")
+	}
+	run := r.RunCommand
+	if run == nil {
+		run = runShell
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultCommandTimeout)
+		defer cancel()
+	}
+	out, err := run(ctx, command)
+	if err != nil {
+		// The command text may itself embed credentials, so only report the
+		// first word.
+		return "", fmt.Errorf("secrets: run %q: %w", firstWord(command), err)
+	}
+	return string(out), nil
+}
+
+func (r Resolver) resolveVault(ref string) (string, error) {
+	path, name, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || name == "" {
+		return "", fmt.Errorf("secrets: vault reference must look like vault:/path/to/vault.json#name")
+	}
+	passphrase := r.Passphrase
+	if passphrase == nil {
+		passphrase = DefaultResolver().Passphrase
+	}
+	secret, err := passphrase()
+	if err != nil {
+		return "", err
+	}
+	read := r.ReadFile
+	if read == nil {
+		read = os.ReadFile
+	}
+	data, err := read(path)
+	if err != nil {
+		return "", fmt.Errorf("secrets: read vault %s: %w", path, err)
+	}
+	entries, err := OpenVault(data, secret)
+	if err != nil {
+		return "", err
+	}
+	value, ok := entries[name]
+	if !ok {
+		return "", fmt.Errorf("secrets: vault %s has no entry %q", path, name)
+	}
+	return value, nil
+}
+
+func runShell(ctx context.Context, command string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func firstWord(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+
+	resolver := Resolver{
+		LookupEnv: func(key string) (string, bool) {
+			if key == "FROM_ENV" {
+				return "env-secret", true
+			}
+			return "", false
+		},
+		ReadFile: os.ReadFile,
+		RunCommand: func(_ context.Context, command string) ([]byte, error) {
+			if command != "pass show fluree" {
+				t.Fatalf("unexpected command %q", command)
+			}
+			return []byte("cmd-secret\n"), nil
+		},
+	}
+
+	cases := map[string]string{
+		"literal-secret":       "literal-secret",
+		"file:" + tokenFile:    "file-secret",
+		"env:FROM_ENV":         "env-secret",
+		"cmd:pass show fluree": "cmd-secret",
+		"  env:FROM_ENV  ":     "env-secret",
+		"":                     "",
+	}
+	for ref, want := range cases {
+		got, err := resolver.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %v", ref, err)
+		}
+		if got != want {
+			t.Fatalf("Resolve(%q) = %q, want %q", ref, got, want)
+		}
+	}
+
+	if _, err := resolver.Resolve(context.Background(), "env:MISSING"); err == nil {
+		t.Fatalf("expected error for unset environment variable")
+	}
+}
+
+func TestResolveCommandErrorHidesArguments(t *testing.T) {
+	resolver := Resolver{
+		RunCommand: func(context.Context, string) ([]byte, error) {
+			return nil, errors.New("exit status 1")
+		},
+	}
+	_, err := resolver.Resolve(context.Background(), "cmd:vault-cli --token hunter22 read")
+	if err == nil {
+		t.Fatalf("expected command failure")
+	}
+	if strings.Contains(err.Error(), "hunter22") {
+		t.Fatalf("error leaked command arguments: %v", err)
+	}
+}
+
+func TestVaultRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	if err := SetVaultEntry(path, "correct horse", "operator", "302e0201-operator-key"); err != nil {
+		t.Fatalf("SetVaultEntry: %v", err)
+	}
+	if err := SetVaultEntry(path, "correct horse", "fluree", "fluree-token"); err != nil {
+		t.Fatalf("SetVaultEntry: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read vault: %v", err)
+	}
+	if bytes.Contains(raw, []byte("fluree-token")) {
+		t.Fatalf("vault stores plaintext secrets")
+	}
+
+	resolver := Resolver{
+		ReadFile:   os.ReadFile,
+		Passphrase: func() (string, error) { return "correct horse", nil },
+	}
+	got, err := resolver.Resolve(context.Background(), "vault:"+path+"#operator")
+	if err != nil {
+		t.Fatalf("Resolve vault: %v", err)
+	}
+	if got != "302e0201-operator-key" {
+		t.Fatalf("unexpected vault value %q", got)
+	}
+
+	if _, err := OpenVault(raw, "wrong"); !errors.Is(err, ErrVaultPassphrase) {
+		t.Fatalf("expected passphrase error, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "vault:"+path+"#missing"); err == nil {
+		t.Fatalf("expected error for missing vault entry")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	Register("super-secret-token")
+	Register("abc")
+
+	got := Redact(`{"token":"super-secret-token","id":"abc"}`)
+	if strings.Contains(got, "super-secret-token") {
+		t.Fatalf("secret not redacted: %s", got)
+	}
+	if !strings.Contains(got, Placeholder) || !strings.Contains(got, `"abc"`) {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+
+	var buf bytes.Buffer
+	w := NewRedactingWriter(&buf)
+	if _, err := w.Write([]byte("auth failed for super-secret-token\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if buf.String() != "auth failed for "+Placeholder+"\n" {
+		t.Fatalf("unexpected writer output: %q", buf.String())
+	}
+}
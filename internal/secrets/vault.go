@@ -0,0 +1,137 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	vaultVersion = 1
+	vaultKDF     = "scrypt"
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	keyLength    = 32
+	saltLength   = 16
+)
+
+// ErrVaultPassphrase is returned when a vault cannot be decrypted with the
+// supplied passphrase.
+var ErrVaultPassphrase = errors.New("secrets: vault passphrase is incorrect or the vault is corrupt")
+
+// vaultFile is the on-disk representation of a passphrase-encrypted vault.
+// Entries are serialised as a JSON object and sealed with AES-256-GCM using a
+// key derived from the passphrase via scrypt.
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// OpenVault decrypts an encoded vault and returns its entries.
+func OpenVault(data []byte, passphrase string) (map[string]string, error) {
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("secrets: decode vault: %w", err)
+	}
+	if file.Version != vaultVersion || file.KDF != vaultKDF {
+		return nil, fmt.Errorf("secrets: unsupported vault version %d (%s)", file.Version, file.KDF)
+	}
+	gcm, err := vaultCipher(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrVaultPassphrase
+	}
+	entries := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, fmt.Errorf("secrets: decode vault entries: %w", err)
+	}
+	return entries, nil
+}
+
+// SealVault encrypts entries with passphrase and returns the encoded vault.
+func SealVault(entries map[string]string, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("secrets: vault passphrase cannot be empty")
+	}
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: encode vault entries: %w", err)
+	}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := vaultCipher(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	file := vaultFile{
+		Version:    vaultVersion,
+		KDF:        vaultKDF,
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// SetVaultEntry stores value under name in the vault at path, creating the
+// vault when it does not exist yet.
+func SetVaultEntry(path, passphrase, name, value string) error {
+	entries := make(map[string]string)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		entries, err = OpenVault(data, passphrase)
+		if err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("secrets: read vault %s: %w", path, err)
+	}
+	entries[name] = value
+	sealed, err := SealVault(entries, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, sealed, 0o600)
+}
+
+func vaultCipher(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: derive vault key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
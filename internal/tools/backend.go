@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+)
+
+// QueryBackend executes competency queries and writes their results as CSV.
+type QueryBackend interface {
+	// Name identifies the backend in progress output.
+	Name() string
+	// Prepare is called once before any query runs. workDir is a scratch
+	// directory that is removed after the run.
+	Prepare(cfg *Config, workDir string) error
+	// Query executes queryFile and writes CSV results to outputFile.
+	Query(queryFile, outputFile string) error
+}
+
+// ScopedBackend is implemented by backends that can run a query against a
+// subset of the datasets, as declared by the query's manifest entry.
+// Backends without it run every query against their full graph.
+type ScopedBackend interface {
+	QueryBackend
+	// QueryScoped executes queryFile against datasets only.
+	QueryScoped(queryFile, outputFile string, datasets []string) error
+}
+
+// RobotBackend merges the example datasets with ROBOT and runs each query
+// against the merged graph. Scoped queries run against a merge of their
+// own datasets, built once per distinct set. Queries may run concurrently.
+type RobotBackend struct {
+	robot    string
+	dataFile string
+	workDir  string
+
+	mu     sync.Mutex
+	scopes map[string]*robotScope
+}
+
+type robotScope struct {
+	once     sync.Once
+	dataFile string
+	err      error
+}
+
+// NewRobotBackend returns the default ROBOT-backed query backend.
+func NewRobotBackend() *RobotBackend {
+	return &RobotBackend{}
+}
+
+func (b *RobotBackend) Name() string {
+	return "robot"
+}
+
+func (b *RobotBackend) Prepare(cfg *Config, workDir string) error {
+	datasets, err := cfg.datasetPaths()
+	if err != nil {
+		return err
+	}
+	if err := ensureNonEmpty(datasets, "dataset"); err != nil {
+		return err
+	}
+	b.robot = cfg.RobotExecutable()
+	b.workDir = workDir
+	b.dataFile = filepath.Join(workDir, "data.ttl")
+	b.scopes = map[string]*robotScope{}
+	return mergeWithRobot(b.robot, datasets, b.dataFile)
+}
+
+func (b *RobotBackend) Query(queryFile, outputFile string) error {
+	return runRobotQuery(b.robot, b.dataFile, queryFile, outputFile)
+}
+
+func (b *RobotBackend) QueryScoped(queryFile, outputFile string, datasets []string) error {
+	key := strings.Join(datasets, "\n")
+	b.mu.Lock()
+	scope, ok := b.scopes[key]
+	if !ok {
+		scope = &robotScope{dataFile: filepath.Join(b.workDir, fmt.Sprintf("scope-%d.ttl", len(b.scopes)))}
+		b.scopes[key] = scope
+	}
+	b.mu.Unlock()
+	scope.once.Do(func() {
+		scope.err = mergeWithRobot(b.robot, datasets, scope.dataFile)
+	})
+	if scope.err != nil {
+		return scope.err
+	}
+	return runRobotQuery(b.robot, scope.dataFile, queryFile, outputFile)
+}
+
+// SPARQLQuerier is the subset of fluree.Client used by FlureeBackend.
+type SPARQLQuerier interface {
+	QuerySPARQL(ctx context.Context, ledger, query string) (*fluree.SPARQLResults, error)
+}
+
+// FlureeBackend runs competency queries against a Fluree ledger that was
+// loaded through the bootstrap or ingest path.
+type FlureeBackend struct {
+	client  SPARQLQuerier
+	ledger  string
+	timeout time.Duration
+}
+
+// NewFlureeBackend returns a backend that queries ledger through client.
+func NewFlureeBackend(client SPARQLQuerier, ledger string) *FlureeBackend {
+	return &FlureeBackend{client: client, ledger: ledger, timeout: 2 * time.Minute}
+}
+
+func (b *FlureeBackend) Name() string {
+	return "fluree:" + b.ledger
+}
+
+func (b *FlureeBackend) Prepare(*Config, string) error {
+	if b.ledger == "" {
+		return fmt.Errorf("fluree backend: ledger is required")
+	}
+	return nil
+}
+
+func (b *FlureeBackend) Query(queryFile, outputFile string) error {
+	query, err := os.ReadFile(queryFile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	results, err := b.client.QuerySPARQL(ctx, b.ledger, string(query))
+	if err != nil {
+		return fmt.Errorf("fluree query (%s): %w", filepath.Base(queryFile), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	if err := results.WriteCSV(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
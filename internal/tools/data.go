@@ -2,38 +2,45 @@ package tools
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
+// datasetPaths returns every dataset: the example graphs under
+// ontology/examples followed by the fixtures under tests/fixtures/datasets.
+// Queries and shapes without a manifest entry run against all of them.
 func (c *Config) datasetPaths() ([]string, error) {
-	base := []string{
-		filepath.Join(c.RepoRoot, "ontology", "examples", "core-consensus.ttl"),
-		filepath.Join(c.RepoRoot, "ontology", "examples", "token-compliance.ttl"),
-		filepath.Join(c.RepoRoot, "ontology", "examples", "smart-contracts.ttl"),
-		filepath.Join(c.RepoRoot, "ontology", "examples", "file-schedule.ttl"),
-		filepath.Join(c.RepoRoot, "ontology", "examples", "mirror-analytics.ttl"),
-		filepath.Join(c.RepoRoot, "ontology", "examples", "hiero.ttl"),
-                filepath.Join(c.RepoRoot, "ontology", "examples", "alignment-impact.ttl"),
-	}
-	fixturesDir := filepath.Join(c.RepoRoot, "tests", "fixtures", "datasets")
-	entries, err := os.ReadDir(fixturesDir)
-	if err != nil && !os.IsNotExist(err) {
+	examples, err := turtleFiles(filepath.Join(c.RepoRoot, "ontology", "examples"))
+	if err != nil {
 		return nil, err
 	}
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if filepath.Ext(entry.Name()) == ".ttl" {
-			base = append(base, filepath.Join(fixturesDir, entry.Name()))
+	fixtures, err := turtleFiles(filepath.Join(c.RepoRoot, "tests", "fixtures", "datasets"))
+	if err != nil {
+		return nil, err
+	}
+	return append(examples, fixtures...), nil
+}
+
+// turtleFiles lists the .ttl files directly under dir in name order. A
+// missing directory has none.
+func turtleFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
+		return nil, err
 	}
-	if len(base) > 7 {
-		sort.Strings(base[7:])
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".ttl" {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
 	}
-	return existingFiles(base), nil
+	return paths, nil
 }
 
 func (c *Config) shapePaths() ([]string, error) {
@@ -74,6 +81,100 @@ func (c *Config) queryPaths() ([]string, error) {
 	return paths, nil
 }
 
+// ontologyModules returns the modules under ontology/src keyed by their
+// slash-separated name relative to that directory without the .ttl suffix
+// (for example "token" or "alignment/aiao"). Names are ordered with core
+// first so dependent modules follow the vocabulary they extend.
+func (c *Config) ontologyModules() ([]string, map[string]string, error) {
+	srcDir := filepath.Join(c.RepoRoot, "ontology", "src")
+	paths := make(map[string]string)
+	err := filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".ttl" {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), ".ttl")
+		paths[name] = path
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "core") != (names[j] == "core") {
+			return names[i] == "core"
+		}
+		return names[i] < names[j]
+	})
+	return names, paths, nil
+}
+
+// resolveModules maps the requested module names to file paths. An empty
+// request selects every module.
+func (c *Config) resolveModules(requested []string) ([]string, map[string]string, error) {
+	names, paths, err := c.ontologyModules()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(requested) == 0 {
+		return names, paths, nil
+	}
+	selected := make([]string, 0, len(requested))
+	for _, name := range requested {
+		name = strings.TrimSuffix(filepath.ToSlash(strings.TrimSpace(name)), ".ttl")
+		if _, ok := paths[name]; !ok {
+			return nil, nil, fmt.Errorf("unknown ontology module %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		selected = append(selected, name)
+	}
+	return selected, paths, nil
+}
+
+// exampleFilesForModule returns the example graphs under ontology/examples
+// that illustrate module. Example files are named after the module they
+// exercise, optionally combined with a neighbouring module
+// (core-consensus.ttl) or a topic suffix (token-compliance.ttl); alignment
+// modules share alignment-*.ttl examples.
+func (c *Config) exampleFilesForModule(module string) ([]string, error) {
+	examplesDir := filepath.Join(c.RepoRoot, "ontology", "examples")
+	entries, err := os.ReadDir(examplesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var matches []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".ttl" {
+			continue
+		}
+		stem := strings.TrimSuffix(entry.Name(), ".ttl")
+		if exampleMatchesModule(stem, module) {
+			matches = append(matches, filepath.Join(examplesDir, entry.Name()))
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func exampleMatchesModule(stem, module string) bool {
+	if group, _, ok := strings.Cut(module, "/"); ok {
+		return group == "alignment" && strings.HasPrefix(stem, "alignment-")
+	}
+	return stem == module || strings.HasPrefix(stem, module+"-") || strings.HasSuffix(stem, "-"+module)
+}
+
 func existingFiles(paths []string) []string {
 	filtered := paths[:0]
 	for _, path := range paths {
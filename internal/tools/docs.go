@@ -0,0 +1,607 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+const (
+	skosNamespace    = "http://www.w3.org/2004/02/skos/core#"
+	dctermsNamespace = "http://purl.org/dc/terms/"
+	hederaNamespace  = "https://bhash.dev/hedera/core/"
+)
+
+// docKinds maps the declaration types documented by RunDocs to their page
+// headings, in the order the index lists them.
+var docKinds = []struct{ typ, name string }{
+	{rdf.OWLNamespace + "Class", "Class"},
+	{rdf.OWLNamespace + "ObjectProperty", "Object property"},
+	{rdf.OWLNamespace + "DatatypeProperty", "Datatype property"},
+	{rdf.OWLNamespace + "AnnotationProperty", "Annotation property"},
+}
+
+// mappingPredicates relate an alignment term to a term of an external
+// ontology.
+var mappingPredicates = []string{
+	rdf.RDFSNamespace + "subClassOf",
+	rdf.RDFSNamespace + "subPropertyOf",
+	rdf.OWLNamespace + "equivalentClass",
+	rdf.OWLNamespace + "equivalentProperty",
+	skosNamespace + "exactMatch",
+	skosNamespace + "closeMatch",
+	skosNamespace + "broadMatch",
+	skosNamespace + "narrowMatch",
+	skosNamespace + "relatedMatch",
+	dctermsNamespace + "source",
+}
+
+// DocsOptions configures RunDocs.
+type DocsOptions struct {
+	// OutDir receives the site. Empty selects build/docs.
+	OutDir string
+}
+
+type docLink struct {
+	Label string
+	Href  string
+	Title string
+}
+
+type docTerm struct {
+	IRI          string
+	Name         string
+	Slug         string
+	Kind         string
+	Module       string
+	Labels       []string
+	Definitions  []string
+	Sources      []string
+	Super        []docLink
+	Sub          []docLink
+	Restrictions []string
+	Domain       []docLink
+	Range        []docLink
+	DomainOf     []docLink
+	RangeOf      []docLink
+	Inverse      []docLink
+	Mappings     []docMapping
+	Shapes       []docLink
+	Queries      []docLink
+}
+
+type docModule struct {
+	Name        string
+	IRI         string
+	File        string
+	Title       string
+	Description string
+	Version     string
+	Imports     []string
+	Terms       []*docTerm
+	Mappings    []docMapping
+}
+
+type docMapping struct {
+	Term     docLink
+	Relation string
+	Target   docLink
+}
+
+// docSite is the documentation model rendered by the templates.
+type docSite struct {
+	Modules []*docModule
+	Terms   map[string]*docTerm
+	ctx     rdf.Context
+	graph   *rdf.Graph
+}
+
+// RunDocs generates a static HTML site from the ontology modules under
+// ontology/src: an index of modules, a page per class and property with its
+// labels, definitions, source documents, hierarchy, domain and range and the
+// SHACL shapes and competency queries that mention it, and a page of the
+// alignment modules' mappings to external ontologies. It returns the
+// directory written.
+func RunDocs(cfg *Config, opts DocsOptions) (string, error) {
+	outDir := opts.OutDir
+	if outDir == "" {
+		outDir = filepath.Join(cfg.BuildDir, "docs")
+	}
+	site, err := loadDocSite(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := site.linkShapes(cfg); err != nil {
+		return "", err
+	}
+	if err := site.linkQueries(cfg); err != nil {
+		return "", err
+	}
+
+	termsDir := filepath.Join(outDir, "terms")
+	if err := os.MkdirAll(termsDir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "style.css"), []byte(docStyle), 0o644); err != nil {
+		return "", err
+	}
+	if err := renderDocPage(filepath.Join(outDir, "index.html"), "index", "", site); err != nil {
+		return "", err
+	}
+	if err := renderDocPage(filepath.Join(outDir, "mappings.html"), "mappings", "", site); err != nil {
+		return "", err
+	}
+	for _, term := range site.Terms {
+		if err := renderDocPage(filepath.Join(termsDir, term.Slug), "term", "../", term); err != nil {
+			return "", err
+		}
+	}
+	return outDir, nil
+}
+
+// loadDocSite parses every module and collects the terms each declares. A
+// term declared by several modules belongs to the first, which puts core
+// ahead of the modules that extend it.
+func loadDocSite(cfg *Config) (*docSite, error) {
+	names, paths, err := cfg.ontologyModules()
+	if err != nil {
+		return nil, err
+	}
+	site := &docSite{Terms: map[string]*docTerm{}, ctx: rdf.DefaultContext(), graph: &rdf.Graph{}}
+	graphs := make([]*rdf.Graph, len(names))
+	for i, name := range names {
+		source, err := os.ReadFile(paths[name])
+		if err != nil {
+			return nil, err
+		}
+		graph, err := rdf.ParseTurtle(string(source), rdf.ParseOptions{Source: paths[name], BlankNodePrefix: fmt.Sprintf("m%d", i)})
+		if err != nil {
+			return nil, err
+		}
+		graphs[i] = graph
+		site.ctx.AddPrefixes(graph.Prefixes)
+		site.graph.Merge(graph)
+	}
+
+	for i, name := range names {
+		graph := graphs[i]
+		module := &docModule{Name: name, File: relativePath(cfg.RepoRoot, paths[name])}
+		for _, subject := range graph.Subjects() {
+			if !subject.IsIRI() {
+				continue
+			}
+			if hasObject(graph, subject, rdf.RDFType, rdf.OWLNamespace+"Ontology") {
+				module.IRI = subject.Value
+				module.Title = firstObject(graph, subject, dctermsNamespace+"title")
+				module.Description = firstObject(graph, subject, dctermsNamespace+"description")
+				module.Version = firstObject(graph, subject, rdf.OWLNamespace+"versionInfo")
+				for _, imported := range graph.Objects(subject, rdf.OWLNamespace+"imports") {
+					module.Imports = append(module.Imports, imported.Value)
+				}
+				continue
+			}
+			for _, kind := range docKinds {
+				if !hasObject(graph, subject, rdf.RDFType, kind.typ) {
+					continue
+				}
+				if _, ok := site.Terms[subject.Value]; !ok {
+					name := site.ctx.CompactIRI(subject.Value)
+					term := &docTerm{IRI: subject.Value, Name: name, Kind: kind.name, Module: module.Name}
+					site.Terms[subject.Value] = term
+					module.Terms = append(module.Terms, term)
+				}
+				break
+			}
+		}
+		sort.Slice(module.Terms, func(a, b int) bool {
+			if module.Terms[a].Kind != module.Terms[b].Kind {
+				return docKindRank(module.Terms[a].Kind) < docKindRank(module.Terms[b].Kind)
+			}
+			return module.Terms[a].Name < module.Terms[b].Name
+		})
+		site.Modules = append(site.Modules, module)
+	}
+
+	assignDocSlugs(site.Terms)
+	for _, term := range site.Terms {
+		site.describe(term)
+	}
+	for _, module := range site.Modules {
+		for _, term := range module.Terms {
+			module.Mappings = append(module.Mappings, term.Mappings...)
+		}
+	}
+	return site, nil
+}
+
+func docKindRank(kind string) int {
+	for i, k := range docKinds {
+		if k.name == kind {
+			return i
+		}
+	}
+	return len(docKinds)
+}
+
+// assignDocSlugs names the page of every term. Terms whose slugs differ only
+// in case, such as the class dcat:Distribution and the property
+// dcat:distribution, would overwrite each other on a case-insensitive
+// filesystem, so each of them gets a suffix derived from its IRI.
+func assignDocSlugs(terms map[string]*docTerm) {
+	folded := map[string][]*docTerm{}
+	for _, term := range terms {
+		term.Slug = docSlug(term.Name)
+		key := strings.ToLower(term.Slug)
+		folded[key] = append(folded[key], term)
+	}
+	for _, group := range folded {
+		for _, term := range group {
+			if len(group) > 1 {
+				sum := sha256.Sum256([]byte(term.IRI))
+				term.Slug += "-" + hex.EncodeToString(sum[:4])
+			}
+			term.Slug += ".html"
+		}
+	}
+}
+
+// docSlug turns a compact name such as hedera:Token into a file name stem.
+func docSlug(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// describe fills in the statements about a term from the merged graph.
+func (s *docSite) describe(term *docTerm) {
+	subject := rdf.IRI(term.IRI)
+	for _, label := range s.graph.Objects(subject, rdf.RDFSNamespace+"label") {
+		term.Labels = appendUnique(term.Labels, label.Value)
+	}
+	for _, definition := range s.graph.Objects(subject, skosNamespace+"definition") {
+		term.Definitions = appendUnique(term.Definitions, definition.Value)
+	}
+	for _, source := range s.graph.Objects(subject, hederaNamespace+"sourceDocument") {
+		term.Sources = appendUnique(term.Sources, source.Value)
+	}
+	parent := rdf.RDFSNamespace + "subClassOf"
+	if term.Kind != "Class" {
+		parent = rdf.RDFSNamespace + "subPropertyOf"
+	}
+	for _, super := range s.graph.Objects(subject, parent) {
+		if super.IsIRI() {
+			term.Super = append(term.Super, s.link(super.Value))
+		} else if restriction := s.restriction(super); restriction != "" {
+			term.Restrictions = append(term.Restrictions, restriction)
+		}
+	}
+	for _, object := range s.graph.Objects(subject, rdf.RDFSNamespace+"domain") {
+		term.Domain = append(term.Domain, s.link(object.Value))
+	}
+	for _, object := range s.graph.Objects(subject, rdf.RDFSNamespace+"range") {
+		term.Range = append(term.Range, s.link(object.Value))
+	}
+	for _, object := range s.graph.Objects(subject, rdf.OWLNamespace+"inverseOf") {
+		term.Inverse = append(term.Inverse, s.link(object.Value))
+	}
+	for _, triple := range s.graph.Triples {
+		if triple.Object.Value != term.IRI || !triple.Subject.IsIRI() {
+			continue
+		}
+		switch triple.Predicate.Value {
+		case parent:
+			term.Sub = append(term.Sub, s.link(triple.Subject.Value))
+		case rdf.RDFSNamespace + "domain":
+			term.DomainOf = append(term.DomainOf, s.link(triple.Subject.Value))
+		case rdf.RDFSNamespace + "range":
+			term.RangeOf = append(term.RangeOf, s.link(triple.Subject.Value))
+		case rdf.OWLNamespace + "inverseOf":
+			term.Inverse = append(term.Inverse, s.link(triple.Subject.Value))
+		}
+	}
+	for _, predicate := range mappingPredicates {
+		for _, object := range s.graph.Objects(subject, predicate) {
+			if object.IsIRI() && s.external(object.Value) {
+				term.Mappings = append(term.Mappings, docMapping{
+					Term:     s.link(term.IRI),
+					Relation: s.ctx.CompactIRI(predicate),
+					Target:   s.link(object.Value),
+				})
+			}
+		}
+	}
+	for _, links := range [][]docLink{term.Super, term.Sub, term.Domain, term.Range, term.DomainOf, term.RangeOf, term.Inverse} {
+		sortLinks(links)
+	}
+}
+
+// restriction renders an owl:Restriction as "property some Class".
+func (s *docSite) restriction(node rdf.Term) string {
+	if !hasObject(s.graph, node, rdf.RDFType, rdf.OWLNamespace+"Restriction") {
+		return ""
+	}
+	property := s.ctx.CompactIRI(firstObject(s.graph, node, rdf.OWLNamespace+"onProperty"))
+	for _, kind := range []struct{ predicate, word string }{
+		{"someValuesFrom", "some"},
+		{"allValuesFrom", "only"},
+		{"hasValue", "value"},
+		{"minCardinality", "min"},
+		{"maxCardinality", "max"},
+		{"cardinality", "exactly"},
+		{"qualifiedCardinality", "exactly"},
+		{"minQualifiedCardinality", "min"},
+		{"maxQualifiedCardinality", "max"},
+	} {
+		if value := firstObject(s.graph, node, rdf.OWLNamespace+kind.predicate); value != "" {
+			return fmt.Sprintf("%s %s %s", property, kind.word, s.ctx.CompactIRI(value))
+		}
+	}
+	return property
+}
+
+// external reports whether iri belongs to another ontology: it is neither a
+// documented term nor part of the RDF, RDFS, OWL or XSD vocabularies.
+func (s *docSite) external(iri string) bool {
+	if _, ok := s.Terms[iri]; ok {
+		return false
+	}
+	for _, ns := range []string{rdf.RDFNamespace, rdf.RDFSNamespace, rdf.OWLNamespace, rdf.XSDNamespace} {
+		if strings.HasPrefix(iri, ns) {
+			return false
+		}
+	}
+	return true
+}
+
+// link points at the page of a documented term and at the IRI itself
+// otherwise. Hrefs are relative to the site root.
+func (s *docSite) link(iri string) docLink {
+	if term, ok := s.Terms[iri]; ok {
+		title := firstObject(s.graph, rdf.IRI(iri), rdf.RDFSNamespace+"label")
+		return docLink{Label: term.Name, Href: "terms/" + term.Slug, Title: title}
+	}
+	return docLink{Label: s.ctx.CompactIRI(iri), Href: iri}
+}
+
+// linkShapes records, for each term, the node shapes that mention it, either
+// directly or through their property shapes and lists.
+func (s *docSite) linkShapes(cfg *Config) error {
+	shapes, err := cfg.shapePaths()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i, shape := range shapes {
+		source, err := os.ReadFile(shape)
+		if err != nil {
+			return err
+		}
+		graph, err := rdf.ParseTurtle(string(source), rdf.ParseOptions{Source: shape, BlankNodePrefix: fmt.Sprintf("s%d", i)})
+		if err != nil {
+			return err
+		}
+		rel := relativePath(cfg.RepoRoot, shape)
+		for _, subject := range graph.Subjects() {
+			if !subject.IsIRI() || !hasObject(graph, subject, rdf.RDFType, shaclNamespace+"NodeShape") {
+				continue
+			}
+			link := docLink{Label: s.ctx.CompactIRI(subject.Value), Title: rel}
+			for _, iri := range reachableIRIs(graph, subject) {
+				if term, ok := s.Terms[iri]; ok && !hasLink(term.Shapes, link) {
+					term.Shapes = append(term.Shapes, link)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// reachableIRIs lists the IRIs stated about subject, following blank nodes.
+func reachableIRIs(graph *rdf.Graph, subject rdf.Term) []string {
+	var iris []string
+	seen := map[rdf.Term]bool{subject: true}
+	queue := []rdf.Term{subject}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, triple := range graph.Triples {
+			if triple.Subject != node {
+				continue
+			}
+			switch object := triple.Object; {
+			case object.IsIRI():
+				iris = append(iris, object.Value)
+			case object.IsBlankNode() && !seen[object]:
+				seen[object] = true
+				queue = append(queue, object)
+			}
+		}
+	}
+	return iris
+}
+
+var (
+	queryPrefixDecl = regexp.MustCompile(`(?i)PREFIX\s+([A-Za-z][\w.-]*)?:\s*<([^>]*)>`)
+	queryIRIRef     = regexp.MustCompile(`<([^<>\s]+)>`)
+	queryPName      = regexp.MustCompile(`(?:^|[^\w?$:])([A-Za-z][\w.-]*)?:([A-Za-z_][\w-]*(?:\.[\w-]+)*)`)
+)
+
+// linkQueries records, for each term, the competency queries that use it.
+func (s *docSite) linkQueries(cfg *Config) error {
+	queries, err := cfg.queryPaths()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, query := range queries {
+		data, err := os.ReadFile(query)
+		if err != nil {
+			return err
+		}
+		text := stripQueryText(string(data))
+		prefixes := map[string]string{}
+		for _, match := range queryPrefixDecl.FindAllStringSubmatch(text, -1) {
+			prefixes[match[1]] = match[2]
+		}
+		text = queryPrefixDecl.ReplaceAllString(text, " ")
+		used := map[string]bool{}
+		for _, match := range queryIRIRef.FindAllStringSubmatch(text, -1) {
+			used[match[1]] = true
+		}
+		text = queryIRIRef.ReplaceAllString(text, " ")
+		for _, match := range queryPName.FindAllStringSubmatch(text, -1) {
+			if ns, ok := prefixes[match[1]]; ok {
+				used[ns+match[2]] = true
+			}
+		}
+		rel := relativePath(cfg.RepoRoot, query)
+		link := docLink{Label: strings.TrimSuffix(filepath.Base(query), ".rq"), Title: rel}
+		for iri := range used {
+			if term, ok := s.Terms[iri]; ok {
+				term.Queries = append(term.Queries, link)
+			}
+		}
+	}
+	for _, term := range s.Terms {
+		sortLinks(term.Queries)
+	}
+	return nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func hasLink(links []docLink, link docLink) bool {
+	for _, existing := range links {
+		if existing == link {
+			return true
+		}
+	}
+	return false
+}
+
+func sortLinks(links []docLink) {
+	sort.Slice(links, func(i, j int) bool { return links[i].Label < links[j].Label })
+}
+
+func renderDocPage(path, name, root string, data any) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := docTemplates.ExecuteTemplate(file, name, struct {
+		Root string
+		Data any
+	}{root, data}); err != nil {
+		file.Close()
+		return fmt.Errorf("render %s: %w", filepath.Base(path), err)
+	}
+	return file.Close()
+}
+
+// docFuncs are available to the page templates. href resolves a link
+// relative to the page's distance from the site root.
+var docFuncs = template.FuncMap{
+	"href": func(root, href string) string {
+		if strings.Contains(href, "://") {
+			return href
+		}
+		return root + href
+	},
+	"isAlignment": func(module string) bool { return strings.HasPrefix(module, "alignment/") },
+	"links": func(root string, links []docLink) any {
+		return struct {
+			Root  string
+			Links []docLink
+		}{root, links}
+	},
+}
+
+var docTemplates = template.Must(template.New("docs").Funcs(docFuncs).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}} · Bhash ontology</title>
+{{end}}
+{{define "nav"}}<link rel="stylesheet" href="{{.}}style.css">
+</head>
+<body>
+<nav><a href="{{.}}index.html">Modules</a> · <a href="{{.}}mappings.html">Alignment mappings</a></nav>
+<main>
+{{end}}
+{{define "footer"}}</main>
+</body>
+</html>
+{{end}}
+{{define "links"}}{{$root := .Root}}{{range $i, $l := .Links}}{{if $i}}, {{end}}<a href="{{href $root $l.Href}}"{{if $l.Title}} title="{{$l.Title}}"{{end}}>{{$l.Label}}</a>{{end}}{{end}}
+
+{{define "index"}}{{template "header" "Modules"}}{{template "nav" .Root}}<h1>Bhash ontology</h1>
+{{range .Data.Modules}}<section id="{{.Name}}">
+<h2>{{if .Title}}{{.Title}}{{else}}{{.Name}}{{end}}</h2>
+<p class="meta"><code>{{.IRI}}</code> · <code>{{.File}}</code>{{if .Version}} · version {{.Version}}{{end}}</p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Imports}}<p>Imports: {{range $i, $iri := .Imports}}{{if $i}}, {{end}}<code>{{$iri}}</code>{{end}}</p>{{end}}
+{{if .Terms}}<ul>
+{{range .Terms}}<li><a href="terms/{{.Slug}}">{{.Name}}</a> <span class="kind">{{.Kind}}</span>{{with .Labels}} — {{index . 0}}{{end}}</li>
+{{end}}</ul>{{end}}
+</section>
+{{end}}{{template "footer"}}{{end}}
+
+{{define "mappings"}}{{template "header" "Alignment mappings"}}{{template "nav" .Root}}{{$root := .Root}}<h1>Alignment mappings</h1>
+{{range .Data.Modules}}{{if and .Mappings (isAlignment .Name)}}<section id="{{.Name}}">
+<h2>{{if .Title}}{{.Title}}{{else}}{{.Name}}{{end}}</h2>
+<table>
+<tr><th>Term</th><th>Relation</th><th>External term</th></tr>
+{{range .Mappings}}<tr><td><a href="{{href $root .Term.Href}}">{{.Term.Label}}</a></td><td><code>{{.Relation}}</code></td><td><a href="{{href $root .Target.Href}}">{{.Target.Label}}</a></td></tr>
+{{end}}</table>
+</section>
+{{end}}{{end}}{{template "footer"}}{{end}}
+
+{{define "term"}}{{template "header" .Data.Name}}{{template "nav" .Root}}{{$root := .Root}}{{with .Data}}<h1>{{.Name}}</h1>
+<p class="meta">{{.Kind}} in <a href="{{$root}}index.html#{{.Module}}">{{.Module}}</a> · <code>{{.IRI}}</code></p>
+<dl>
+{{if .Labels}}<dt>Labels</dt><dd>{{range $i, $l := .Labels}}{{if $i}}; {{end}}{{$l}}{{end}}</dd>
+{{end}}{{range .Definitions}}<dt>Definition</dt><dd>{{.}}</dd>
+{{end}}{{if .Sources}}<dt>Source documents</dt><dd>{{range $i, $s := .Sources}}{{if $i}}, {{end}}<a href="{{$s}}">{{$s}}</a>{{end}}</dd>
+{{end}}{{if .Super}}<dt>{{if eq .Kind "Class"}}Superclasses{{else}}Superproperties{{end}}</dt><dd>{{template "links" (links $root .Super)}}</dd>
+{{end}}{{if .Sub}}<dt>{{if eq .Kind "Class"}}Subclasses{{else}}Subproperties{{end}}</dt><dd>{{template "links" (links $root .Sub)}}</dd>
+{{end}}{{if .Restrictions}}<dt>Restrictions</dt><dd><ul>{{range .Restrictions}}<li><code>{{.}}</code></li>{{end}}</ul></dd>
+{{end}}{{if .Domain}}<dt>Domain</dt><dd>{{template "links" (links $root .Domain)}}</dd>
+{{end}}{{if .Range}}<dt>Range</dt><dd>{{template "links" (links $root .Range)}}</dd>
+{{end}}{{if .Inverse}}<dt>Inverse of</dt><dd>{{template "links" (links $root .Inverse)}}</dd>
+{{end}}{{if .DomainOf}}<dt>Properties with this domain</dt><dd>{{template "links" (links $root .DomainOf)}}</dd>
+{{end}}{{if .RangeOf}}<dt>Properties with this range</dt><dd>{{template "links" (links $root .RangeOf)}}</dd>
+{{end}}{{if .Mappings}}<dt>Mappings</dt><dd><ul>{{range .Mappings}}<li><code>{{.Relation}}</code> <a href="{{href $root .Target.Href}}">{{.Target.Label}}</a></li>{{end}}</ul></dd>
+{{end}}{{if .Shapes}}<dt>SHACL shapes</dt><dd><ul>{{range .Shapes}}<li>{{.Label}} <span class="meta">({{.Title}})</span></li>{{end}}</ul></dd>
+{{end}}{{if .Queries}}<dt>Competency queries</dt><dd><ul>{{range .Queries}}<li>{{.Label}} <span class="meta">({{.Title}})</span></li>{{end}}</ul></dd>
+{{end}}</dl>
+{{end}}{{template "footer"}}{{end}}
+`))
+
+const docStyle = `body { font-family: system-ui, sans-serif; margin: 0; color: #1f2933; }
+nav { background: #1f2933; padding: 0.75rem 2rem; }
+nav a { color: #f5f7fa; }
+main { max-width: 60rem; margin: 0 auto; padding: 1rem 2rem; }
+.meta, .kind { color: #616e7c; font-size: 0.9em; }
+dt { font-weight: 600; margin-top: 0.75rem; }
+dd { margin-left: 1.5rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #cbd2d9; padding: 0.35rem 0.5rem; text-align: left; }
+`
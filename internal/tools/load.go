@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/hashgraph/bhash/internal/fluree"
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// Transactor submits transactions to a Fluree ledger.
+type Transactor interface {
+	Transact(context.Context, fluree.TransactionRequest) (*fluree.TransactionReceipt, error)
+}
+
+// LoadOptions configures LoadOntology.
+type LoadOptions struct {
+	Ledger string
+	// Modules lists module names relative to ontology/src (for example
+	// "token" or "alignment/aiao"). Empty selects every module.
+	Modules []string
+	// WithExamples also loads the example graphs under ontology/examples that
+	// illustrate each selected module.
+	WithExamples bool
+	Limits       fluree.BatchLimits
+	// DryRun converts and splits the graphs without transacting them.
+	DryRun bool
+	// Progress receives one line per submitted batch. Nil discards progress.
+	Progress io.Writer
+}
+
+// LoadBatch records the outcome of a single submitted transaction.
+type LoadBatch struct {
+	File    string                     `json:"file"`
+	Batch   int                        `json:"batch"`
+	Batches int                        `json:"batches"`
+	Nodes   int                        `json:"nodes"`
+	Bytes   int                        `json:"bytes"`
+	Receipt *fluree.TransactionReceipt `json:"receipt,omitempty"`
+}
+
+// LoadResult summarises a LoadOntology run.
+type LoadResult struct {
+	Ledger  string         `json:"ledger"`
+	Context map[string]any `json:"context"`
+	Files   []string       `json:"files"`
+	Batches []LoadBatch    `json:"batches"`
+}
+
+// LoadOntology converts ontology modules (and optionally their example
+// graphs) from Turtle to JSON-LD compacted against a shared context, splits
+// each file into size-bounded transactions and submits them in order.
+func LoadOntology(ctx context.Context, cfg *Config, client Transactor, opts LoadOptions) (LoadResult, error) {
+	if opts.Ledger == "" {
+		return LoadResult{}, fmt.Errorf("ledger is required")
+	}
+	files, err := cfg.loadFiles(opts.Modules, opts.WithExamples)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
+	}
+
+	graphs := make([]*rdf.Graph, len(files))
+	jsonldContext := rdf.DefaultContext()
+	for i, file := range files {
+		graph, err := rdf.ParseTurtleFile(file)
+		if err != nil {
+			return LoadResult{}, err
+		}
+		graphs[i] = graph
+		jsonldContext.AddPrefixes(graph.Prefixes)
+	}
+
+	result := LoadResult{Ledger: opts.Ledger, Context: jsonldContext.Object()}
+	for i, file := range files {
+		rel, err := filepath.Rel(cfg.RepoRoot, file)
+		if err != nil {
+			rel = file
+		}
+		rel = filepath.ToSlash(rel)
+		result.Files = append(result.Files, rel)
+
+		nodes := rdf.ToJSONLD(graphs[i].Triples, jsonldContext)
+		if len(nodes) == 0 {
+			fmt.Fprintf(progress, "%s: no statements, skipping\n", rel)
+			continue
+		}
+		batches, err := fluree.SplitTransaction(fluree.TransactionRequest{
+			Ledger:  opts.Ledger,
+			Context: result.Context,
+			Insert:  nodes,
+		}, opts.Limits)
+		if err != nil {
+			return result, err
+		}
+		for n, batch := range batches {
+			encoded, err := json.Marshal(batch.Insert)
+			if err != nil {
+				return result, err
+			}
+			record := LoadBatch{File: rel, Batch: n + 1, Batches: len(batches), Nodes: len(batch.Insert), Bytes: len(encoded)}
+			fmt.Fprintf(progress, "%s: batch %d/%d (%d nodes, %d bytes)\n", rel, record.Batch, record.Batches, record.Nodes, record.Bytes)
+			if !opts.DryRun {
+				receipt, err := client.Transact(ctx, batch)
+				if err != nil {
+					return result, fmt.Errorf("transact %s batch %d/%d: %w", rel, record.Batch, record.Batches, err)
+				}
+				record.Receipt = receipt
+			}
+			result.Batches = append(result.Batches, record)
+		}
+	}
+	return result, nil
+}
+
+// loadFiles lists the module files followed by their example graphs.
+func (c *Config) loadFiles(modules []string, withExamples bool) ([]string, error) {
+	names, paths, err := c.resolveModules(modules)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		files = append(files, paths[name])
+	}
+	if !withExamples {
+		return files, nil
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		examples, err := c.exampleFilesForModule(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, example := range examples {
+			if !seen[example] {
+				seen[example] = true
+				files = append(files, example)
+			}
+		}
+	}
+	return files, nil
+}
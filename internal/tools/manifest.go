@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Manifest maps competency queries and SHACL shapes to the datasets they
+// run against and, for queries, to their expected results. It is read from
+// tests/manifest.json.
+type Manifest struct {
+	Queries []ManifestEntry `json:"queries"`
+	Shapes  []ManifestEntry `json:"shapes"`
+}
+
+// ManifestEntry scopes one query or shape file. Paths are relative to the
+// repository root. The datasets of an entry are the listed files plus the
+// example graphs of each ontology module; an entry with neither runs
+// against every dataset.
+type ManifestEntry struct {
+	File     string   `json:"file"`
+	Modules  []string `json:"modules,omitempty"`
+	Datasets []string `json:"datasets,omitempty"`
+	Expected string   `json:"expected,omitempty"`
+}
+
+// ManifestPath returns the location of the dataset manifest.
+func (c *Config) ManifestPath() string {
+	return filepath.Join(c.RepoRoot, "tests", "manifest.json")
+}
+
+// loadManifest reads the manifest. A repository without one yields an
+// empty manifest, so every query and shape runs against every dataset.
+func (c *Config) loadManifest() (*Manifest, error) {
+	data, err := os.ReadFile(c.ManifestPath())
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", relativePath(c.RepoRoot, c.ManifestPath()), err)
+	}
+	return &manifest, nil
+}
+
+// findManifestEntry returns the entry for path, matched on its path
+// relative to the repository root.
+func findManifestEntry(entries []ManifestEntry, cfg *Config, path string) (ManifestEntry, bool) {
+	rel := relativePath(cfg.RepoRoot, path)
+	for _, entry := range entries {
+		if filepath.ToSlash(filepath.Clean(entry.File)) == rel {
+			return entry, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+func (m *Manifest) queryEntry(cfg *Config, query string) (ManifestEntry, bool) {
+	return findManifestEntry(m.Queries, cfg, query)
+}
+
+func (m *Manifest) shapeEntry(cfg *Config, shape string) (ManifestEntry, bool) {
+	return findManifestEntry(m.Shapes, cfg, shape)
+}
+
+// datasets resolves the datasets of an entry to absolute paths. It returns
+// nil when the entry does not scope its datasets.
+func (e ManifestEntry) datasets(cfg *Config) ([]string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, dataset := range e.Datasets {
+		path := filepath.Join(cfg.RepoRoot, filepath.FromSlash(dataset))
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("%s: dataset %s: %w", e.File, dataset, err)
+		}
+		add(path)
+	}
+	if len(e.Modules) > 0 {
+		modules, _, err := cfg.resolveModules(e.Modules)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.File, err)
+		}
+		for _, module := range modules {
+			examples, err := cfg.exampleFilesForModule(module)
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range examples {
+				add(path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// expectedPath returns the fixture a query is compared with: the entry's
+// expected file, or tests/fixtures/results/<query>.csv.
+func (e ManifestEntry) expectedPath(cfg *Config, query string) string {
+	if e.Expected != "" {
+		return filepath.Join(cfg.RepoRoot, filepath.FromSlash(e.Expected))
+	}
+	name := filepath.Base(query)
+	return filepath.Join(cfg.RepoRoot, "tests", "fixtures", "results", strings.TrimSuffix(name, filepath.Ext(name))+".csv")
+}
+
+// ValidateManifest checks that the manifest has exactly one entry for every
+// competency query and shape file, that the files, datasets, modules and
+// expected results it names exist, and that the modules of each query agree
+// with its front matter. It returns every problem found.
+func ValidateManifest(cfg *Config) ([]string, error) {
+	manifest, err := cfg.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	queries, err := cfg.queryPaths()
+	if err != nil {
+		return nil, err
+	}
+	shapes, err := cfg.shapePaths()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var problems []string
+	problems = append(problems, validateManifestEntries(cfg, "query", manifest.Queries, queries)...)
+	problems = append(problems, validateManifestEntries(cfg, "shape", manifest.Shapes, shapes)...)
+	for _, entry := range manifest.Queries {
+		if entry.Expected != "" {
+			if _, err := os.Stat(filepath.Join(cfg.RepoRoot, filepath.FromSlash(entry.Expected))); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: expected results %s do not exist", entry.File, entry.Expected))
+			}
+		}
+		meta, err := readQueryMeta(filepath.Join(cfg.RepoRoot, filepath.FromSlash(entry.File)))
+		if err != nil {
+			continue
+		}
+		if declared := meta["module"]; len(declared) > 0 && !sameStrings(declared, entry.Modules) {
+			problems = append(problems, fmt.Sprintf("%s: front matter modules %s differ from manifest modules %s",
+				entry.File, strings.Join(declared, ", "), strings.Join(entry.Modules, ", ")))
+		}
+	}
+	return problems, nil
+}
+
+func validateManifestEntries(cfg *Config, kind string, entries []ManifestEntry, files []string) []string {
+	var problems []string
+	counts := map[string]int{}
+	for _, entry := range entries {
+		rel := filepath.ToSlash(filepath.Clean(entry.File))
+		counts[rel]++
+		if counts[rel] == 2 {
+			problems = append(problems, fmt.Sprintf("%s: listed more than once", entry.File))
+		}
+		if _, err := os.Stat(filepath.Join(cfg.RepoRoot, filepath.FromSlash(rel))); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s file does not exist", entry.File, kind))
+			continue
+		}
+		if _, err := entry.datasets(cfg); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, file := range files {
+		if rel := relativePath(cfg.RepoRoot, file); counts[rel] == 0 {
+			problems = append(problems, fmt.Sprintf("%s: %s is not in the manifest", rel, kind))
+		}
+	}
+	return problems
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
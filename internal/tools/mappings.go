@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// MappingCoverage counts the classes of one ontology module that a
+// crosswalk row mentions.
+type MappingCoverage struct {
+	Module   string
+	Classes  int
+	Mapped   int
+	Unmapped []string
+}
+
+// MappingCheck is the outcome of CheckMappings. Problems name the crosswalk
+// file and line of each unknown or deprecated term and each missing or
+// malformed source document.
+type MappingCheck struct {
+	Problems []string
+	Coverage []MappingCoverage
+}
+
+// WriteCoverage writes the mapped share of each module's classes and the
+// classes it leaves unmapped.
+func (m *MappingCheck) WriteCoverage(w io.Writer) error {
+	var b strings.Builder
+	classes, mapped, width := 0, 0, len("module")
+	for _, coverage := range m.Coverage {
+		classes += coverage.Classes
+		mapped += coverage.Mapped
+		if len(coverage.Module) > width {
+			width = len(coverage.Module)
+		}
+	}
+	fmt.Fprintf(&b, "coverage: %d/%d classes mapped\n", mapped, classes)
+	for _, coverage := range m.Coverage {
+		fmt.Fprintf(&b, "  %-*s  %d/%d\n", width, coverage.Module, coverage.Mapped, coverage.Classes)
+		if len(coverage.Unmapped) > 0 {
+			fmt.Fprintf(&b, "  %-*s  unmapped: %s\n", width, "", strings.Join(coverage.Unmapped, ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CheckMappings parses every crosswalk under docs/mappings, resolves each
+// term against the modules under ontology/src and checks its source
+// document URL. Coverage counts the classes each module declares, leaving
+// out the vocabulary stubs under ontology/src/imports.
+func CheckMappings(cfg *Config) (*MappingCheck, error) {
+	names, paths, err := cfg.ontologyModules()
+	if err != nil {
+		return nil, err
+	}
+	modules := &rdf.Graph{}
+	seen := map[string]bool{}
+	classes := map[string][]string{}
+	for i, name := range names {
+		source, err := os.ReadFile(paths[name])
+		if err != nil {
+			return nil, err
+		}
+		graph, err := rdf.ParseTurtle(string(source), rdf.ParseOptions{Source: paths[name], BlankNodePrefix: fmt.Sprintf("m%d", i)})
+		if err != nil {
+			return nil, err
+		}
+		modules.Merge(graph)
+		for _, subject := range graph.Subjects() {
+			if !subject.IsIRI() || !hasObject(graph, subject, rdf.RDFType, rdf.OWLNamespace+"Class") {
+				continue
+			}
+			if !seen[subject.Value] {
+				seen[subject.Value] = true
+				if !strings.HasPrefix(name, "imports/") {
+					classes[name] = append(classes[name], subject.Value)
+				}
+			}
+		}
+	}
+	ctx := rdf.DefaultContext()
+	ctx.AddPrefixes(modules.Prefixes)
+
+	crosswalks, err := filepath.Glob(filepath.Join(cfg.RepoRoot, "docs", "mappings", "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNonEmpty(crosswalks, "crosswalk"); err != nil {
+		return nil, err
+	}
+	sort.Strings(crosswalks)
+	check := &MappingCheck{}
+	mapped := map[string]bool{}
+	for _, crosswalk := range crosswalks {
+		problems, terms, err := checkCrosswalk(cfg, crosswalk, modules, ctx)
+		if err != nil {
+			return nil, err
+		}
+		check.Problems = append(check.Problems, problems...)
+		for _, term := range terms {
+			mapped[term] = true
+		}
+	}
+
+	for _, name := range names {
+		if len(classes[name]) == 0 {
+			continue
+		}
+		coverage := MappingCoverage{Module: name, Classes: len(classes[name])}
+		for _, class := range classes[name] {
+			if mapped[class] {
+				coverage.Mapped++
+			} else {
+				coverage.Unmapped = append(coverage.Unmapped, ctx.CompactIRI(class))
+			}
+		}
+		sort.Strings(coverage.Unmapped)
+		check.Coverage = append(check.Coverage, coverage)
+	}
+	return check, nil
+}
+
+// checkCrosswalk validates the rows of one crosswalk, whose columns are
+// Term, Source Document and Notes, and returns the IRIs of the terms it
+// maps.
+func checkCrosswalk(cfg *Config, path string, modules *rdf.Graph, ctx rdf.Context) ([]string, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	rel := relativePath(cfg.RepoRoot, path)
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", rel, err)
+	}
+	termColumn, sourceColumn := -1, -1
+	for i, column := range header {
+		switch strings.TrimSpace(column) {
+		case "Term":
+			termColumn = i
+		case "Source Document":
+			sourceColumn = i
+		}
+	}
+	if termColumn < 0 || sourceColumn < 0 {
+		return []string{fmt.Sprintf("%s: expected Term and Source Document columns", rel)}, nil, nil
+	}
+
+	var problems, terms []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse %s: %w", rel, err)
+		}
+		line, _ := reader.FieldPos(0)
+		at := fmt.Sprintf("%s:%d", rel, line)
+		field := func(i int) string {
+			if i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		term := field(termColumn)
+		switch iri := resolveMappingTerm(term, ctx); {
+		case term == "":
+			problems = append(problems, fmt.Sprintf("%s: missing term", at))
+		case iri == "":
+			problems = append(problems, fmt.Sprintf("%s: term %s has an undeclared prefix", at, term))
+		case len(modules.Objects(rdf.IRI(iri), rdf.RDFType)) == 0:
+			problems = append(problems, fmt.Sprintf("%s: unknown term %s", at, term))
+		default:
+			terms = append(terms, iri)
+			if deprecated := firstObject(modules, rdf.IRI(iri), rdf.OWLNamespace+"deprecated"); deprecated == "true" || deprecated == "1" {
+				problems = append(problems, fmt.Sprintf("%s: term %s is deprecated", at, term))
+			}
+		}
+		switch source := field(sourceColumn); {
+		case source == "":
+			problems = append(problems, fmt.Sprintf("%s: %s has no source document", at, term))
+		case !isSourceURL(source):
+			problems = append(problems, fmt.Sprintf("%s: %s source document %q is not an http(s) URL", at, term, source))
+		}
+	}
+	return problems, terms, nil
+}
+
+// resolveMappingTerm expands a prefixed name or unwraps an IRI. It returns
+// "" for a prefixed name whose prefix the modules do not declare.
+func resolveMappingTerm(term string, ctx rdf.Context) string {
+	if strings.HasPrefix(term, "<") && strings.HasSuffix(term, ">") {
+		return term[1 : len(term)-1]
+	}
+	if strings.Contains(term, "://") {
+		return term
+	}
+	if expanded := ctx.ExpandIRI(term); expanded != term {
+		return expanded
+	}
+	return ""
+}
+
+func isSourceURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
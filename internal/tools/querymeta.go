@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// QueryMeta is the front matter of a competency query: the "# key: value"
+// comment lines before the first statement. Keys are lower-cased and values
+// are split on commas, so "# module: token, core" declares two modules.
+type QueryMeta map[string][]string
+
+func readQueryMeta(path string) (QueryMeta, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	meta := QueryMeta{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(comment, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				meta[key] = append(meta[key], item)
+			}
+		}
+	}
+	return meta, scanner.Err()
+}
+
+// hasTags reports whether meta declares every key/value pair, ignoring
+// case.
+func (m QueryMeta) hasTags(tags [][2]string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, item := range m[strings.ToLower(tag[0])] {
+			if strings.EqualFold(item, tag[1]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTags turns "key=value" selectors into pairs.
+func parseTags(tags []string) ([][2]string, error) {
+	pairs := make([][2]string, 0, len(tags))
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid tag selector %q (want key=value)", tag)
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs, nil
+}
+
+// matchesQueryName reports whether name, with or without its extension,
+// matches one of the glob patterns. No patterns match every name.
+func matchesQueryName(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
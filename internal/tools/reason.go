@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Reasoning outcomes of a module.
+const (
+	ReasonCoherent      = "coherent"
+	ReasonUnsatisfiable = "unsatisfiable"
+	ReasonInconsistent  = "inconsistent"
+)
+
+// ReasonOptions configures RunReason.
+type ReasonOptions struct {
+	// Modules selects ontology modules by name; empty reasons over all.
+	Modules []string
+	// Reasoner is ELK (the default) or HermiT.
+	Reasoner string
+	// Robot runs ROBOT commands. Nil runs the ROBOT installed by bhashctl
+	// install.
+	Robot RobotRunner
+	// Output receives one line per module and the explanations of failing
+	// ones. Nil discards it.
+	Output io.Writer
+}
+
+// ReasonResult is the outcome of reasoning over one module. Unsatisfiable
+// lists the offending classes and Explanation holds ROBOT's explanation of
+// each, naming the axioms responsible.
+type ReasonResult struct {
+	Module        string
+	Status        string
+	Output        string
+	Unsatisfiable []string
+	Explanation   string
+}
+
+var unsatisfiableLine = regexp.MustCompile(`unsatisfiable:\s*<?([^\s>]+)>?`)
+
+// RunReason classifies each module with ROBOT, resolving owl:imports between
+// modules through the import catalog, and writes the inferred ontologies to
+// build/reasoned. A module with unsatisfiable classes or an inconsistent
+// ontology is explained with robot explain; the error lists those modules.
+func RunReason(cfg *Config, opts ReasonOptions) ([]ReasonResult, error) {
+	reasoner, err := reasonerName(opts.Reasoner)
+	if err != nil {
+		return nil, err
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	robot := opts.Robot
+	if robot == nil {
+		robot = cfg.runRobot
+	}
+	names, paths, err := cfg.resolveModules(opts.Modules)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := cfg.writeImportCatalog()
+	if err != nil {
+		return nil, err
+	}
+	reasonedDir := filepath.Join(cfg.BuildDir, "reasoned")
+
+	var results []ReasonResult
+	var failed []string
+	for _, name := range names {
+		result, err := reasonModule(robot, catalog, reasoner, name, paths[name], reasonedDir)
+		if err != nil {
+			fmt.Fprintf(out, "error %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		result.Output = relativePath(cfg.RepoRoot, result.Output)
+		results = append(results, result)
+		switch result.Status {
+		case ReasonCoherent:
+			fmt.Fprintf(out, "ok    %s (%s) -> %s\n", name, reasoner, result.Output)
+		case ReasonUnsatisfiable:
+			fmt.Fprintf(out, "fail  %s: %d unsatisfiable classes: %s\n", name, len(result.Unsatisfiable), strings.Join(result.Unsatisfiable, ", "))
+		case ReasonInconsistent:
+			fmt.Fprintf(out, "fail  %s: ontology is inconsistent\n", name)
+		}
+		if result.Status != ReasonCoherent {
+			failed = append(failed, name)
+			if result.Explanation != "" {
+				fmt.Fprintf(out, "%s\n", indent(result.Explanation, "    "))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("reasoning failed: %s", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// reasonerName canonicalises the reasoner ROBOT should use.
+func reasonerName(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "elk":
+		return "ELK", nil
+	case "hermit":
+		return "HermiT", nil
+	}
+	return "", fmt.Errorf("unsupported reasoner %q (want ELK or HermiT)", name)
+}
+
+// reasonModule runs robot reason over one module. ROBOT exits non-zero for
+// incoherent and inconsistent ontologies; its log tells them apart from
+// other failures, and robot explain then lists the responsible axioms.
+func reasonModule(robot RobotRunner, catalog, reasoner, name, path, reasonedDir string) (ReasonResult, error) {
+	result := ReasonResult{Module: name, Status: ReasonCoherent, Output: filepath.Join(reasonedDir, name+".ttl")}
+	if err := os.MkdirAll(filepath.Dir(result.Output), 0o755); err != nil {
+		return result, err
+	}
+	output, err := robot([]string{"--catalog", catalog, "reason", "--reasoner", reasoner, "--input", path, "--output", result.Output})
+	if err == nil {
+		return result, nil
+	}
+	log := string(output)
+	lower := strings.ToLower(log)
+	mode := ""
+	switch {
+	case strings.Contains(lower, "inconsistent"):
+		result.Status, mode = ReasonInconsistent, "inconsistency"
+	case strings.Contains(lower, "unsatisfiable"):
+		result.Status, mode = ReasonUnsatisfiable, "unsatisfiability"
+		for _, match := range unsatisfiableLine.FindAllStringSubmatch(log, -1) {
+			result.Unsatisfiable = append(result.Unsatisfiable, match[1])
+		}
+		sort.Strings(result.Unsatisfiable)
+	default:
+		return result, fmt.Errorf("robot reason: %w\n%s", err, strings.TrimSpace(log))
+	}
+
+	explanation := filepath.Join(reasonedDir, name+"-explanation.md")
+	args := []string{"--catalog", catalog, "explain", "--reasoner", reasoner, "--input", path, "--mode", mode}
+	if mode == "unsatisfiability" {
+		args = append(args, "--unsatisfiable", "all")
+	}
+	args = append(args, "--explanation", explanation)
+	if output, err := robot(args); err != nil {
+		return result, fmt.Errorf("robot explain: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	text, err := os.ReadFile(explanation)
+	if err != nil {
+		return result, err
+	}
+	result.Explanation = strings.TrimSpace(string(text))
+	return result, nil
+}
+
+func indent(text, prefix string) string {
+	var b strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for first := true; scanner.Scan(); first = false {
+		if !first {
+			b.WriteString("\n")
+		}
+		if line := scanner.Text(); line != "" {
+			b.WriteString(prefix + line)
+		}
+	}
+	return b.String()
+}
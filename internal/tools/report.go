@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// QueryStatus is the outcome of one competency query.
+type QueryStatus string
+
+const (
+	StatusPass             QueryStatus = "pass"
+	StatusFail             QueryStatus = "fail"
+	StatusSkippedNoFixture QueryStatus = "skipped-no-fixture"
+	StatusError            QueryStatus = "error"
+	StatusUpdated          QueryStatus = "updated"
+)
+
+// RowDiff lists the CSV rows that differ between the expected fixture and
+// the actual results. Reordered is set when both hold the same rows but the
+// query's ORDER BY requires a different order; BlankNodes when the rows
+// only differ in blank node labels that cannot be mapped one-to-one.
+type RowDiff struct {
+	Missing    []string `json:"missing,omitempty"`
+	Unexpected []string `json:"unexpected,omitempty"`
+	Reordered  bool     `json:"reordered,omitempty"`
+	BlankNodes bool     `json:"blankNodes,omitempty"`
+}
+
+func (d *RowDiff) summary() string {
+	if d.Reordered {
+		return "rows match the fixture but are in a different order"
+	}
+	if d.BlankNodes {
+		return "rows match the fixture but blank nodes do not map one-to-one"
+	}
+	return fmt.Sprintf("%d expected rows missing, %d unexpected rows", len(d.Missing), len(d.Unexpected))
+}
+
+// QueryResult is the outcome of one query in a SparqlReport.
+type QueryResult struct {
+	Name    string      `json:"name"`
+	Query   string      `json:"query"`
+	Fixture string      `json:"fixture,omitempty"`
+	Status  QueryStatus `json:"status"`
+	Seconds float64     `json:"seconds"`
+	Rows    int         `json:"rows,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Diff    *RowDiff    `json:"diff,omitempty"`
+}
+
+// SparqlReport collects the outcomes of a RunSparql run.
+type SparqlReport struct {
+	Backend string        `json:"backend"`
+	Started time.Time     `json:"started"`
+	Seconds float64       `json:"seconds"`
+	Results []QueryResult `json:"results"`
+}
+
+// Counts returns the number of queries with each status.
+func (r *SparqlReport) Counts() map[QueryStatus]int {
+	counts := map[QueryStatus]int{StatusPass: 0, StatusFail: 0, StatusSkippedNoFixture: 0, StatusError: 0, StatusUpdated: 0}
+	for _, result := range r.Results {
+		counts[result.Status]++
+	}
+	return counts
+}
+
+// Failures returns the names of the queries that failed or errored.
+func (r *SparqlReport) Failures() []string {
+	var names []string
+	for _, result := range r.Results {
+		if result.Status == StatusFail || result.Status == StatusError {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+// WriteJSON writes the report with a summary of the status counts.
+func (r *SparqlReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		*SparqlReport
+		Summary map[QueryStatus]int `json:"summary"`
+	}{r, r.Counts()})
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as a JUnit XML document with one test case
+// per query, so CI systems can annotate individual failures.
+func (r *SparqlReport) WriteJUnit(w io.Writer) error {
+	counts := r.Counts()
+	suite := junitSuite{
+		Name:      "sparql." + r.Backend,
+		Tests:     len(r.Results),
+		Failures:  counts[StatusFail],
+		Errors:    counts[StatusError],
+		Skipped:   counts[StatusSkippedNoFixture],
+		Time:      seconds(r.Seconds),
+		Timestamp: r.Started.Format(time.RFC3339),
+	}
+	for _, result := range r.Results {
+		testCase := junitCase{Name: result.Name, Classname: suite.Name, File: result.Query, Time: seconds(result.Seconds)}
+		switch result.Status {
+		case StatusFail:
+			testCase.Failure = &junitMessage{Message: result.Message, Type: "mismatch", Body: result.Diff.text()}
+		case StatusError:
+			testCase.Error = &junitMessage{Message: result.Message, Type: "error"}
+		case StatusSkippedNoFixture:
+			testCase.Skipped = &junitMessage{Message: result.Message}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteText writes one line per query followed by the row diff of each
+// failure or fixture update.
+func (r *SparqlReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, result := range r.Results {
+		fmt.Fprintf(&b, "%-20s %s (%ss)", result.Status, result.Name, seconds(result.Seconds))
+		if result.Message != "" {
+			fmt.Fprintf(&b, ": %s", result.Message)
+		}
+		b.WriteString("\n")
+		if result.Status == StatusFail || result.Status == StatusUpdated {
+			b.WriteString(result.Diff.text())
+		}
+	}
+	counts := r.Counts()
+	fmt.Fprintf(&b, "%d passed, %d failed, %d errors, %d skipped (no fixture)",
+		counts[StatusPass], counts[StatusFail], counts[StatusError], counts[StatusSkippedNoFixture])
+	if counts[StatusUpdated] > 0 {
+		fmt.Fprintf(&b, ", %d fixtures updated", counts[StatusUpdated])
+	}
+	fmt.Fprintf(&b, " in %ss\n", seconds(r.Seconds))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (d *RowDiff) text() string {
+	if d == nil {
+		return ""
+	}
+	var b strings.Builder
+	if d.Reordered {
+		b.WriteString("  rows are in a different order\n")
+	}
+	if d.BlankNodes {
+		b.WriteString("  blank nodes do not map one-to-one\n")
+	}
+	for _, line := range d.Missing {
+		fmt.Fprintf(&b, "  - %s\n", line)
+	}
+	for _, line := range d.Unexpected {
+		fmt.Fprintf(&b, "  + %s\n", line)
+	}
+	return b.String()
+}
+
+func seconds(value float64) string {
+	return fmt.Sprintf("%.3f", value)
+}
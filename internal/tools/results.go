@@ -0,0 +1,510 @@
+package tools
+
+import (
+	"encoding/csv"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const xsdNamespace = "http://www.w3.org/2001/XMLSchema#"
+
+// termKind classifies a cell of a CSV query result.
+type termKind int
+
+const (
+	// termPlain is an unannotated cell. SPARQL CSV output drops term types,
+	// so fixtures and ROBOT output are made of plain cells.
+	termPlain termKind = iota
+	termIRI
+	termBlank
+	termLiteral
+)
+
+// rdfTerm is a parsed result cell. canon holds the value-space form used
+// for comparison: numbers as exact rationals and dateTimes in UTC.
+type rdfTerm struct {
+	kind     termKind
+	value    string
+	datatype string
+	lang     string
+	canon    string
+}
+
+// resultRow is one solution of a result set. line keeps the CSV text of
+// the row for diffs.
+type resultRow struct {
+	terms []rdfTerm
+	line  string
+}
+
+// resultSet is a parsed CSV query result.
+type resultSet struct {
+	vars []string
+	rows []resultRow
+}
+
+func readResultSet(path string) (*resultSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	set := &resultSet{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return set, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if set.vars == nil {
+			set.vars = record
+			continue
+		}
+		row := resultRow{terms: make([]rdfTerm, len(record)), line: csvLine(record)}
+		for i, cell := range record {
+			row.terms[i] = parseTerm(cell)
+		}
+		set.rows = append(set.rows, row)
+	}
+}
+
+func csvLine(record []string) string {
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	_ = writer.Write(record)
+	writer.Flush()
+	return strings.TrimRight(b.String(), "\r\n")
+}
+
+// alignTo reorders the columns of s to follow vars. It reports false when
+// the two headers do not name the same variables.
+func (s *resultSet) alignTo(vars []string) bool {
+	if len(s.vars) != len(vars) {
+		return false
+	}
+	index := make(map[string]int, len(s.vars))
+	for i, name := range s.vars {
+		index[name] = i
+	}
+	if len(index) != len(vars) {
+		return false
+	}
+	order := make([]int, len(vars))
+	for i, name := range vars {
+		j, ok := index[name]
+		if !ok {
+			return false
+		}
+		order[i] = j
+	}
+	for r, row := range s.rows {
+		if len(row.terms) != len(order) {
+			continue
+		}
+		terms := make([]rdfTerm, len(order))
+		for i, j := range order {
+			terms[i] = row.terms[j]
+		}
+		s.rows[r].terms = terms
+	}
+	s.vars = append([]string(nil), vars...)
+	return true
+}
+
+// parseTerm reads a cell written either as plain SPARQL CSV or in the
+// N-Triples-like form some engines emit: <iri>, _:label, "lex"@lang and
+// "lex"^^datatype.
+func parseTerm(cell string) rdfTerm {
+	switch {
+	case strings.HasPrefix(cell, "_:"):
+		return rdfTerm{kind: termBlank, value: cell[2:]}
+	case len(cell) >= 2 && cell[0] == '<' && cell[len(cell)-1] == '>':
+		value := cell[1 : len(cell)-1]
+		return rdfTerm{kind: termIRI, value: value, canon: value}
+	case strings.HasPrefix(cell, `"`):
+		if end := strings.LastIndex(cell, `"`); end > 0 {
+			lexical, suffix := cell[1:end], cell[end+1:]
+			switch {
+			case suffix == "":
+				return literalTerm(lexical, xsdNamespace+"string", "")
+			case strings.HasPrefix(suffix, "@"):
+				return literalTerm(lexical, "", strings.ToLower(suffix[1:]))
+			case strings.HasPrefix(suffix, "^^"):
+				return literalTerm(lexical, expandDatatype(suffix[2:]), "")
+			}
+		}
+	}
+	return rdfTerm{kind: termPlain, value: cell, canon: plainCanon(cell)}
+}
+
+func literalTerm(lexical, datatype, lang string) rdfTerm {
+	term := rdfTerm{kind: termLiteral, value: lexical, datatype: datatype, lang: lang, canon: lexical}
+	switch datatypeClass(datatype) {
+	case "numeric":
+		if canon, ok := canonicalNumber(lexical); ok {
+			term.canon = canon
+		}
+	case "dateTime":
+		if canon, ok := canonicalDateTime(lexical); ok {
+			term.canon = canon
+		}
+	case xsdNamespace + "boolean":
+		switch lexical {
+		case "1":
+			term.canon = "true"
+		case "0":
+			term.canon = "false"
+		}
+	}
+	return term
+}
+
+func expandDatatype(datatype string) string {
+	if strings.HasPrefix(datatype, "<") && strings.HasSuffix(datatype, ">") {
+		return datatype[1 : len(datatype)-1]
+	}
+	if local, ok := strings.CutPrefix(datatype, "xsd:"); ok {
+		return xsdNamespace + local
+	}
+	return datatype
+}
+
+var numericDatatypes = map[string]bool{
+	"integer": true, "decimal": true, "double": true, "float": true,
+	"int": true, "long": true, "short": true, "byte": true,
+	"nonNegativeInteger": true, "positiveInteger": true, "negativeInteger": true, "nonPositiveInteger": true,
+	"unsignedLong": true, "unsignedInt": true, "unsignedShort": true, "unsignedByte": true,
+}
+
+// datatypeClass groups datatypes whose values compare with each other, so
+// that "42"^^xsd:integer equals "42.0"^^xsd:decimal.
+func datatypeClass(datatype string) string {
+	local, ok := strings.CutPrefix(datatype, xsdNamespace)
+	switch {
+	case ok && numericDatatypes[local]:
+		return "numeric"
+	case ok && (local == "dateTime" || local == "dateTimeStamp"):
+		return "dateTime"
+	}
+	return datatype
+}
+
+var numericPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+func canonicalNumber(lexical string) (string, bool) {
+	if !numericPattern.MatchString(lexical) {
+		return "", false
+	}
+	value, ok := new(big.Rat).SetString(lexical)
+	if !ok {
+		return "", false
+	}
+	return "n:" + value.RatString(), true
+}
+
+func canonicalDateTime(lexical string) (string, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, lexical); err == nil {
+		return "t:" + t.UTC().Format(time.RFC3339Nano), true
+	}
+	if t, err := time.Parse("2006-01-02T15:04:05.999999999", lexical); err == nil {
+		return "t:" + t.Format("2006-01-02T15:04:05.999999999"), true
+	}
+	return "", false
+}
+
+func plainCanon(value string) string {
+	if canon, ok := canonicalNumber(value); ok {
+		return canon
+	}
+	if canon, ok := canonicalDateTime(value); ok {
+		return canon
+	}
+	return value
+}
+
+// matches compares two non-blank terms. A plain cell carries no type, so it
+// matches any term with the same value.
+func (t rdfTerm) matches(o rdfTerm) bool {
+	switch {
+	case t.kind == termBlank || o.kind == termBlank:
+		return t.kind == o.kind
+	case t.kind == termPlain || o.kind == termPlain:
+		return t.canon == o.canon
+	case t.kind != o.kind:
+		return false
+	case t.kind == termIRI:
+		return t.value == o.value
+	}
+	return t.lang == o.lang && datatypeClass(t.datatype) == datatypeClass(o.datatype) && t.canon == o.canon
+}
+
+func rowsMatch(expected, actual resultRow) bool {
+	if len(expected.terms) != len(actual.terms) {
+		return false
+	}
+	for i := range expected.terms {
+		if !expected.terms[i].matches(actual.terms[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasBlankNodes(rows []resultRow) bool {
+	for _, row := range rows {
+		for _, term := range row.terms {
+			if term.kind == termBlank {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// blankMapping is a bijection between expected and actual blank node
+// labels, built up as rows are paired.
+type blankMapping struct {
+	forward  map[string]string
+	backward map[string]string
+}
+
+func newBlankMapping() *blankMapping {
+	return &blankMapping{forward: map[string]string{}, backward: map[string]string{}}
+}
+
+// extend adds the blank node pairs of a matched row. It returns the labels
+// it added so that a failed search can retract them.
+func (m *blankMapping) extend(expected, actual resultRow) ([]string, bool) {
+	var added []string
+	for i, term := range expected.terms {
+		if term.kind != termBlank {
+			continue
+		}
+		other := actual.terms[i].value
+		if mapped, seen := m.forward[term.value]; seen {
+			if mapped == other {
+				continue
+			}
+			m.retract(added)
+			return nil, false
+		}
+		if _, taken := m.backward[other]; taken {
+			m.retract(added)
+			return nil, false
+		}
+		m.forward[term.value] = other
+		m.backward[other] = term.value
+		added = append(added, term.value)
+	}
+	return added, true
+}
+
+func (m *blankMapping) retract(added []string) {
+	for _, label := range added {
+		delete(m.backward, m.forward[label])
+		delete(m.forward, label)
+	}
+}
+
+// matchOrdered pairs rows by position.
+func matchOrdered(expected, actual []resultRow) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	mapping := newBlankMapping()
+	for i := range expected {
+		if !rowsMatch(expected[i], actual[i]) {
+			return false
+		}
+		if _, ok := mapping.extend(expected[i], actual[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// blankSearchBudget caps the number of row pairings tried while looking for
+// a consistent blank node mapping.
+const blankSearchBudget = 100000
+
+// matchUnordered compares rows as multisets. Without blank nodes this is a
+// bipartite matching; with them, a search for a pairing whose blank node
+// labels map one-to-one.
+func matchUnordered(expected, actual []resultRow) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	if !hasBlankNodes(expected) && !hasBlankNodes(actual) {
+		matched, _ := maximumMatching(expected, actual)
+		return matched == len(expected)
+	}
+	candidates := rowCandidates(expected, actual)
+	used := make([]bool, len(actual))
+	mapping := newBlankMapping()
+	budget := blankSearchBudget
+	var search func(int) bool
+	search = func(i int) bool {
+		if i == len(expected) {
+			return true
+		}
+		for _, j := range candidates[i] {
+			if used[j] || budget == 0 {
+				continue
+			}
+			budget--
+			added, ok := mapping.extend(expected[i], actual[j])
+			if !ok {
+				continue
+			}
+			used[j] = true
+			if search(i + 1) {
+				return true
+			}
+			used[j] = false
+			mapping.retract(added)
+		}
+		return false
+	}
+	return search(0)
+}
+
+func rowCandidates(expected, actual []resultRow) [][]int {
+	candidates := make([][]int, len(expected))
+	for i := range expected {
+		for j := range actual {
+			if rowsMatch(expected[i], actual[j]) {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+	}
+	return candidates
+}
+
+// maximumMatching pairs expected rows with matching actual rows, ignoring
+// blank node consistency, and returns the size of the largest pairing and
+// the actual row chosen for each expected row (-1 when unpaired).
+func maximumMatching(expected, actual []resultRow) (int, []int) {
+	candidates := rowCandidates(expected, actual)
+	owner := make([]int, len(actual))
+	for j := range owner {
+		owner[j] = -1
+	}
+	var augment func(int, []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for _, j := range candidates[i] {
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			if owner[j] < 0 || augment(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	matched := 0
+	for i := range expected {
+		if augment(i, make([]bool, len(actual))) {
+			matched++
+		}
+	}
+	pairs := make([]int, len(expected))
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	for j, i := range owner {
+		if i >= 0 {
+			pairs[i] = j
+		}
+	}
+	return matched, pairs
+}
+
+// compareRows compares result rows, by position when ordered is set and
+// as a multiset otherwise. It returns nil when the rows match.
+func compareRows(expected, actual []resultRow, ordered bool) *RowDiff {
+	unordered := matchUnordered(expected, actual)
+	if ordered && matchOrdered(expected, actual) || !ordered && unordered {
+		return nil
+	}
+	diff := &RowDiff{}
+	_, pairs := maximumMatching(expected, actual)
+	paired := make([]bool, len(actual))
+	for i, j := range pairs {
+		if j < 0 {
+			diff.Missing = append(diff.Missing, expected[i].line)
+			continue
+		}
+		paired[j] = true
+	}
+	for j, row := range actual {
+		if !paired[j] {
+			diff.Unexpected = append(diff.Unexpected, row.line)
+		}
+	}
+	if len(diff.Missing) == 0 && len(diff.Unexpected) == 0 {
+		diff.Reordered = unordered
+		diff.BlankNodes = !unordered
+	}
+	return diff
+}
+
+var orderByPattern = regexp.MustCompile(`(?i)\bORDER\s+BY\b`)
+
+// queryIsOrdered reports whether the outermost query has an ORDER BY
+// clause, that is one after the last closing brace once comments and
+// string literals are removed.
+func queryIsOrdered(query string) bool {
+	stripped := stripQueryText(query)
+	if i := strings.LastIndex(stripped, "}"); i >= 0 {
+		stripped = stripped[i+1:]
+	}
+	return orderByPattern.MatchString(stripped)
+}
+
+func stripQueryText(query string) string {
+	var b strings.Builder
+	var quote byte
+	inIRI := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+				b.WriteByte(c)
+			}
+			continue
+		case inIRI:
+			inIRI = c != '>' && c != ' ' && c != '\t' && c != '\n'
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '<':
+			inIRI = true
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			c = '\n'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
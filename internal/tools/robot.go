@@ -3,12 +3,63 @@ package tools
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
 )
 
+// RobotRunner runs ROBOT with args and returns its combined output.
+type RobotRunner func(args []string) ([]byte, error)
+
+// runRobot runs the ROBOT installed by bhashctl install.
+func (c *Config) runRobot(args []string) ([]byte, error) {
+	return exec.Command(c.RobotExecutable(), args...).CombinedOutput()
+}
+
+// writeImportCatalog writes build/catalog-v001.xml, mapping the IRI of every
+// ontology under ontology/src to its file so owl:imports between modules
+// resolve locally, and returns its path.
+func (c *Config) writeImportCatalog() (string, error) {
+	_, modules, err := c.ontologyModules()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b bytes.Buffer
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	b.WriteString("<catalog prefer=\"public\" xmlns=\"urn:oasis:names:tc:entity:xmlns:xml:catalog\">\n")
+	for _, name := range names {
+		graph, err := rdf.ParseTurtleFile(modules[name])
+		if err != nil {
+			return "", err
+		}
+		abs, err := filepath.Abs(modules[name])
+		if err != nil {
+			return "", err
+		}
+		for _, subject := range graph.Subjects() {
+			if subject.IsIRI() && hasObject(graph, subject, rdf.RDFType, "http://www.w3.org/2002/07/owl#Ontology") {
+				fmt.Fprintf(&b, "  <uri name=\"%s\" uri=\"file:%s\"/>\n", html.EscapeString(subject.Value), html.EscapeString(filepath.ToSlash(abs)))
+			}
+		}
+	}
+	b.WriteString("</catalog>\n")
+	if err := os.MkdirAll(c.BuildDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(c.BuildDir, "catalog-v001.xml")
+	return path, os.WriteFile(path, b.Bytes(), 0o644)
+}
+
 func mergeWithRobot(robot string, inputs []string, output string) error {
 	if len(inputs) == 0 {
 		return fmt.Errorf("merge: no input files provided")
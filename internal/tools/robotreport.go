@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReportIssue is one row of a ROBOT report. Level is ERROR, WARN or INFO.
+type ReportIssue struct {
+	Module   string `json:"module"`
+	Level    string `json:"level"`
+	Rule     string `json:"rule"`
+	Subject  string `json:"subject"`
+	Property string `json:"property,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+func (i ReportIssue) String() string {
+	text := fmt.Sprintf("%s %s %s", i.Level, i.Rule, i.Subject)
+	if i.Property != "" {
+		text += " " + i.Property
+	}
+	if i.Value != "" {
+		text += " " + i.Value
+	}
+	return text
+}
+
+// ReportOptions configures RunReport.
+type ReportOptions struct {
+	// Modules selects ontology modules by name; empty reports on all.
+	Modules []string
+	// Profile is passed to robot report --profile and replaces ROBOT's
+	// default profile wholesale. Empty runs the default profile.
+	Profile string
+	// Levels changes the level of individual rules after ROBOT reports them:
+	// one tab-separated LEVEL and rule name per line. Empty selects
+	// tests/report/levels.txt when it exists.
+	Levels string
+	// Baseline lists accepted issues. Empty selects
+	// tests/report/baseline.json; a missing file accepts nothing.
+	Baseline string
+	// UpdateBaseline rewrites the baseline with the issues found, so they
+	// are accepted from now on.
+	UpdateBaseline bool
+	// Robot runs ROBOT commands. Nil runs the ROBOT installed by bhashctl
+	// install.
+	Robot RobotRunner
+	// Output receives the per-module summary and the new issues. Nil
+	// discards it.
+	Output io.Writer
+}
+
+// ModuleReport holds the issues ROBOT reported for one module. New lists
+// the issues missing from the baseline.
+type ModuleReport struct {
+	Module string
+	Report string
+	Issues []ReportIssue
+	New    []ReportIssue
+}
+
+// Count returns the number of issues at level, counting only new ones when
+// onlyNew is set.
+func (m ModuleReport) Count(level string, onlyNew bool) int {
+	issues := m.Issues
+	if onlyNew {
+		issues = m.New
+	}
+	n := 0
+	for _, issue := range issues {
+		if issue.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// ReportLevelsPath returns the default rule level overrides.
+func (c *Config) ReportLevelsPath() string {
+	return filepath.Join(c.RepoRoot, "tests", "report", "levels.txt")
+}
+
+// ReportBaselinePath returns the default baseline of accepted report issues.
+func (c *Config) ReportBaselinePath() string {
+	return filepath.Join(c.RepoRoot, "tests", "report", "baseline.json")
+}
+
+// RunReport runs robot report over each module, writing the TSV reports to
+// build/reports, re-levels the issues whose rules the levels file lists and
+// compares them with the baseline. Overriding levels after the report keeps
+// every other rule of ROBOT's default profile in force. Accepted issues
+// are matched on module, level, rule, subject, property and value; a
+// baseline entry is used up by the first matching issue. The error reports
+// new ERROR issues only.
+func RunReport(cfg *Config, opts ReportOptions) ([]ModuleReport, error) {
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	robot := opts.Robot
+	if robot == nil {
+		robot = cfg.runRobot
+	}
+	levelsPath := opts.Levels
+	if levelsPath == "" {
+		if _, err := os.Stat(cfg.ReportLevelsPath()); err == nil {
+			levelsPath = cfg.ReportLevelsPath()
+		}
+	}
+	levels, err := readReportLevels(levelsPath)
+	if err != nil {
+		return nil, err
+	}
+	baselinePath := opts.Baseline
+	if baselinePath == "" {
+		baselinePath = cfg.ReportBaselinePath()
+	}
+	baseline, err := readReportBaseline(baselinePath)
+	if err != nil {
+		return nil, err
+	}
+	names, paths, err := cfg.resolveModules(opts.Modules)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := cfg.writeImportCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := map[ReportIssue]int{}
+	for _, issue := range baseline {
+		accepted[issue]++
+	}
+	var reports []ModuleReport
+	for _, name := range names {
+		reportPath := filepath.Join(cfg.BuildDir, "reports", name+"-report.tsv")
+		if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err != nil {
+			return nil, err
+		}
+		args := []string{"--catalog", catalog, "report", "--input", paths[name], "--fail-on", "none", "--print", "0"}
+		if opts.Profile != "" {
+			args = append(args, "--profile", opts.Profile)
+		}
+		args = append(args, "--output", reportPath)
+		if output, err := robot(args); err != nil {
+			return nil, fmt.Errorf("robot report (%s): %w\n%s", name, err, strings.TrimSpace(string(output)))
+		}
+		issues, err := readRobotReport(reportPath, name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range issues {
+			if level, ok := levels[issues[i].Rule]; ok {
+				issues[i].Level = level
+			}
+		}
+		report := ModuleReport{Module: name, Report: relativePath(cfg.RepoRoot, reportPath), Issues: issues}
+		for _, issue := range issues {
+			if accepted[issue] > 0 {
+				accepted[issue]--
+				continue
+			}
+			report.New = append(report.New, issue)
+		}
+		reports = append(reports, report)
+	}
+
+	writeReportSummary(out, reports)
+	if opts.UpdateBaseline {
+		if err := writeReportBaseline(baselinePath, baseline, reports); err != nil {
+			return reports, err
+		}
+		fmt.Fprintf(out, "baseline written to %s\n", relativePath(cfg.RepoRoot, baselinePath))
+		return reports, nil
+	}
+	var failed []string
+	for _, report := range reports {
+		if n := report.Count("ERROR", true); n > 0 {
+			failed = append(failed, fmt.Sprintf("%s (%d)", report.Module, n))
+		}
+	}
+	if len(failed) > 0 {
+		return reports, fmt.Errorf("robot report found new errors: %s", strings.Join(failed, ", "))
+	}
+	return reports, nil
+}
+
+// writeReportSummary prints one line of counts per module, followed by the
+// new issues of the modules that have any.
+func writeReportSummary(w io.Writer, reports []ModuleReport) {
+	width := len("module")
+	for _, report := range reports {
+		if len(report.Module) > width {
+			width = len(report.Module)
+		}
+	}
+	fmt.Fprintf(w, "%-*s  %6s  %8s  %4s  %s\n", width, "module", "errors", "warnings", "info", "new")
+	for _, report := range reports {
+		fmt.Fprintf(w, "%-*s  %6d  %8d  %4d  %d\n", width, report.Module,
+			report.Count("ERROR", false), report.Count("WARN", false), report.Count("INFO", false), len(report.New))
+	}
+	for _, report := range reports {
+		if len(report.New) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "new in %s (%s):\n", report.Module, report.Report)
+		for _, issue := range report.New {
+			fmt.Fprintf(w, "  %s\n", issue)
+		}
+	}
+}
+
+// readRobotReport parses the TSV written by robot report: a header row
+// followed by Level, Rule Name, Subject, Property and Value columns.
+func readRobotReport(path, module string) ([]ReportIssue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	var issues []ReportIssue
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(record[0], "Level") {
+			continue
+		}
+		for len(record) < 5 {
+			record = append(record, "")
+		}
+		issues = append(issues, ReportIssue{
+			Module:   module,
+			Level:    strings.ToUpper(strings.TrimSpace(record[0])),
+			Rule:     strings.TrimSpace(record[1]),
+			Subject:  strings.TrimSpace(record[2]),
+			Property: strings.TrimSpace(record[3]),
+			Value:    strings.TrimSpace(record[4]),
+		})
+	}
+	return issues, nil
+}
+
+// readReportLevels parses rule level overrides: lines of a level (ERROR,
+// WARN or INFO), a tab and a rule name. Blank lines and lines starting with
+// # are ignored. An empty path overrides nothing.
+func readReportLevels(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	levels := map[string]string{}
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		level, rule, ok := strings.Cut(line, "\t")
+		level = strings.ToUpper(strings.TrimSpace(level))
+		if !ok || strings.TrimSpace(rule) == "" || (level != "ERROR" && level != "WARN" && level != "INFO") {
+			return nil, fmt.Errorf("%s:%d: expected LEVEL<tab>rule", filepath.Base(path), n+1)
+		}
+		levels[strings.TrimSpace(rule)] = level
+	}
+	return levels, nil
+}
+
+func readReportBaseline(path string) ([]ReportIssue, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var baseline []ReportIssue
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return baseline, nil
+}
+
+// writeReportBaseline replaces the entries of the reported modules with
+// their current issues and keeps those of modules not reported on.
+func writeReportBaseline(path string, baseline []ReportIssue, reports []ModuleReport) error {
+	reported := map[string]bool{}
+	var issues []ReportIssue
+	for _, report := range reports {
+		reported[report.Module] = true
+		issues = append(issues, report.Issues...)
+	}
+	for _, issue := range baseline {
+		if !reported[issue.Module] {
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Module != issues[j].Module {
+			return issues[i].Module < issues[j].Module
+		}
+		return issues[i].String() < issues[j].String()
+	})
+	if issues == nil {
+		issues = []ReportIssue{}
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
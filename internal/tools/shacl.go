@@ -1,71 +1,184 @@
 package tools
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
 )
 
-func RunShacl(cfg *Config) error {
-	datasets, err := cfg.datasetPaths()
-	if err != nil {
-		return err
+// shaclGroup is a set of shapes validated against the same datasets.
+type shaclGroup struct {
+	datasets []string
+	shapes   []string
+	scoped   bool
+}
+
+// ShaclOptions configures RunShacl.
+type ShaclOptions struct {
+	// Validator produces validation reports. Nil selects the TopBraid
+	// validator installed by bhashctl install.
+	Validator ShaclValidator
+	// FailOn is the lowest severity that fails the run: Violation, Warning
+	// or Info. Empty selects Violation.
+	FailOn string
+}
+
+// RunShacl validates the datasets against the shapes and returns the parsed
+// results. Each shape runs against the datasets of its manifest entry;
+// shapes sharing a dataset set are validated together, and shapes without
+// an entry run against every dataset. The raw report of each group with
+// results is written under build/reports. The error reports results at or
+// above opts.FailOn; the report is returned with it.
+func RunShacl(cfg *Config, opts ShaclOptions) (*ShaclReport, error) {
+	validate := opts.Validator
+	if validate == nil {
+		validate = topBraidValidator
 	}
-	if err := ensureNonEmpty(datasets, "dataset"); err != nil {
-		return err
+	threshold := "Violation"
+	if opts.FailOn != "" {
+		var err error
+		if threshold, err = ParseSeverity(opts.FailOn); err != nil {
+			return nil, err
+		}
 	}
 	shapes, err := cfg.shapePaths()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := ensureNonEmpty(shapes, "shape"); err != nil {
-		return err
+		return nil, err
+	}
+	manifest, err := cfg.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := shaclGroups(cfg, manifest, shapes)
+	if err != nil {
+		return nil, err
 	}
 
+	if err := os.MkdirAll(cfg.BuildDir, 0o755); err != nil {
+		return nil, err
+	}
 	tempDir, err := os.MkdirTemp(cfg.BuildDir, "shacl-")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer os.RemoveAll(tempDir)
 
-	dataFile := filepath.Join(tempDir, "data.ttl")
-	if err := mergeWithRobot(cfg.RobotExecutable(), datasets, dataFile); err != nil {
-		return err
+	reportDir := filepath.Join(cfg.BuildDir, "reports")
+	if err := os.MkdirAll(reportDir, 0o755); err != nil {
+		return nil, err
 	}
 
-	shapesFile := filepath.Join(tempDir, "shapes.ttl")
-	if err := mergeWithRobot(cfg.RobotExecutable(), shapes, shapesFile); err != nil {
-		return err
+	report := &ShaclReport{Results: []ShaclResult{}, prefixes: rdf.DefaultContext()}
+	for i, group := range groups {
+		reportPath := filepath.Join(reportDir, "shacl-report.ttl")
+		if group.scoped {
+			reportPath = filepath.Join(reportDir, "shacl-report-"+shapeStems(group.shapes)+".ttl")
+		}
+		results, err := validateShaclGroup(cfg, group, validate, filepath.Join(tempDir, fmt.Sprint(i)), reportPath, report.prefixes)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			report.Reports = append(report.Reports, relativePath(cfg.RepoRoot, reportPath))
+		}
+		report.Results = append(report.Results, results...)
+	}
+	if n := report.AtOrAbove(threshold); n > 0 {
+		return report, fmt.Errorf("shacl validation failed: %d results at or above %s; report written to %s",
+			n, threshold, strings.Join(report.Reports, ", "))
 	}
+	return report, nil
+}
 
-	reportDir := filepath.Join(cfg.BuildDir, "reports")
-	if err := os.MkdirAll(reportDir, 0o755); err != nil {
-		return err
+// shaclGroups groups shapes by the datasets of their manifest entries,
+// keeping the order in which each set is first seen.
+func shaclGroups(cfg *Config, manifest *Manifest, shapes []string) ([]shaclGroup, error) {
+	var groups []shaclGroup
+	index := map[string]int{}
+	for _, shape := range shapes {
+		entry, _ := manifest.shapeEntry(cfg, shape)
+		datasets, err := entry.datasets(cfg)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.Join(datasets, "\n")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, shaclGroup{datasets: datasets, scoped: len(datasets) > 0})
+		}
+		groups[i].shapes = append(groups[i].shapes, shape)
+	}
+	for i := range groups {
+		if groups[i].scoped {
+			continue
+		}
+		datasets, err := cfg.datasetPaths()
+		if err != nil {
+			return nil, err
+		}
+		if err := ensureNonEmpty(datasets, "dataset"); err != nil {
+			return nil, err
+		}
+		groups[i].datasets = datasets
 	}
-	reportPath := filepath.Join(reportDir, "shacl-report.ttl")
+	return groups, nil
+}
 
-	cmd := exec.Command(cfg.ShaclValidateScript(), "-datafile", dataFile, "-shapesfile", shapesFile)
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	output := stdout.Bytes()
-	fmt.Print(string(output))
+func shapeStems(shapes []string) string {
+	stems := make([]string, len(shapes))
+	for i, shape := range shapes {
+		stems[i] = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(shape), ".ttl"), ".shacl")
+	}
+	return strings.Join(stems, "+")
+}
 
+// validateShaclGroup validates a group and parses its results, locating
+// each focus node in the group's datasets. A report with results is written
+// to reportPath; a conforming one removes any stale report. The prefixes of
+// the shapes and datasets are added to prefixes for compacting IRIs.
+func validateShaclGroup(cfg *Config, group shaclGroup, validate ShaclValidator, workDir, reportPath string, prefixes rdf.Context) ([]ShaclResult, error) {
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return nil, err
+	}
+	index, err := loadShapeIndex(group.shapes)
+	if err != nil {
+		return nil, err
+	}
+	prefixes.AddPrefixes(index.graph.Prefixes)
+	output, err := validate(cfg, group.datasets, group.shapes, workDir)
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseShaclResults(cfg, output, index)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if writeErr := os.WriteFile(reportPath, output, 0o644); writeErr != nil {
-				return fmt.Errorf("shacl validation failed: %v (additional error writing report: %w)", exitErr, writeErr)
-			}
-			return fmt.Errorf("shacl validation failed; report written to %s", reportPath)
+		return nil, err
+	}
+	if len(results) == 0 {
+		if _, err := os.Stat(reportPath); err == nil {
+			os.Remove(reportPath)
 		}
-		return err
+		return nil, nil
 	}
-
-	if _, err := os.Stat(reportPath); err == nil {
-		os.Remove(reportPath)
+	if err := os.WriteFile(reportPath, output, 0o644); err != nil {
+		return nil, err
+	}
+	declared := datasetPrefixes(group.datasets)
+	for _, dataset := range group.datasets {
+		prefixes.AddPrefixes(declared[dataset])
+	}
+	for i := range results {
+		file, line := locateSubject(group.datasets, declared, results[i].FocusNode)
+		if file != "" {
+			results[i].DataFile, results[i].DataLine = relativePath(cfg.RepoRoot, file), line
+		}
 	}
-	return nil
+	return results, nil
 }
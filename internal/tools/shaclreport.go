@@ -0,0 +1,406 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// ShaclResult is one sh:ValidationResult of a validation report.
+// SourceShape names the node shape; results raised by one of its blank
+// property shapes are attributed to it. Module and ShapeFile locate that
+// shape, DataFile and DataLine the focus node.
+type ShaclResult struct {
+	FocusNode   string `json:"focusNode"`
+	Path        string `json:"path,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Severity    string `json:"severity"`
+	SourceShape string `json:"sourceShape,omitempty"`
+	Constraint  string `json:"constraint,omitempty"`
+	Module      string `json:"module,omitempty"`
+	ShapeFile   string `json:"shapeFile,omitempty"`
+	DataFile    string `json:"dataFile,omitempty"`
+	DataLine    int    `json:"dataLine,omitempty"`
+}
+
+// ShaclReport collects the results of a RunShacl run. Reports lists the raw
+// Turtle reports written under build/reports.
+type ShaclReport struct {
+	Results []ShaclResult `json:"results"`
+	Reports []string      `json:"reports,omitempty"`
+
+	prefixes rdf.Context
+}
+
+// severityRanks orders SHACL severities for thresholds.
+var severityRanks = map[string]int{"Info": 1, "Warning": 2, "Violation": 3}
+
+// ParseSeverity returns the canonical name of a severity given in any case,
+// for example "warning" or "sh:Warning".
+func ParseSeverity(value string) (string, error) {
+	name := severityName(value)
+	for severity := range severityRanks {
+		if strings.EqualFold(severity, name) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("unknown severity %q (want violation, warning or info)", value)
+}
+
+// Counts returns the number of results with each severity.
+func (r *ShaclReport) Counts() map[string]int {
+	counts := map[string]int{"Violation": 0, "Warning": 0, "Info": 0}
+	for _, result := range r.Results {
+		counts[result.Severity]++
+	}
+	return counts
+}
+
+// AtOrAbove returns the number of results whose severity is at least
+// threshold.
+func (r *ShaclReport) AtOrAbove(threshold string) int {
+	n := 0
+	for _, result := range r.Results {
+		if severityRanks[result.Severity] >= severityRanks[threshold] {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ShaclReport) compact(iri string) string {
+	if r.prefixes == nil || iri == "" {
+		return iri
+	}
+	return r.prefixes.CompactIRI(iri)
+}
+
+// WriteText writes the results as a table grouped by module and source
+// shape, followed by the severity counts.
+func (r *ShaclReport) WriteText(w io.Writer) error {
+	results := append([]ShaclResult(nil), r.Results...)
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Module != results[j].Module {
+			return results[i].Module < results[j].Module
+		}
+		return results[i].SourceShape < results[j].SourceShape
+	})
+	var b strings.Builder
+	module, shape := "\x00", "\x00"
+	for _, result := range results {
+		if result.Module != module {
+			module, shape = result.Module, "\x00"
+			name := module
+			if name == "" {
+				name = "(unknown module)"
+			}
+			if result.ShapeFile != "" {
+				name += " — " + result.ShapeFile
+			}
+			fmt.Fprintf(&b, "%s\n", name)
+		}
+		if result.SourceShape != shape {
+			shape = result.SourceShape
+			name := r.compact(shape)
+			if name == "" {
+				name = "(unknown shape)"
+			}
+			fmt.Fprintf(&b, "  %s\n", name)
+		}
+		fmt.Fprintf(&b, "    %-9s %s", result.Severity, r.compact(result.FocusNode))
+		if result.Path != "" {
+			fmt.Fprintf(&b, " %s", r.compact(result.Path))
+		}
+		if result.Value != "" {
+			fmt.Fprintf(&b, " = %s", r.compact(result.Value))
+		}
+		if result.Message != "" {
+			fmt.Fprintf(&b, ": %s", result.Message)
+		}
+		if result.DataFile != "" {
+			fmt.Fprintf(&b, " (%s:%d)", result.DataFile, result.DataLine)
+		}
+		b.WriteString("\n")
+	}
+	counts := r.Counts()
+	fmt.Fprintf(&b, "%d results: %d violations, %d warnings, %d info\n",
+		len(r.Results), counts["Violation"], counts["Warning"], counts["Info"])
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the results with a summary of the severity counts.
+func (r *ShaclReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		*ShaclReport
+		Summary map[string]int `json:"summary"`
+	}{r, r.Counts()})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+var sarifLevels = map[string]string{"Violation": "error", "Warning": "warning", "Info": "note"}
+
+// WriteSARIF writes the results as a SARIF 2.1.0 log with one rule per
+// source shape, located at the focus node in the dataset when it can be
+// found and at the shape file otherwise, so code review tools can annotate
+// the offending lines.
+func (r *ShaclReport) WriteSARIF(w io.Writer) error {
+	run := sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: "bhashctl shacl", Rules: []sarifRule{}}}, Results: []sarifResult{}}
+	rules := map[string]bool{}
+	for _, result := range r.Results {
+		ruleID := r.compact(result.SourceShape)
+		if ruleID == "" {
+			ruleID = r.compact(result.Constraint)
+		}
+		if !rules[ruleID] {
+			rules[ruleID] = true
+			rule := sarifRule{ID: ruleID}
+			if result.Module != "" {
+				rule.ShortDescription = &sarifMessage{Text: fmt.Sprintf("SHACL shape %s (%s module)", ruleID, result.Module)}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+		text := r.compact(result.FocusNode)
+		if result.Path != "" {
+			text += " " + r.compact(result.Path)
+		}
+		if result.Message != "" {
+			text += ": " + result.Message
+		}
+		entry := sarifResult{RuleID: ruleID, Level: sarifLevels[result.Severity], Message: sarifMessage{Text: text}}
+		switch {
+		case result.DataFile != "":
+			entry.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: result.DataFile},
+				Region:           &sarifRegion{StartLine: result.DataLine},
+			}}}
+		case result.ShapeFile != "":
+			entry.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: result.ShapeFile}}}}
+		}
+		run.Results = append(run.Results, entry)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// shapeIndex is the merged shapes graph of a validation together with the
+// file that declares each node shape.
+type shapeIndex struct {
+	graph *rdf.Graph
+	files map[string]string
+}
+
+func loadShapeIndex(shapes []string) (*shapeIndex, error) {
+	index := &shapeIndex{graph: &rdf.Graph{}, files: map[string]string{}}
+	for i, shape := range shapes {
+		source, err := os.ReadFile(shape)
+		if err != nil {
+			return nil, err
+		}
+		graph, err := rdf.ParseTurtle(string(source), rdf.ParseOptions{Source: shape, BlankNodePrefix: fmt.Sprintf("s%d", i)})
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range graph.Subjects() {
+			if subject.IsIRI() {
+				if _, ok := index.files[subject.Value]; !ok {
+					index.files[subject.Value] = shape
+				}
+			}
+		}
+		index.graph.Merge(graph)
+	}
+	return index, nil
+}
+
+// owningNodeShape finds the node shape whose blank property shape raised a
+// result. Validators label blank shapes independently, so the property
+// shape is recognised by its path and message.
+func (s *shapeIndex) owningNodeShape(path string, messages []rdf.Term) string {
+	var byPath []string
+	for _, triple := range s.graph.Triples {
+		if triple.Predicate.Value != shaclNamespace+"property" || !triple.Subject.IsIRI() {
+			continue
+		}
+		property := triple.Object
+		if firstObject(s.graph, property, shaclNamespace+"path") != path {
+			continue
+		}
+		byPath = append(byPath, triple.Subject.Value)
+		for _, message := range s.graph.Objects(property, shaclNamespace+"message") {
+			for _, reported := range messages {
+				if message.Value == reported.Value {
+					return triple.Subject.Value
+				}
+			}
+		}
+	}
+	if len(byPath) == 1 {
+		return byPath[0]
+	}
+	return ""
+}
+
+// parseShaclResults reads the sh:ValidationResult nodes of a Turtle report
+// and attributes each to its node shape and module.
+func parseShaclResults(cfg *Config, report []byte, shapes *shapeIndex) ([]ShaclResult, error) {
+	graph, err := rdf.ParseTurtle(string(report), rdf.ParseOptions{Source: "validation report"})
+	if err != nil {
+		return nil, err
+	}
+	var results []ShaclResult
+	for _, subject := range graph.Subjects() {
+		if !hasObject(graph, subject, rdf.RDFType, shaclNamespace+"ValidationResult") {
+			continue
+		}
+		messages := graph.Objects(subject, shaclNamespace+"resultMessage")
+		result := ShaclResult{
+			FocusNode:  firstObject(graph, subject, shaclNamespace+"focusNode"),
+			Path:       firstObject(graph, subject, shaclNamespace+"resultPath"),
+			Value:      firstObject(graph, subject, shaclNamespace+"value"),
+			Severity:   severityName(firstObject(graph, subject, shaclNamespace+"resultSeverity")),
+			Constraint: firstObject(graph, subject, shaclNamespace+"sourceConstraintComponent"),
+		}
+		if len(messages) > 0 {
+			result.Message = messages[0].Value
+		}
+		for _, source := range graph.Objects(subject, shaclNamespace+"sourceShape") {
+			if source.IsIRI() {
+				result.SourceShape = source.Value
+			} else {
+				result.SourceShape = shapes.owningNodeShape(result.Path, messages)
+			}
+		}
+		if file, ok := shapes.files[result.SourceShape]; ok {
+			result.ShapeFile = relativePath(cfg.RepoRoot, file)
+			result.Module = shapeModule(file)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// shapeModule names the ontology module a shape file validates:
+// ontology/shapes/token.shacl.ttl belongs to "token".
+func shapeModule(file string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), ".ttl"), ".shacl")
+}
+
+// datasetPrefixes reads the prefixes each dataset declares. Datasets that
+// do not parse are left out, so their focus nodes are only found when
+// written as full IRIs.
+func datasetPrefixes(datasets []string) map[string]map[string]string {
+	prefixes := map[string]map[string]string{}
+	for _, dataset := range datasets {
+		if graph, err := rdf.ParseTurtleFile(dataset); err == nil {
+			prefixes[dataset] = graph.Prefixes
+		}
+	}
+	return prefixes
+}
+
+// locateSubject finds the line of the first statement about iri in the
+// datasets, written either as <iri> or as a prefixed name.
+func locateSubject(datasets []string, prefixes map[string]map[string]string, iri string) (string, int) {
+	for _, dataset := range datasets {
+		spellings := []string{"<" + iri + ">"}
+		for prefix, ns := range prefixes[dataset] {
+			if strings.HasPrefix(iri, ns) && len(iri) > len(ns) {
+				spellings = append(spellings, prefix+":"+iri[len(ns):])
+			}
+		}
+		if line := findSubjectLine(dataset, spellings); line > 0 {
+			return dataset, line
+		}
+	}
+	return "", 0
+}
+
+func findSubjectLine(path string, spellings []string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || unicode.IsSpace(rune(text[0])) {
+			continue
+		}
+		for _, spelling := range spellings {
+			if rest, ok := strings.CutPrefix(text, spelling); ok && (rest == "" || unicode.IsSpace(rune(rest[0]))) {
+				return line
+			}
+		}
+	}
+	return 0
+}
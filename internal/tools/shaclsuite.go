@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+const shaclNamespace = "http://www.w3.org/ns/shacl#"
+
+// ShaclCase pairs a deliberately invalid dataset under tests/shacl with the
+// violations the shapes must report for it. It is read from the JSON file
+// next to the dataset: tests/shacl/<name>.json describes <name>.ttl.
+type ShaclCase struct {
+	Description string `json:"description"`
+	// Shapes lists the shape files, relative to the repository root, that
+	// validate the dataset. Empty selects every shape.
+	Shapes     []string         `json:"shapes,omitempty"`
+	Violations []ShaclViolation `json:"violations"`
+}
+
+// ShaclViolation identifies one validation result. IRIs may be written as
+// prefixed names declared by the case dataset or the shapes. SourceShape
+// names the node shape; results raised by one of its blank property shapes
+// are attributed to it. Severity is Violation, Warning or Info.
+type ShaclViolation struct {
+	FocusNode   string `json:"focusNode"`
+	Path        string `json:"path,omitempty"`
+	SourceShape string `json:"sourceShape"`
+	Severity    string `json:"severity"`
+}
+
+func (v ShaclViolation) String() string {
+	text := fmt.Sprintf("%s %s", v.Severity, v.FocusNode)
+	if v.Path != "" {
+		text += " " + v.Path
+	}
+	return text + " (" + v.SourceShape + ")"
+}
+
+// ShaclValidator validates the union of datasets against shapes and returns
+// the Turtle validation report.
+type ShaclValidator func(cfg *Config, datasets []string, shapes []string, workDir string) ([]byte, error)
+
+// ShaclSuiteOptions configures RunShaclSuite.
+type ShaclSuiteOptions struct {
+	// Validator produces validation reports. Nil selects the TopBraid
+	// validator installed by bhashctl install.
+	Validator ShaclValidator
+	// Output receives one line per case and the differences of failing
+	// cases. Nil discards it.
+	Output io.Writer
+}
+
+// RunShaclSuite validates every dataset under tests/shacl and checks that
+// the report holds exactly the expected violations. The error lists the
+// cases whose report differs.
+func RunShaclSuite(cfg *Config, opts ShaclSuiteOptions) error {
+	validate := opts.Validator
+	if validate == nil {
+		validate = topBraidValidator
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	suiteDir := filepath.Join(cfg.RepoRoot, "tests", "shacl")
+	cases, err := filepath.Glob(filepath.Join(suiteDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if err := ensureNonEmpty(cases, "shacl suite case"); err != nil {
+		return err
+	}
+	sort.Strings(cases)
+	allShapes, err := cfg.shapePaths()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.BuildDir, 0o755); err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp(cfg.BuildDir, "shacl-suite-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	var failed []string
+	for i, casePath := range cases {
+		name := strings.TrimSuffix(filepath.Base(casePath), ".json")
+		missing, unexpected, err := runShaclCase(cfg, casePath, allShapes, validate, filepath.Join(tempDir, fmt.Sprint(i)))
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "error %s: %v\n", name, err)
+			failed = append(failed, name)
+		case len(missing) > 0 || len(unexpected) > 0:
+			fmt.Fprintf(out, "fail  %s\n", name)
+			for _, violation := range missing {
+				fmt.Fprintf(out, "  - %s\n", violation)
+			}
+			for _, violation := range unexpected {
+				fmt.Fprintf(out, "  + %s\n", violation)
+			}
+			failed = append(failed, name)
+		default:
+			fmt.Fprintf(out, "pass  %s\n", name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("shacl suite failures: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// runShaclCase validates one case and returns the expected violations that
+// were not reported and the reported ones that were not expected.
+func runShaclCase(cfg *Config, casePath string, allShapes []string, validate ShaclValidator, workDir string) ([]ShaclViolation, []ShaclViolation, error) {
+	data, err := os.ReadFile(casePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var spec ShaclCase
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", filepath.Base(casePath), err)
+	}
+	dataFile := strings.TrimSuffix(casePath, ".json") + ".ttl"
+	dataGraph, err := rdf.ParseTurtleFile(dataFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shapes := allShapes
+	if len(spec.Shapes) > 0 {
+		shapes = make([]string, len(spec.Shapes))
+		for i, shape := range spec.Shapes {
+			shapes[i] = filepath.Join(cfg.RepoRoot, filepath.FromSlash(shape))
+		}
+	}
+	index, err := loadShapeIndex(shapes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return nil, nil, err
+	}
+	report, err := validate(cfg, []string{dataFile}, shapes, workDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := parseShaclResults(cfg, report, index)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefixes := rdf.Context{}
+	for _, graph := range []*rdf.Graph{index.graph, dataGraph} {
+		for prefix, ns := range graph.Prefixes {
+			prefixes[prefix] = ns
+		}
+	}
+	expected := make([]ShaclViolation, len(spec.Violations))
+	for i, violation := range spec.Violations {
+		expected[i] = ShaclViolation{
+			FocusNode:   prefixes.ExpandIRI(violation.FocusNode),
+			Path:        prefixes.ExpandIRI(violation.Path),
+			SourceShape: prefixes.ExpandIRI(violation.SourceShape),
+			Severity:    severityName(violation.Severity),
+		}
+	}
+	reported := make([]ShaclViolation, len(results))
+	for i, result := range results {
+		reported[i] = ShaclViolation{
+			FocusNode:   result.FocusNode,
+			Path:        result.Path,
+			SourceShape: result.SourceShape,
+			Severity:    result.Severity,
+		}
+	}
+	missing, unexpected := diffViolations(expected, reported)
+	return missing, unexpected, nil
+}
+
+func diffViolations(expected, actual []ShaclViolation) (missing, unexpected []ShaclViolation) {
+	counts := map[ShaclViolation]int{}
+	for _, violation := range actual {
+		counts[violation]++
+	}
+	for _, violation := range expected {
+		if counts[violation] > 0 {
+			counts[violation]--
+			continue
+		}
+		missing = append(missing, violation)
+	}
+	for _, violation := range actual {
+		if counts[violation] > 0 {
+			counts[violation]--
+			unexpected = append(unexpected, violation)
+		}
+	}
+	return missing, unexpected
+}
+
+func firstObject(graph *rdf.Graph, subject rdf.Term, predicate string) string {
+	if objects := graph.Objects(subject, predicate); len(objects) > 0 {
+		return objects[0].Value
+	}
+	return ""
+}
+
+func hasObject(graph *rdf.Graph, subject rdf.Term, predicate, object string) bool {
+	for _, term := range graph.Objects(subject, predicate) {
+		if term.Value == object {
+			return true
+		}
+	}
+	return false
+}
+
+// severityName reduces sh:Violation, the full IRI or a bare name to the
+// local name.
+func severityName(value string) string {
+	if i := strings.LastIndexAny(value, "#:"); i >= 0 {
+		return value[i+1:]
+	}
+	return value
+}
+
+// topBraidValidator merges the datasets and shapes with ROBOT and
+// validates them with the TopBraid command line tool. A non-zero exit only
+// means the data does not conform, so the report is returned either way.
+func topBraidValidator(cfg *Config, datasets []string, shapes []string, workDir string) ([]byte, error) {
+	dataFile := datasets[0]
+	if len(datasets) > 1 {
+		dataFile = filepath.Join(workDir, "data.ttl")
+		if err := mergeWithRobot(cfg.RobotExecutable(), datasets, dataFile); err != nil {
+			return nil, err
+		}
+	}
+	shapesFile := filepath.Join(workDir, "shapes.ttl")
+	if err := mergeWithRobot(cfg.RobotExecutable(), shapes, shapesFile); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(cfg.ShaclValidateScript(), "-datafile", dataFile, "-shapesfile", shapesFile)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok || stdout.Len() == 0 {
+			return nil, fmt.Errorf("shaclvalidate %s: %w", filepath.Base(dataFile), err)
+		}
+	}
+	return stdout.Bytes(), nil
+}
@@ -2,129 +2,289 @@ package tools
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
-func RunSparql(cfg *Config) error {
-	datasets, err := cfg.datasetPaths()
+// SparqlOptions configures a competency query regression run.
+type SparqlOptions struct {
+	// Backend executes the queries. Nil selects the ROBOT backend.
+	Backend QueryBackend
+	// Progress receives one line per query as it starts. Nil discards
+	// progress.
+	Progress io.Writer
+	// Queries selects the queries whose name, with or without the .rq
+	// extension, matches one of these glob patterns. Empty selects every
+	// query.
+	Queries []string
+	// Tags selects the queries whose front matter declares every
+	// "key=value" pair, for example "module=token" or "priority=high".
+	Tags []string
+	// Workers is the number of queries run concurrently. Values below one
+	// run them one after another.
+	Workers int
+	// UpdateFixtures overwrites the fixture of every selected query whose
+	// results differ from it or that has no fixture yet.
+	UpdateFixtures bool
+	// Only restricts fixture updates to queries whose name, with or without
+	// the .rq extension, matches one of these glob patterns. Empty selects
+	// every query.
+	Only []string
+	// Confirm is asked before each fixture update. Nil updates without
+	// asking.
+	Confirm func(QueryResult) bool
+	// Strict fails queries that have no fixture instead of skipping them.
+	Strict bool
+}
+
+// selectQueries filters the query files by name pattern and front matter
+// tags.
+func (o SparqlOptions) selectQueries(queries []string) ([]string, error) {
+	tags, err := parseTags(o.Tags)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := ensureNonEmpty(datasets, "dataset"); err != nil {
-		return err
+	selected := make([]string, 0, len(queries))
+	for _, query := range queries {
+		if !matchesQueryName(o.Queries, filepath.Base(query)) {
+			continue
+		}
+		if len(tags) > 0 {
+			meta, err := readQueryMeta(query)
+			if err != nil {
+				return nil, err
+			}
+			if !meta.hasTags(tags) {
+				continue
+			}
+		}
+		selected = append(selected, query)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no queries match the selection")
+	}
+	return selected, nil
+}
+
+// RunSparql runs the selected competency queries and compares their
+// results with the expected results named in the manifest, by default the
+// fixture of the same name under tests/fixtures/results.
+// The report holds one outcome per query, in file order whatever the
+// number of workers; the error lists the queries that failed or could not
+// run. Setup failures return a nil report.
+func RunSparql(cfg *Config, opts SparqlOptions) (*SparqlReport, error) {
+	backend := opts.Backend
+	if backend == nil {
+		backend = NewRobotBackend()
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = io.Discard
 	}
 
 	queries, err := cfg.queryPaths()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := ensureNonEmpty(queries, "query"); err != nil {
-		return err
+		return nil, err
+	}
+	queries, err = opts.selectQueries(queries)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := cfg.loadManifest()
+	if err != nil {
+		return nil, err
 	}
 
+	if err := os.MkdirAll(cfg.BuildDir, 0o755); err != nil {
+		return nil, err
+	}
 	tempDir, err := os.MkdirTemp(cfg.BuildDir, "sparql-")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer os.RemoveAll(tempDir)
 
-	dataFile := filepath.Join(tempDir, "data.ttl")
-	if err := mergeWithRobot(cfg.RobotExecutable(), datasets, dataFile); err != nil {
-		return err
+	started := time.Now()
+	if err := backend.Prepare(cfg, tempDir); err != nil {
+		return nil, err
 	}
 
 	outputDir := filepath.Join(cfg.BuildDir, "queries")
 	if err := os.MkdirAll(outputDir, 0o755); err != nil {
-		return err
+		return nil, err
 	}
 
-	resultsDir := filepath.Join(cfg.RepoRoot, "tests", "fixtures", "results")
-	success := true
-	var failures []string
-
-	for _, query := range queries {
+	report := &SparqlReport{Backend: backend.Name(), Started: started.UTC(), Results: make([]QueryResult, len(queries))}
+	var progressMu sync.Mutex
+	run := func(i int) {
+		query := queries[i]
 		name := filepath.Base(query)
-		fmt.Printf("Running %s...\n", name)
+		progressMu.Lock()
+		fmt.Fprintf(progress, "Running %s (%s)...\n", name, backend.Name())
+		progressMu.Unlock()
 		output := filepath.Join(outputDir, strings.TrimSuffix(name, filepath.Ext(name))+".csv")
-		if err := runRobotQuery(cfg.RobotExecutable(), dataFile, query, output); err != nil {
-			return err
+		result := QueryResult{Name: name, Query: relativePath(cfg.RepoRoot, query)}
+		entry, _ := manifest.queryEntry(cfg, query)
+		queryStarted := time.Now()
+		if err := runQuery(cfg, backend, entry, query, output); err != nil {
+			result.Status = StatusError
+			result.Message = err.Error()
+		} else {
+			fixture := entry.expectedPath(cfg, query)
+			compareResults(query, fixture, output, &result)
+			result.Fixture = relativePath(cfg.RepoRoot, fixture)
+		}
+		result.Seconds = time.Since(queryStarted).Seconds()
+		report.Results[i] = result
+	}
+	runWorkers(len(queries), opts.Workers, run)
+
+	for i := range report.Results {
+		result := &report.Results[i]
+		if result.Status == StatusError {
+			continue
 		}
-		expected := filepath.Join(resultsDir, filepath.Base(output))
-		match, err := compareCSV(expected, output)
-		if err != nil {
-			return err
+		if opts.UpdateFixtures {
+			output := filepath.Join(outputDir, strings.TrimSuffix(result.Name, filepath.Ext(result.Name))+".csv")
+			updateFixture(opts, output, filepath.Join(cfg.RepoRoot, filepath.FromSlash(result.Fixture)), result)
 		}
-		if !match {
-			success = false
-			failures = append(failures, name)
+		if opts.Strict && result.Status == StatusSkippedNoFixture {
+			result.Status = StatusFail
+			result.Message += " (strict)"
 		}
 	}
+	report.Seconds = time.Since(started).Seconds()
 
-	if !success {
-		return fmt.Errorf("sparql regression failures: %s", strings.Join(failures, ", "))
+	if failures := report.Failures(); len(failures) > 0 {
+		return report, fmt.Errorf("sparql regression failures: %s", strings.Join(failures, ", "))
 	}
-	return nil
+	return report, nil
 }
 
-func compareCSV(expectedPath, actualPath string) (bool, error) {
-	expected, err := readCSVLines(expectedPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Printf("No expected results for %s; skipping comparison.\n", filepath.Base(actualPath))
-			return true, nil
-		}
-		return false, err
+// runQuery executes one query, against the datasets of its manifest entry
+// when the backend supports scoping.
+func runQuery(cfg *Config, backend QueryBackend, entry ManifestEntry, query, output string) error {
+	scoped, ok := backend.(ScopedBackend)
+	if !ok {
+		return backend.Query(query, output)
 	}
-	actual, err := readCSVLines(actualPath)
+	datasets, err := entry.datasets(cfg)
 	if err != nil {
-		return false, err
+		return err
 	}
-	if len(expected) != len(actual) {
-		printCSVDelta(expected, actual)
-		return false, nil
+	if len(datasets) == 0 {
+		return backend.Query(query, output)
 	}
-	for i := range expected {
-		if expected[i] != actual[i] {
-			printCSVDelta(expected, actual)
-			return false, nil
-		}
+	return scoped.QueryScoped(query, output, datasets)
+}
+
+// runWorkers calls run for every index in [0, n) from at most workers
+// goroutines.
+func runWorkers(n, workers int, run func(int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
 	}
-	return true, nil
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				run(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 }
 
-func readCSVLines(path string) ([]string, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// updateFixture copies the actual results over the fixture when the query
+// failed or had no fixture and the options select and confirm it.
+func updateFixture(opts SparqlOptions, actualPath, fixturePath string, result *QueryResult) {
+	if result.Status != StatusFail && result.Status != StatusSkippedNoFixture {
+		return
 	}
-	lines := strings.Split(string(data), "\n")
-	filtered := make([]string, 0, len(lines))
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" {
-			filtered = append(filtered, normalizeCSVLine(trimmed))
-		}
+	if !matchesQueryName(opts.Only, result.Name) || opts.Confirm != nil && !opts.Confirm(*result) {
+		return
+	}
+	data, err := os.ReadFile(actualPath)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fixturePath), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(fixturePath, data, 0o644)
+	}
+	if err != nil {
+		result.Status = StatusError
+		result.Message = fmt.Sprintf("update fixture: %v", err)
+		return
 	}
-	return filtered, nil
+	result.Status = StatusUpdated
+	result.Message = fmt.Sprintf("updated %s", result.Fixture)
 }
 
-func printCSVDelta(expected, actual []string) {
-	fmt.Println("Expected:")
-	for _, line := range expected {
-		fmt.Printf("  %s\n", line)
+// compareResults compares the actual results with the expected fixture as
+// parsed result sets and records the outcome in result. Rows are compared
+// in order only when the query has an ORDER BY clause.
+func compareResults(queryPath, expectedPath, actualPath string, result *QueryResult) {
+	expected, err := readResultSet(expectedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			result.Status = StatusSkippedNoFixture
+			result.Message = fmt.Sprintf("no expected results for %s", filepath.Base(actualPath))
+			return
+		}
+		result.Status = StatusError
+		result.Message = err.Error()
+		return
 	}
-	fmt.Println("Actual:")
-	for _, line := range actual {
-		fmt.Printf("  %s\n", line)
+	actual, err := readResultSet(actualPath)
+	if err != nil {
+		result.Status = StatusError
+		result.Message = err.Error()
+		return
+	}
+	query, err := os.ReadFile(queryPath)
+	if err != nil {
+		result.Status = StatusError
+		result.Message = err.Error()
+		return
+	}
+	result.Rows = len(actual.rows)
+	if !actual.alignTo(expected.vars) {
+		result.Status = StatusFail
+		result.Diff = &RowDiff{Missing: []string{csvLine(expected.vars)}, Unexpected: []string{csvLine(actual.vars)}}
+		result.Message = fmt.Sprintf("columns differ: expected %s, got %s", strings.Join(expected.vars, ","), strings.Join(actual.vars, ","))
+		return
 	}
+	diff := compareRows(expected.rows, actual.rows, queryIsOrdered(string(query)))
+	if diff == nil {
+		result.Status = StatusPass
+		return
+	}
+	result.Status = StatusFail
+	result.Diff = diff
+	result.Message = diff.summary()
 }
 
-func normalizeCSVLine(line string) string {
-	// Normalise time zone suffixes so that "Z" and "+00:00" compare equal.
-	line = strings.ReplaceAll(line, "+00:00", "Z")
-	// ROBOT may produce "-00:00" for unknown offsets; keep consistent with fixtures.
-	line = strings.ReplaceAll(line, "-00:00", "Z")
-	return line
+func relativePath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
 }
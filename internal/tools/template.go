@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/bhash/internal/rdf"
+)
+
+// TemplateOptions configures RunTemplates.
+type TemplateOptions struct {
+	// Mappings also turns the crosswalks under docs/mappings into templates
+	// stating each term's hedera:sourceDocument.
+	Mappings bool
+	// Robot runs ROBOT commands. Nil runs the ROBOT installed by bhashctl
+	// install.
+	Robot RobotRunner
+	// Output receives the generated files and the drift found. Nil discards
+	// it.
+	Output io.Writer
+}
+
+// TemplateDrift is a statement a template generates about a term that the
+// hand-written module declaring the term does not make.
+type TemplateDrift struct {
+	Template  string
+	Term      string
+	Predicate string
+	Value     string
+}
+
+func (d TemplateDrift) String() string {
+	return fmt.Sprintf("%s %s %s (from %s)", d.Term, d.Predicate, d.Value, d.Template)
+}
+
+// TemplateModulePath returns the module merged from every template output.
+func (c *Config) TemplateModulePath() string {
+	return filepath.Join(c.BuildDir, "templates", "templated-module.ttl")
+}
+
+// RunTemplates runs each ROBOT template under templates/, and with
+// opts.Mappings each crosswalk under docs/mappings, merges the outputs into
+// build/templates/templated-module.ttl and compares the generated terms
+// with the hand-written modules under ontology/src. Terms the modules do
+// not declare are CSV-authored and only listed; statements about declared
+// terms that the modules lack are drift, and the error reports them.
+// Literals compare by lexical form, so a template label without a language
+// tag matches the module's "@en" label.
+func RunTemplates(cfg *Config, opts TemplateOptions) ([]TemplateDrift, error) {
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	robot := opts.Robot
+	if robot == nil {
+		robot = cfg.runRobot
+	}
+	modules, err := loadModuleGraph(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := filepath.Glob(filepath.Join(cfg.RepoRoot, "templates", "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(templates)
+	origins := map[string]string{}
+	outDir := filepath.Join(cfg.BuildDir, "templates")
+	if opts.Mappings {
+		mappings, err := filepath.Glob(filepath.Join(cfg.RepoRoot, "docs", "mappings", "*.csv"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(mappings)
+		for _, mapping := range mappings {
+			template, err := mappingTemplate(mapping, filepath.Join(outDir, "mappings"), modules)
+			if err != nil {
+				return nil, err
+			}
+			origins[template] = mapping
+			templates = append(templates, template)
+		}
+	}
+	if err := ensureNonEmpty(templates, "template"); err != nil {
+		return nil, err
+	}
+
+	var prefixArgs []string
+	prefixes := make([]string, 0, len(modules.Prefixes))
+	for prefix := range modules.Prefixes {
+		if prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		prefixArgs = append(prefixArgs, "--prefix", prefix+": "+modules.Prefixes[prefix])
+	}
+
+	outputs := make([]string, len(templates))
+	sources := map[string]string{}
+	for i, template := range templates {
+		origin, mapped := origins[template]
+		outputs[i] = filepath.Join(outDir, strings.TrimSuffix(filepath.Base(template), ".csv")+".ttl")
+		if mapped {
+			outputs[i] = filepath.Join(outDir, "mappings", strings.TrimSuffix(filepath.Base(template), ".csv")+".ttl")
+		} else {
+			origin = template
+		}
+		if err := os.MkdirAll(filepath.Dir(outputs[i]), 0o755); err != nil {
+			return nil, err
+		}
+		args := append(append([]string(nil), prefixArgs...), "template", "--template", template, "--output", outputs[i])
+		if output, err := robot(args); err != nil {
+			return nil, fmt.Errorf("robot template (%s): %w\n%s", filepath.Base(template), err, strings.TrimSpace(string(output)))
+		}
+		sources[outputs[i]] = relativePath(cfg.RepoRoot, origin)
+		fmt.Fprintf(out, "generated %s\n", relativePath(cfg.RepoRoot, outputs[i]))
+	}
+	merged := cfg.TemplateModulePath()
+	args := []string{"merge"}
+	for _, output := range outputs {
+		args = append(args, "--input", output)
+	}
+	if output, err := robot(append(args, "--output", merged)); err != nil {
+		return nil, fmt.Errorf("robot merge: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	fmt.Fprintf(out, "merged %s\n", relativePath(cfg.RepoRoot, merged))
+
+	var drift []TemplateDrift
+	var authored []string
+	ctx := rdf.DefaultContext()
+	ctx.AddPrefixes(modules.Prefixes)
+	for _, output := range outputs {
+		generated, err := rdf.ParseTurtleFile(output)
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range generated.Subjects() {
+			if !subject.IsIRI() || hasObject(generated, subject, rdf.RDFType, rdf.OWLNamespace+"Ontology") {
+				continue
+			}
+			if len(modules.Objects(subject, rdf.RDFType)) == 0 {
+				authored = append(authored, ctx.CompactIRI(subject.Value))
+				continue
+			}
+			drift = append(drift, termDrift(generated, modules, subject, sources[output], ctx)...)
+		}
+	}
+	if len(authored) > 0 {
+		fmt.Fprintf(out, "%d terms authored only in templates: %s\n", len(authored), strings.Join(authored, ", "))
+	}
+	if len(drift) > 0 {
+		fmt.Fprintf(out, "%d statements differ from ontology/src:\n", len(drift))
+		for _, d := range drift {
+			fmt.Fprintf(out, "  %s\n", d)
+		}
+		return drift, fmt.Errorf("templates drift from ontology modules: %d statements", len(drift))
+	}
+	return nil, nil
+}
+
+// loadModuleGraph merges the hand-written modules under ontology/src.
+func loadModuleGraph(cfg *Config) (*rdf.Graph, error) {
+	names, paths, err := cfg.ontologyModules()
+	if err != nil {
+		return nil, err
+	}
+	merged := &rdf.Graph{}
+	for i, name := range names {
+		source, err := os.ReadFile(paths[name])
+		if err != nil {
+			return nil, err
+		}
+		graph, err := rdf.ParseTurtle(string(source), rdf.ParseOptions{Source: paths[name], BlankNodePrefix: fmt.Sprintf("m%d", i)})
+		if err != nil {
+			return nil, err
+		}
+		merged.Merge(graph)
+	}
+	return merged, nil
+}
+
+// termDrift lists the statements about subject in generated that modules
+// does not make. Blank node objects are skipped.
+func termDrift(generated, modules *rdf.Graph, subject rdf.Term, template string, ctx rdf.Context) []TemplateDrift {
+	var drift []TemplateDrift
+	for _, triple := range generated.Triples {
+		if triple.Subject != subject || triple.Object.IsBlankNode() {
+			continue
+		}
+		found := false
+		for _, object := range modules.Objects(subject, triple.Predicate.Value) {
+			if object.Kind == triple.Object.Kind && object.Value == triple.Object.Value {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		value := triple.Object.Value
+		if triple.Object.IsIRI() {
+			value = ctx.CompactIRI(value)
+		} else {
+			value = fmt.Sprintf("%q", value)
+		}
+		drift = append(drift, TemplateDrift{
+			Template:  template,
+			Term:      ctx.CompactIRI(subject.Value),
+			Predicate: ctx.CompactIRI(triple.Predicate.Value),
+			Value:     value,
+		})
+	}
+	return drift
+}
+
+// mappingTemplate writes a ROBOT template for a docs/mappings crosswalk,
+// whose columns are Term, Source Document and Notes. Each term keeps the
+// type its module declares, so properties are not templated as classes.
+func mappingTemplate(mapping, outDir string, modules *rdf.Graph) (string, error) {
+	file, err := os.Open(mapping)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("parse %s: %w", filepath.Base(mapping), err)
+	}
+	if len(records) == 0 || len(records[0]) < 2 || records[0][0] != "Term" || records[0][1] != "Source Document" {
+		return "", fmt.Errorf("%s: expected Term and Source Document columns", filepath.Base(mapping))
+	}
+	rows := [][]string{
+		{"ID", "Type", "Source Document"},
+		{"ID", "TYPE", "AI hedera:sourceDocument"},
+	}
+	for _, record := range records[1:] {
+		if len(record) < 2 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		term := strings.TrimSpace(record[0])
+		rows = append(rows, []string{term, declaredType(modules, term), strings.TrimSpace(record[1])})
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(outDir, filepath.Base(mapping))
+	target, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	writer := csv.NewWriter(target)
+	if err := writer.WriteAll(rows); err != nil {
+		target.Close()
+		return "", err
+	}
+	return path, target.Close()
+}
+
+// declaredType returns the OWL type of a prefixed term in the modules as a
+// ROBOT template TYPE value, defaulting to owl:Class.
+func declaredType(modules *rdf.Graph, term string) string {
+	iri := rdf.Context(modules.Prefixes).ExpandIRI(term)
+	for _, typ := range []string{"ObjectProperty", "DatatypeProperty", "AnnotationProperty", "NamedIndividual"} {
+		if hasObject(modules, rdf.IRI(iri), rdf.RDFType, rdf.OWLNamespace+typ) {
+			return "owl:" + typ
+		}
+	}
+	return "owl:Class"
+}
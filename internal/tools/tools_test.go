@@ -2,6 +2,9 @@ package tools
 
 import (
 	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,7 +13,10 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/hashgraph/bhash/internal/fluree"
 )
 
 func TestNewConfigSetsDefaults(t *testing.T) {
@@ -108,6 +114,21 @@ func createFile(t *testing.T, path string) {
 	}
 }
 
+// writeFiles creates each file in files, keyed by its slash-separated path
+// relative to root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll(%s): %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s): %v", path, err)
+		}
+	}
+}
+
 func TestDatasetPaths(t *testing.T) {
 	repoRoot := t.TempDir()
 	cfg := NewConfig(repoRoot)
@@ -338,3 +359,954 @@ SELECT ?a ?b ?c WHERE {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+type fakeSPARQLQuerier struct {
+	ledgers []string
+	results *fluree.SPARQLResults
+}
+
+func (f *fakeSPARQLQuerier) QuerySPARQL(_ context.Context, ledger, _ string) (*fluree.SPARQLResults, error) {
+	f.ledgers = append(f.ledgers, ledger)
+	return f.results, nil
+}
+
+func TestRunSparqlWithFlureeBackend(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+
+	writeFiles(t, repoRoot, map[string]string{
+		"tests/queries/cq-test-001.rq":           "SELECT ?token ?symbol WHERE { ?token <x:symbol> ?symbol }",
+		"tests/fixtures/results/cq-test-001.csv": "token,symbol\nurn:token:1,USDH\n",
+	})
+
+	results := &fluree.SPARQLResults{}
+	results.Head.Vars = []string{"token", "symbol"}
+	results.Results.Bindings = []map[string]fluree.SPARQLTerm{{
+		"token":  {Type: "uri", Value: "urn:token:1"},
+		"symbol": {Type: "literal", Value: "USDH"},
+	}}
+	querier := &fakeSPARQLQuerier{results: results}
+
+	report, err := RunSparql(cfg, SparqlOptions{Backend: NewFlureeBackend(querier, "tenant/ledger")})
+	if err != nil {
+		t.Fatalf("RunSparql returned error: %v", err)
+	}
+	if !reflect.DeepEqual(querier.ledgers, []string{"tenant/ledger"}) {
+		t.Fatalf("unexpected ledgers queried: %v", querier.ledgers)
+	}
+	if len(report.Results) != 1 || report.Results[0].Status != StatusPass || report.Results[0].Fixture != "tests/fixtures/results/cq-test-001.csv" {
+		t.Fatalf("unexpected report: %+v", report.Results)
+	}
+
+	results.Results.Bindings[0]["symbol"] = fluree.SPARQLTerm{Type: "literal", Value: "HBARX"}
+	report, err = RunSparql(cfg, SparqlOptions{Backend: NewFlureeBackend(querier, "tenant/ledger")})
+	if err == nil || !strings.Contains(err.Error(), "cq-test-001.rq") {
+		t.Fatalf("expected regression failure, got %v", err)
+	}
+	diff := report.Results[0].Diff
+	if report.Results[0].Status != StatusFail || !reflect.DeepEqual(diff.Missing, []string{"urn:token:1,USDH"}) || !reflect.DeepEqual(diff.Unexpected, []string{"urn:token:1,HBARX"}) {
+		t.Fatalf("unexpected failure result: %+v", report.Results[0])
+	}
+}
+
+type failingBackend struct{ failures map[string]error }
+
+func (b failingBackend) Name() string                  { return "fake" }
+func (b failingBackend) Prepare(*Config, string) error { return nil }
+func (b failingBackend) Query(queryFile, outputFile string) error {
+	if err := b.failures[filepath.Base(queryFile)]; err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, []byte("a\n2\n1\n"), 0o644)
+}
+
+func TestSparqlReportOutcomesAndFormats(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"tests/queries/cq-a.rq":           "SELECT ?a WHERE {}",
+		"tests/queries/cq-b.rq":           "SELECT ?a WHERE {} ORDER BY ?a",
+		"tests/queries/cq-c.rq":           "SELECT ?a WHERE {}",
+		"tests/queries/cq-d.rq":           "SELECT ?a WHERE {}",
+		"tests/fixtures/results/cq-a.csv": "a\n2\n1\n",
+		"tests/fixtures/results/cq-b.csv": "a\n1\n2\n",
+		"tests/fixtures/results/cq-d.csv": "a\n1\n",
+	})
+	backend := failingBackend{failures: map[string]error{"cq-d.rq": errors.New("backend unavailable")}}
+	var progress strings.Builder
+	report, err := RunSparql(cfg, SparqlOptions{Backend: backend, Progress: &progress})
+	if err == nil || err.Error() != "sparql regression failures: cq-b.rq, cq-d.rq" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statuses []QueryStatus
+	for _, result := range report.Results {
+		statuses = append(statuses, result.Status)
+	}
+	want := []QueryStatus{StatusPass, StatusFail, StatusSkippedNoFixture, StatusError}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if !report.Results[1].Diff.Reordered {
+		t.Fatalf("expected cq-b to be reported as reordered: %+v", report.Results[1].Diff)
+	}
+	if !strings.Contains(progress.String(), "Running cq-c.rq (fake)...") {
+		t.Fatalf("unexpected progress: %s", progress.String())
+	}
+
+	var junit strings.Builder
+	if err := report.WriteJUnit(&junit); err != nil {
+		t.Fatalf("WriteJUnit returned error: %v", err)
+	}
+	for _, fragment := range []string{
+		`<testsuite name="sparql.fake" tests="4" failures="1" errors="1" skipped="1"`,
+		`<failure message="rows match the fixture but are in a different order" type="mismatch">`,
+		`<error message="backend unavailable" type="error">`,
+		`<skipped message="no expected results for cq-c.csv">`,
+	} {
+		if !strings.Contains(junit.String(), fragment) {
+			t.Fatalf("JUnit output missing %q:\n%s", fragment, junit.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+	var decoded struct {
+		Results []QueryResult  `json:"results"`
+		Summary map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode JSON report: %v", err)
+	}
+	if decoded.Summary["skipped-no-fixture"] != 1 || decoded.Results[3].Message != "backend unavailable" {
+		t.Fatalf("unexpected JSON report: %s", buf.String())
+	}
+}
+
+type scopedBackend struct {
+	failingBackend
+	mu     sync.Mutex
+	scopes map[string][]string
+}
+
+func (b *scopedBackend) QueryScoped(queryFile, outputFile string, datasets []string) error {
+	b.mu.Lock()
+	b.scopes[filepath.Base(queryFile)] = datasets
+	b.mu.Unlock()
+	return b.Query(queryFile, outputFile)
+}
+
+func TestRunSparqlSelectsAndScopesQueries(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/token.ttl":                  "",
+		"ontology/src/core.ttl":                   "",
+		"ontology/examples/token-compliance.ttl":  "",
+		"ontology/examples/core-consensus.ttl":    "",
+		"tests/fixtures/datasets/cq-comp-001.ttl": "",
+		"tests/queries/cq-comp-001.rq":            "# module: token\n# priority: high\n\nSELECT ?a WHERE {}",
+		"tests/queries/cq-comp-002.rq":            "# module: core\n# priority: medium\nSELECT ?a WHERE {}",
+		"tests/queries/cq-comp-003.rq":            "# priority: High\nSELECT ?a WHERE {}",
+		"tests/queries/cq-dev-001.rq":             "# priority: high\nSELECT ?a WHERE {}",
+		"tests/manifest.json":                     `{"queries": [{"file": "tests/queries/cq-comp-001.rq", "modules": ["token"], "datasets": ["tests/fixtures/datasets/cq-comp-001.ttl"]}]}`,
+	})
+	backend := &scopedBackend{scopes: map[string][]string{}}
+	report, err := RunSparql(cfg, SparqlOptions{Backend: backend, Queries: []string{"cq-comp-*"}, Tags: []string{"priority=high"}, Workers: 4})
+	if err != nil {
+		t.Fatalf("RunSparql returned error: %v", err)
+	}
+	var names []string
+	for _, result := range report.Results {
+		names = append(names, result.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"cq-comp-001.rq", "cq-comp-003.rq"}) {
+		t.Fatalf("unexpected selection: %v", names)
+	}
+	want := map[string][]string{"cq-comp-001.rq": {
+		filepath.Join(repoRoot, "ontology", "examples", "token-compliance.ttl"),
+		filepath.Join(repoRoot, "tests", "fixtures", "datasets", "cq-comp-001.ttl"),
+	}}
+	if !reflect.DeepEqual(backend.scopes, want) {
+		t.Fatalf("unexpected scopes: %v", backend.scopes)
+	}
+
+	if _, err := RunSparql(cfg, SparqlOptions{Backend: backend, Tags: []string{"stakeholder=nobody"}}); err == nil || err.Error() != "no queries match the selection" {
+		t.Fatalf("expected empty selection error, got %v", err)
+	}
+	if _, err := RunSparql(cfg, SparqlOptions{Backend: backend, Tags: []string{"priority"}}); err == nil || !strings.Contains(err.Error(), "want key=value") {
+		t.Fatalf("expected invalid tag error, got %v", err)
+	}
+}
+
+func TestValidateManifestAndShaclGroups(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	for _, rel := range []string{
+		"ontology/src/token.ttl",
+		"ontology/examples/token-compliance.ttl",
+		"ontology/examples/hiero.ttl",
+		"ontology/shapes/token.shacl.ttl",
+		"ontology/shapes/hiero.shacl.ttl",
+		"tests/queries/cq-a.rq",
+		"tests/queries/cq-b.rq",
+	} {
+		createFile(t, filepath.Join(repoRoot, filepath.FromSlash(rel)))
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "tests", "queries", "cq-a.rq"), []byte("# module: token\nSELECT ?a WHERE {}"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	manifest := `{
+  "queries": [
+    {"file": "tests/queries/cq-a.rq", "modules": ["core"], "expected": "tests/fixtures/results/cq-a.csv"},
+    {"file": "tests/queries/cq-c.rq"}
+  ],
+  "shapes": [
+    {"file": "ontology/shapes/token.shacl.ttl", "modules": ["token"]},
+    {"file": "ontology/shapes/hiero.shacl.ttl", "datasets": ["ontology/examples/missing.ttl"]}
+  ]
+}`
+	if err := os.WriteFile(cfg.ManifestPath(), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	problems, err := ValidateManifest(cfg)
+	if err != nil {
+		t.Fatalf("ValidateManifest returned error: %v", err)
+	}
+	for _, fragment := range []string{
+		`tests/queries/cq-a.rq: unknown ontology module "core"`,
+		"tests/queries/cq-c.rq: query file does not exist",
+		"tests/queries/cq-b.rq: query is not in the manifest",
+		"ontology/shapes/hiero.shacl.ttl: dataset ontology/examples/missing.ttl",
+		"tests/queries/cq-a.rq: expected results tests/fixtures/results/cq-a.csv do not exist",
+		"tests/queries/cq-a.rq: front matter modules token differ from manifest modules core",
+	} {
+		if !strings.Contains(strings.Join(problems, "\n"), fragment) {
+			t.Errorf("problems missing %q:\n%s", fragment, strings.Join(problems, "\n"))
+		}
+	}
+	if len(problems) != 6 {
+		t.Fatalf("unexpected problems:\n%s", strings.Join(problems, "\n"))
+	}
+
+	manifest = `{"shapes": [{"file": "ontology/shapes/token.shacl.ttl", "modules": ["token"]}]}`
+	if err := os.WriteFile(cfg.ManifestPath(), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	loaded, err := cfg.loadManifest()
+	if err != nil {
+		t.Fatalf("loadManifest: %v", err)
+	}
+	shapes, err := cfg.shapePaths()
+	if err != nil {
+		t.Fatalf("shapePaths: %v", err)
+	}
+	groups, err := shaclGroups(cfg, loaded, shapes)
+	if err != nil {
+		t.Fatalf("shaclGroups: %v", err)
+	}
+	examples := filepath.Join(repoRoot, "ontology", "examples")
+	want := []shaclGroup{
+		{datasets: []string{filepath.Join(examples, "hiero.ttl"), filepath.Join(examples, "token-compliance.ttl")}, shapes: []string{shapes[0]}},
+		{datasets: []string{filepath.Join(examples, "token-compliance.ttl")}, shapes: []string{shapes[1]}, scoped: true},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestRunShaclSuiteComparesReportedViolations(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	shapes, err := os.ReadFile(filepath.Join("..", "..", "ontology", "shapes", "token.shacl.ttl"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	data := "@prefix ex: <https://example.org/> .\n@prefix hedera: <https://bhash.dev/hedera/core/> .\nex:T a hedera:StablecoinToken .\n"
+	expected := `{"violations": [
+  {"focusNode": "ex:T", "path": "hedera:hasKeyAssignment", "sourceShape": "hedera:StablecoinKeyGovernanceShape", "severity": "sh:Violation"},
+  {"focusNode": "ex:T", "path": "hedera:hasTreasury", "sourceShape": "hedera:StablecoinKeyGovernanceShape", "severity": "Violation"}
+]}`
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/shapes/token.shacl.ttl": string(shapes),
+		"tests/shacl/a-token.ttl":         data,
+		"tests/shacl/a-token.json":        expected,
+		"tests/shacl/b-token.ttl":         data,
+		"tests/shacl/b-token.json":        `{"violations": []}`,
+	})
+	report := `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix hedera: <https://bhash.dev/hedera/core/> .
+[ a sh:ValidationReport ;
+  sh:conforms false ;
+  sh:result [
+    a sh:ValidationResult ;
+    sh:focusNode <https://example.org/T> ;
+    sh:resultPath hedera:hasKeyAssignment ;
+    sh:resultSeverity sh:Violation ;
+    sh:resultMessage "Stablecoin tokens must have at least one KYC key assignment."@en ;
+    sh:sourceShape _:b7 ;
+  ] , [
+    a sh:ValidationResult ;
+    sh:focusNode <https://example.org/T> ;
+    sh:resultPath hedera:hasTreasury ;
+    sh:resultSeverity sh:Violation ;
+    sh:sourceShape _:b3 ;
+  ]
+] .
+`
+	var validated []string
+	validator := func(_ *Config, datasets []string, shapes []string, _ string) ([]byte, error) {
+		validated = append(validated, filepath.Base(datasets[0]))
+		if len(shapes) != 1 || filepath.Base(shapes[0]) != "token.shacl.ttl" {
+			t.Fatalf("unexpected shapes %v", shapes)
+		}
+		return []byte(report), nil
+	}
+	var out strings.Builder
+	err = RunShaclSuite(cfg, ShaclSuiteOptions{Validator: validator, Output: &out})
+	if err == nil || err.Error() != "shacl suite failures: b-token" {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	if !reflect.DeepEqual(validated, []string{"a-token.ttl", "b-token.ttl"}) {
+		t.Fatalf("unexpected validations: %v", validated)
+	}
+	want := "pass  a-token\nfail  b-token\n" +
+		"  + Violation https://example.org/T https://bhash.dev/hedera/core/hasKeyAssignment (https://bhash.dev/hedera/core/StablecoinKeyGovernanceShape)\n" +
+		"  + Violation https://example.org/T https://bhash.dev/hedera/core/hasTreasury (https://bhash.dev/hedera/core/StablecoinKeyGovernanceShape)\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRunShaclParsesResultsAndAppliesThreshold(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	shapes, err := os.ReadFile(filepath.Join("..", "..", "ontology", "shapes", "token.shacl.ttl"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/shapes/token.shacl.ttl": string(shapes),
+		"ontology/examples/token.ttl":     "@prefix ex: <https://example.org/> .\n@prefix hedera: <https://bhash.dev/hedera/core/> .\n\nex:T a hedera:StablecoinToken ;\n  hedera:tokenSymbol \"T\" .\n",
+	})
+	report := `@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix hedera: <https://bhash.dev/hedera/core/> .
+[ a sh:ValidationReport ;
+  sh:conforms false ;
+  sh:result [
+    a sh:ValidationResult ;
+    sh:focusNode <https://example.org/T> ;
+    sh:resultPath hedera:hasKeyAssignment ;
+    sh:resultSeverity sh:Warning ;
+    sh:resultMessage "Stablecoin tokens must have at least one KYC key assignment."@en ;
+    sh:sourceShape _:b7 ;
+    sh:sourceConstraintComponent sh:MinCountConstraintComponent ;
+  ]
+] .
+`
+	validator := func(_ *Config, datasets []string, _ []string, _ string) ([]byte, error) {
+		if len(datasets) != 1 || filepath.Base(datasets[0]) != "token.ttl" {
+			t.Fatalf("unexpected datasets %v", datasets)
+		}
+		return []byte(report), nil
+	}
+
+	result, err := RunShacl(cfg, ShaclOptions{Validator: validator})
+	if err != nil {
+		t.Fatalf("RunShacl: %v", err)
+	}
+	want := ShaclResult{
+		FocusNode:   "https://example.org/T",
+		Path:        "https://bhash.dev/hedera/core/hasKeyAssignment",
+		Message:     "Stablecoin tokens must have at least one KYC key assignment.",
+		Severity:    "Warning",
+		SourceShape: "https://bhash.dev/hedera/core/StablecoinKeyGovernanceShape",
+		Constraint:  "http://www.w3.org/ns/shacl#MinCountConstraintComponent",
+		Module:      "token",
+		ShapeFile:   "ontology/shapes/token.shacl.ttl",
+		DataFile:    "ontology/examples/token.ttl",
+		DataLine:    4,
+	}
+	if !reflect.DeepEqual(result.Results, []ShaclResult{want}) {
+		t.Fatalf("unexpected results: %+v", result.Results)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.BuildDir, "reports", "shacl-report.ttl")); err != nil {
+		t.Fatalf("raw report not written: %v", err)
+	}
+
+	var text strings.Builder
+	if err := result.WriteText(&text); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	wantText := "token — ontology/shapes/token.shacl.ttl\n" +
+		"  hedera:StablecoinKeyGovernanceShape\n" +
+		"    Warning   ex:T hedera:hasKeyAssignment: Stablecoin tokens must have at least one KYC key assignment. (ontology/examples/token.ttl:4)\n" +
+		"1 results: 0 violations, 1 warnings, 0 info\n"
+	if text.String() != wantText {
+		t.Fatalf("unexpected text:\n%s", text.String())
+	}
+
+	var sarif bytes.Buffer
+	if err := result.WriteSARIF(&sarif); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	sarifResult := log.Runs[0].Results[0]
+	location := sarifResult.Locations[0].PhysicalLocation
+	if sarifResult.RuleID != "hedera:StablecoinKeyGovernanceShape" || sarifResult.Level != "warning" ||
+		location.ArtifactLocation.URI != "ontology/examples/token.ttl" || location.Region.StartLine != 4 {
+		t.Fatalf("unexpected sarif result: %+v", sarifResult)
+	}
+
+	result, err = RunShacl(cfg, ShaclOptions{Validator: validator, FailOn: "warning"})
+	if err == nil || !strings.Contains(err.Error(), "1 results at or above Warning") || result == nil {
+		t.Fatalf("expected warning threshold to fail, got %v", err)
+	}
+	if _, err := RunShacl(cfg, ShaclOptions{Validator: validator, FailOn: "fatal"}); err == nil {
+		t.Fatalf("expected unknown severity to fail")
+	}
+}
+
+func TestRunReasonExplainsUnsatisfiableModules(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl":  "@prefix owl: <http://www.w3.org/2002/07/owl#> .\n<https://bhash.dev/hedera/core> a owl:Ontology .\n",
+		"ontology/src/token.ttl": "@prefix owl: <http://www.w3.org/2002/07/owl#> .\n<https://bhash.dev/hedera/token> a owl:Ontology ;\n  owl:imports <https://bhash.dev/hedera/core> .\n",
+	})
+	var commands []string
+	robot := func(args []string) ([]byte, error) {
+		commands = append(commands, args[2]+" "+filepath.Base(args[6]))
+		if args[2] == "explain" {
+			path := args[len(args)-1]
+			if err := os.WriteFile(path, []byte("# Unsatisfiable: Stablecoin\n\nStablecoin SubClassOf NonFungibleToken\n"), 0o644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			return nil, nil
+		}
+		if strings.HasSuffix(args[6], "token.ttl") {
+			return []byte("ERROR There are 1 unsatisfiable classes in the ontology.\nERROR     unsatisfiable: https://bhash.dev/hedera/core/Stablecoin\n"), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+
+	var out strings.Builder
+	results, err := RunReason(cfg, ReasonOptions{Reasoner: "hermit", Robot: robot, Output: &out})
+	if err == nil || err.Error() != "reasoning failed: token" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(commands, []string{"reason core.ttl", "reason token.ttl", "explain token.ttl"}) {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+	if len(results) != 2 || results[0].Status != ReasonCoherent || results[1].Status != ReasonUnsatisfiable ||
+		!reflect.DeepEqual(results[1].Unsatisfiable, []string{"https://bhash.dev/hedera/core/Stablecoin"}) {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	want := "ok    core (HermiT) -> build/reasoned/core.ttl\n" +
+		"fail  token: 1 unsatisfiable classes: https://bhash.dev/hedera/core/Stablecoin\n" +
+		"    # Unsatisfiable: Stablecoin\n\n    Stablecoin SubClassOf NonFungibleToken\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	catalog, err := os.ReadFile(filepath.Join(cfg.BuildDir, "catalog-v001.xml"))
+	if err != nil || !strings.Contains(string(catalog), `name="https://bhash.dev/hedera/core"`) {
+		t.Fatalf("catalog missing core mapping: %v\n%s", err, catalog)
+	}
+	if _, err := RunReason(cfg, ReasonOptions{Reasoner: "pellet", Robot: robot}); err == nil {
+		t.Fatalf("expected unsupported reasoner error")
+	}
+}
+
+func TestRunReportFailsOnlyOnNewErrors(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl":      "@prefix owl: <http://www.w3.org/2002/07/owl#> .\n<https://bhash.dev/hedera/core> a owl:Ontology .\n",
+		"ontology/src/token.ttl":     "@prefix owl: <http://www.w3.org/2002/07/owl#> .\n<https://bhash.dev/hedera/token> a owl:Ontology .\n",
+		"tests/report/levels.txt":    "# demoted\nINFO\tmissing_ontology_description\n",
+		"tests/report/baseline.json": `[{"module": "core", "level": "ERROR", "rule": "missing_label", "subject": "hedera:Account"}]`,
+	})
+	tsv := map[string]string{
+		"core.ttl":  "Level\tRule Name\tSubject\tProperty\tValue\nERROR\tmissing_label\thedera:Account\t\t\nWARN\tmissing_ontology_description\thedera:core\tdcterms:description\t\n",
+		"token.ttl": "Level\tRule Name\tSubject\tProperty\tValue\nERROR\tmissing_label\thedera:Token\t\t\n",
+	}
+	robot := func(args []string) ([]byte, error) {
+		var input, output, profile string
+		for i := 0; i+1 < len(args); i++ {
+			switch args[i] {
+			case "--input":
+				input = args[i+1]
+			case "--output":
+				output = args[i+1]
+			case "--profile":
+				profile = args[i+1]
+			}
+		}
+		if profile != "" {
+			t.Fatalf("expected ROBOT's default profile, got %q", profile)
+		}
+		return nil, os.WriteFile(output, []byte(tsv[filepath.Base(input)]), 0o644)
+	}
+
+	var out strings.Builder
+	reports, err := RunReport(cfg, ReportOptions{Robot: robot, Output: &out})
+	if err == nil || err.Error() != "robot report found new errors: token (1)" {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	if len(reports) != 2 || len(reports[0].New) != 1 || reports[0].New[0].Level != "INFO" {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+	want := "module  errors  warnings  info  new\n" +
+		"core         1         0     1  1\n" +
+		"token        1         0     0  1\n" +
+		"new in core (build/reports/core-report.tsv):\n" +
+		"  INFO missing_ontology_description hedera:core dcterms:description\n" +
+		"new in token (build/reports/token-report.tsv):\n" +
+		"  ERROR missing_label hedera:Token\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	if _, err := RunReport(cfg, ReportOptions{Modules: []string{"token"}, Robot: robot, UpdateBaseline: true}); err != nil {
+		t.Fatalf("RunReport --update-baseline: %v", err)
+	}
+	if _, err := RunReport(cfg, ReportOptions{Robot: robot}); err != nil {
+		t.Fatalf("expected accepted errors to pass: %v", err)
+	}
+}
+
+func TestRunDocsRendersTermsShapesQueriesAndMappings(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	prefixes := "@prefix hedera: <https://bhash.dev/hedera/core/> .\n@prefix owl: <http://www.w3.org/2002/07/owl#> .\n" +
+		"@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .\n@prefix skos: <http://www.w3.org/2004/02/skos/core#> .\n"
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl": prefixes + `<https://bhash.dev/hedera/core> a owl:Ontology .
+hedera:Token a owl:Class ; rdfs:label "Token"@en ;
+  skos:definition "Digital asset."@en ;
+  hedera:sourceDocument <https://docs.hedera.com/tokens> .
+hedera:StablecoinToken a owl:Class ; rdfs:label "Stablecoin token"@en ; rdfs:subClassOf hedera:Token .
+hedera:hasTreasury a owl:ObjectProperty ; rdfs:label "has treasury"@en ; rdfs:domain hedera:Token .
+`,
+		"ontology/src/alignment/aiao.ttl": prefixes + `@prefix aiao: <https://datadudes.xyz/ontology/aiao#> .
+<https://bhash.dev/hedera/alignment/aiao> a owl:Ontology .
+hedera:ReserveAssertion a owl:Class ; rdfs:subClassOf hedera:Token , aiao:ImpactAssertion .
+`,
+		"ontology/shapes/token.shacl.ttl": "@prefix sh: <http://www.w3.org/ns/shacl#> .\n@prefix hedera: <https://bhash.dev/hedera/core/> .\n" +
+			"hedera:TokenShape a sh:NodeShape ; sh:targetClass hedera:StablecoinToken ; sh:property [ sh:path hedera:hasTreasury ; sh:minCount 1 ] .\n",
+		"tests/queries/cq-comp-003.rq": "# Uses hedera:Token in a comment only\nPREFIX h: <https://bhash.dev/hedera/core/>\nSELECT ?t WHERE { ?t a h:StablecoinToken }\n",
+	})
+
+	dir, err := RunDocs(cfg, DocsOptions{})
+	if err != nil {
+		t.Fatalf("RunDocs: %v", err)
+	}
+	read := func(rel string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		return string(data)
+	}
+	for rel, wants := range map[string][]string{
+		"terms/hedera-Token.html": {
+			"<dd>Digital asset.</dd>",
+			`<a href="https://docs.hedera.com/tokens">`,
+			`<dt>Subclasses</dt><dd><a href="../terms/hedera-ReserveAssertion.html">hedera:ReserveAssertion</a>, <a href="../terms/hedera-StablecoinToken.html" title="Stablecoin token">hedera:StablecoinToken</a></dd>`,
+			`<dt>Properties with this domain</dt><dd><a href="../terms/hedera-hasTreasury.html" title="has treasury">hedera:hasTreasury</a></dd>`,
+		},
+		"terms/hedera-StablecoinToken.html": {
+			"<li>hedera:TokenShape <span class=\"meta\">(ontology/shapes/token.shacl.ttl)</span></li>",
+			"<li>cq-comp-003 <span class=\"meta\">(tests/queries/cq-comp-003.rq)</span></li>",
+		},
+		"terms/hedera-hasTreasury.html": {"hedera:TokenShape", "Object property in"},
+		"mappings.html": {
+			`<td><a href="terms/hedera-ReserveAssertion.html">hedera:ReserveAssertion</a></td><td><code>rdfs:subClassOf</code></td><td><a href="https://datadudes.xyz/ontology/aiao#ImpactAssertion">aiao:ImpactAssertion</a></td>`,
+		},
+		"index.html": {`<a href="terms/hedera-Token.html">hedera:Token</a> <span class="kind">Class</span> — Token`},
+	} {
+		page := read(rel)
+		for _, want := range wants {
+			if !strings.Contains(page, want) {
+				t.Errorf("%s missing %q:\n%s", rel, want, page)
+			}
+		}
+	}
+	if strings.Contains(read("terms/hedera-Token.html"), "cq-comp-003") {
+		t.Errorf("query comment should not reference hedera:Token")
+	}
+}
+
+func TestRunDocsKeepsSlugsDistinctAcrossCase(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl": `@prefix dcat: <http://www.w3.org/ns/dcat#> .
+@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+<https://bhash.dev/hedera/core> a owl:Ontology .
+dcat:Distribution a owl:Class ; rdfs:label "Distribution"@en .
+dcat:distribution a owl:ObjectProperty ; rdfs:label "distribution"@en .
+hedera:Token a owl:Class .
+`,
+	})
+
+	dir, err := RunDocs(cfg, DocsOptions{})
+	if err != nil {
+		t.Fatalf("RunDocs: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "terms"))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	folded := map[string]string{}
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Name())
+		if other, ok := folded[key]; ok {
+			t.Fatalf("%s and %s collide on a case-insensitive filesystem", other, entry.Name())
+		}
+		folded[key] = entry.Name()
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected three term pages, got %d", len(entries))
+	}
+	if _, ok := folded["hedera-token.html"]; !ok {
+		t.Fatalf("expected a term without a collision to keep its plain slug, got %v", folded)
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, entry := range entries {
+		page, err := os.ReadFile(filepath.Join(dir, "terms", entry.Name()))
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		name := "dcat:Distribution"
+		if strings.Contains(string(page), "Object property") {
+			name = "dcat:distribution"
+		} else if strings.HasPrefix(entry.Name(), "hedera-") {
+			name = "hedera:Token"
+		}
+		if link := `<a href="terms/` + entry.Name() + `">` + name + `</a>`; !strings.Contains(string(index), link) {
+			t.Errorf("index.html missing %s", link)
+		}
+	}
+}
+
+func TestRunTemplatesReportsDriftFromModules(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+hedera:Token a owl:Class ; rdfs:label "Token"@en ;
+  hedera:sourceDocument <https://docs.hedera.com/tokens> .
+hedera:hasTreasury a owl:ObjectProperty .
+`,
+		"templates/example.csv":   "ID,LABEL,TYPE\nID,LABEL,TYPE\n",
+		"docs/mappings/token.csv": "Term,Source Document,Notes\nhedera:Token,https://docs.hedera.com/tokens,ok\nhedera:hasTreasury,https://hips.hedera.com/hip/hip-540,stale\n",
+	})
+	generated := map[string]string{
+		"example.csv": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+hedera:Token a owl:Class ; rdfs:label "Token" .
+<https://bhash.dev/hedera/consensus/SampleTopic> a owl:Class .
+`,
+		"token.csv": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+hedera:Token a owl:Class ; hedera:sourceDocument <https://docs.hedera.com/tokens> .
+hedera:hasTreasury a owl:ObjectProperty ; hedera:sourceDocument <https://hips.hedera.com/hip/hip-540> .
+`,
+	}
+	var mappingTemplate string
+	robot := func(args []string) ([]byte, error) {
+		output := args[len(args)-1]
+		for i, arg := range args {
+			if arg == "template" {
+				template := args[i+2]
+				if strings.Contains(template, "mappings") {
+					data, err := os.ReadFile(template)
+					if err != nil {
+						t.Fatalf("ReadFile: %v", err)
+					}
+					mappingTemplate = string(data)
+				}
+				if args[0] != "--prefix" || args[1] != "hedera: https://bhash.dev/hedera/core/" {
+					t.Fatalf("unexpected prefixes %v", args[:i])
+				}
+				return nil, os.WriteFile(output, []byte(generated[filepath.Base(template)]), 0o644)
+			}
+		}
+		return nil, os.WriteFile(output, nil, 0o644)
+	}
+
+	var out strings.Builder
+	if _, err := RunTemplates(cfg, TemplateOptions{Robot: robot, Output: &out}); err != nil {
+		t.Fatalf("RunTemplates: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "1 terms authored only in templates: https://bhash.dev/hedera/consensus/SampleTopic") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	drift, err := RunTemplates(cfg, TemplateOptions{Mappings: true, Robot: robot, Output: &out})
+	if err == nil || err.Error() != "templates drift from ontology modules: 1 statements" {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	want := TemplateDrift{Template: "docs/mappings/token.csv", Term: "hedera:hasTreasury", Predicate: "hedera:sourceDocument", Value: "https://hips.hedera.com/hip/hip-540"}
+	if !reflect.DeepEqual(drift, []TemplateDrift{want}) {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+	wantTemplate := "ID,Type,Source Document\nID,TYPE,AI hedera:sourceDocument\n" +
+		"hedera:Token,owl:Class,https://docs.hedera.com/tokens\nhedera:hasTreasury,owl:ObjectProperty,https://hips.hedera.com/hip/hip-540\n"
+	if mappingTemplate != wantTemplate {
+		t.Fatalf("unexpected mapping template:\n%s", mappingTemplate)
+	}
+}
+
+func TestCheckMappingsFlagsTermsSourcesAndCoverage(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+hedera:Token a owl:Class .
+hedera:Topic a owl:Class .
+hedera:LegacyKey a owl:Class ; owl:deprecated "true"^^xsd:boolean .
+hedera:hasTreasury a owl:ObjectProperty .
+`,
+		"ontology/src/imports/provo.ttl": `@prefix prov: <http://www.w3.org/ns/prov#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+prov:Entity a owl:Class .
+`,
+		"docs/mappings/token.csv": "Term,Source Document,Notes\n" +
+			"hedera:Token,https://docs.hedera.com/tokens,ok\n" +
+			"hedera:hasTreasury,,no source\n" +
+			"hedera:Missing,https://docs.hedera.com/missing,unknown\n" +
+			"hedera:LegacyKey,docs/keys.md,deprecated and relative\n" +
+			"ex:Thing,https://example.org,undeclared prefix\n",
+	})
+
+	check, err := CheckMappings(cfg)
+	if err != nil {
+		t.Fatalf("CheckMappings: %v", err)
+	}
+	want := []string{
+		"docs/mappings/token.csv:3: hedera:hasTreasury has no source document",
+		"docs/mappings/token.csv:4: unknown term hedera:Missing",
+		"docs/mappings/token.csv:5: term hedera:LegacyKey is deprecated",
+		`docs/mappings/token.csv:5: hedera:LegacyKey source document "docs/keys.md" is not an http(s) URL`,
+		"docs/mappings/token.csv:6: term ex:Thing has an undeclared prefix",
+	}
+	if strings.Join(check.Problems, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected problems:\n%s", strings.Join(check.Problems, "\n"))
+	}
+	if len(check.Coverage) != 1 {
+		t.Fatalf("expected coverage of core only, got %+v", check.Coverage)
+	}
+	core := check.Coverage[0]
+	if core.Module != "core" || core.Classes != 3 || core.Mapped != 2 || strings.Join(core.Unmapped, ",") != "hedera:Topic" {
+		t.Fatalf("unexpected coverage %+v", core)
+	}
+	var out strings.Builder
+	if err := check.WriteCoverage(&out); err != nil {
+		t.Fatalf("WriteCoverage: %v", err)
+	}
+	if !strings.Contains(out.String(), "coverage: 2/3 classes mapped") || !strings.Contains(out.String(), "unmapped: hedera:Topic") {
+		t.Fatalf("unexpected coverage output:\n%s", out.String())
+	}
+}
+
+func TestRunSparqlUpdatesFixturesAndStrict(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+	writeFiles(t, repoRoot, map[string]string{
+		"tests/queries/cq-a.rq":           "SELECT ?a WHERE {}",
+		"tests/queries/cq-b.rq":           "SELECT ?a WHERE {}",
+		"tests/queries/cq-c.rq":           "SELECT ?a WHERE {}",
+		"tests/fixtures/results/cq-a.csv": "a\n3\n",
+		"tests/fixtures/results/cq-c.csv": "a\n3\n",
+	})
+	var asked []string
+	opts := SparqlOptions{
+		Backend:        failingBackend{},
+		UpdateFixtures: true,
+		Only:           []string{"cq-a", "cq-b.*"},
+		Confirm: func(result QueryResult) bool {
+			asked = append(asked, result.Name)
+			return true
+		},
+	}
+	report, err := RunSparql(cfg, opts)
+	if err == nil || err.Error() != "sparql regression failures: cq-c.rq" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statuses []QueryStatus
+	for _, result := range report.Results {
+		statuses = append(statuses, result.Status)
+	}
+	if want := []QueryStatus{StatusUpdated, StatusUpdated, StatusFail}; !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if !reflect.DeepEqual(asked, []string{"cq-a.rq", "cq-b.rq"}) {
+		t.Fatalf("unexpected confirmations: %v", asked)
+	}
+	for _, name := range []string{"cq-a.csv", "cq-b.csv"} {
+		data, err := os.ReadFile(filepath.Join(repoRoot, "tests", "fixtures", "results", name))
+		if err != nil || string(data) != "a\n2\n1\n" {
+			t.Fatalf("fixture %s not updated: %q, %v", name, data, err)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(repoRoot, "tests", "fixtures", "results", "cq-b.csv")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	report, err = RunSparql(cfg, SparqlOptions{Backend: failingBackend{}, Strict: true})
+	if err == nil || err.Error() != "sparql regression failures: cq-b.rq, cq-c.rq" {
+		t.Fatalf("unexpected strict error: %v", err)
+	}
+	if report.Results[1].Message != "no expected results for cq-b.csv (strict)" {
+		t.Fatalf("unexpected strict result: %+v", report.Results[1])
+	}
+}
+
+func TestCompareRowsUsesTermsOrderAndBlankNodes(t *testing.T) {
+	rows := func(lines ...string) []resultRow {
+		var out []resultRow
+		for _, line := range lines {
+			row := resultRow{line: line}
+			for _, cell := range strings.Split(line, ",") {
+				row.terms = append(row.terms, parseTerm(cell))
+			}
+			out = append(out, row)
+		}
+		return out
+	}
+	cases := []struct {
+		name     string
+		expected []resultRow
+		actual   []resultRow
+		ordered  bool
+		want     *RowDiff
+	}{
+		{"numeric", rows("42000", "0.92"), rows(`"42000"^^xsd:integer`, `"0.920"^^<http://www.w3.org/2001/XMLSchema#decimal>`), false, nil},
+		{"datetime", rows("2025-10-01T00:00:00+00:00"), rows(`"2025-10-01T02:00:00+02:00"^^xsd:dateTime`), false, nil},
+		{"language", rows("Shard Alpha", `"chat"@EN`), rows(`"Shard Alpha"@en`, `"chat"@en`), false, nil},
+		{"language mismatch", rows(`"chat"@fr`), rows(`"chat"@en`), false, &RowDiff{Missing: []string{`"chat"@fr`}, Unexpected: []string{`"chat"@en`}}},
+		{"typed mismatch", rows(`"1"^^xsd:integer`), rows(`"1"^^xsd:boolean`), false, &RowDiff{Missing: []string{`"1"^^xsd:integer`}, Unexpected: []string{`"1"^^xsd:boolean`}}},
+		{"unordered", rows("a,1", "b,2"), rows("b,2", "a,1"), false, nil},
+		{"ordered", rows("a,1", "b,2"), rows("b,2", "a,1"), true, &RowDiff{Reordered: true}},
+		{"blank isomorphic", rows("_:x,_:y", "_:y,_:x"), rows("_:b1,_:b0", "_:b0,_:b1"), false, nil},
+		{"blank not isomorphic", rows("_:x,_:y", "_:y,_:x"), rows("_:b0,_:b1", "_:b0,_:b2"), false, &RowDiff{BlankNodes: true}},
+		{"missing row", rows("a", "b"), rows("a", "c"), false, &RowDiff{Missing: []string{"b"}, Unexpected: []string{"c"}}},
+	}
+	for _, tc := range cases {
+		if got := compareRows(tc.expected, tc.actual, tc.ordered); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: compareRows = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+
+	for query, want := range map[string]bool{
+		"SELECT ?a WHERE { ?a ?b ?c } ORDER BY ?a":                                      true,
+		"SELECT ?a WHERE { { SELECT ?a WHERE {} ORDER BY ?a LIMIT 1 } }":                false,
+		"SELECT ?a WHERE { ?a <x:p> \"}\" } # ORDER BY ?a":                              false,
+		"PREFIX x: <http://example.org/x#>\nSELECT ?a WHERE { ?a x:p ?b }\norder by ?b": true,
+	} {
+		if got := queryIsOrdered(query); got != want {
+			t.Errorf("queryIsOrdered(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+type fakeTransactor struct {
+	requests []fluree.TransactionRequest
+}
+
+func (f *fakeTransactor) Transact(_ context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
+	f.requests = append(f.requests, req)
+	return &fluree.TransactionReceipt{Ledger: req.Ledger, T: int64(len(f.requests))}, nil
+}
+
+func TestLoadOntologyTransactsModulesAndExamples(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := NewConfig(repoRoot)
+
+	writeFiles(t, repoRoot, map[string]string{
+		"ontology/src/core.ttl": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+hedera:Account a owl:Class .
+`,
+		"ontology/src/token.ttl": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+hedera:Token a owl:Class .
+hedera:TokenTransfer a owl:Class .
+hedera:hasTreasury a owl:ObjectProperty .
+`,
+		"ontology/examples/token-compliance.ttl": `@prefix ex: <https://example.org/token/> .
+@prefix hedera: <https://bhash.dev/hedera/core/> .
+ex:usdh a hedera:Token .
+`,
+		"ontology/examples/core-consensus.ttl": `@prefix hedera: <https://bhash.dev/hedera/core/> .
+hedera:Other a hedera:Account .
+`,
+	})
+
+	client := &fakeTransactor{}
+	var progress strings.Builder
+	result, err := LoadOntology(context.Background(), cfg, client, LoadOptions{
+		Ledger:       "tenant/ledger",
+		Modules:      []string{"token"},
+		WithExamples: true,
+		Limits:       fluree.BatchLimits{MaxNodes: 2},
+		Progress:     &progress,
+	})
+	if err != nil {
+		t.Fatalf("LoadOntology returned error: %v", err)
+	}
+	wantFiles := []string{"ontology/src/token.ttl", "ontology/examples/token-compliance.ttl"}
+	if !reflect.DeepEqual(result.Files, wantFiles) {
+		t.Fatalf("unexpected files: %v", result.Files)
+	}
+	if len(client.requests) != 3 || len(result.Batches) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(client.requests))
+	}
+	if client.requests[0].Insert[0]["@id"] != "hedera:Token" || client.requests[2].Insert[0]["@id"] != "ex:usdh" {
+		t.Fatalf("unexpected transaction order: %+v", client.requests)
+	}
+	if result.Context["ex"] != "https://example.org/token/" {
+		t.Fatalf("shared context missing example prefix: %v", result.Context)
+	}
+	if !strings.Contains(progress.String(), "ontology/src/token.ttl: batch 2/2 (1 nodes") {
+		t.Fatalf("unexpected progress: %s", progress.String())
+	}
+
+	if _, err := LoadOntology(context.Background(), cfg, client, LoadOptions{Ledger: "tenant/ledger", Modules: []string{"missing"}}); err == nil {
+		t.Fatalf("expected unknown module error")
+	}
+}
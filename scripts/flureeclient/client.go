@@ -19,7 +19,8 @@ type Client struct {
 // New returns a logging-aware client using the provided configuration.
 // If logger is nil a logger writing to io.Discard is used. When httpClient
 // is nil the default HTTP client is wrapped with logging instrumentation.
-func New(cfg fluree.Config, logger *slog.Logger, httpClient *http.Client) *Client {
+// Options are forwarded to fluree.NewClient.
+func New(cfg fluree.Config, logger *slog.Logger, httpClient *http.Client, opts ...fluree.ClientOption) *Client {
 	log := logger
 	if log == nil {
 		log = slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -27,7 +28,7 @@ func New(cfg fluree.Config, logger *slog.Logger, httpClient *http.Client) *Clien
 	client := wrapHTTPClient(httpClient, log)
 	return &Client{
 		logger: log,
-		inner:  fluree.NewClient(cfg, client),
+		inner:  fluree.NewClient(cfg, client, opts...),
 	}
 }
 
@@ -44,7 +45,7 @@ func NewFromEnv(lookup func(string) (string, bool), logger *slog.Logger, httpCli
 
 // CreateDataset proxies to the underlying Fluree client and emits request
 // metadata helpful for debugging.
-func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req fluree.CreateDatasetRequest) (any, error) {
+func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req fluree.CreateDatasetRequest) (*fluree.DatasetDescriptor, error) {
 	start := time.Now()
 	c.logger.Info("fluree create-dataset", "owner", ownerHandle, "dataset", req.DatasetName)
 	resp, err := c.inner.CreateDataset(ctx, ownerHandle, req)
@@ -52,8 +53,35 @@ func (c *Client) CreateDataset(ctx context.Context, ownerHandle string, req flur
 	return resp, err
 }
 
+// ListDatasets proxies to the list-datasets endpoint.
+func (c *Client) ListDatasets(ctx context.Context, ownerHandle string) ([]fluree.DatasetDescriptor, error) {
+	start := time.Now()
+	c.logger.Info("fluree list-datasets", "owner", ownerHandle)
+	resp, err := c.inner.ListDatasets(ctx, ownerHandle)
+	c.logResult("list-datasets", start, err)
+	return resp, err
+}
+
+// DescribeDataset proxies to the describe-dataset endpoint.
+func (c *Client) DescribeDataset(ctx context.Context, ownerHandle, datasetName string) (*fluree.DatasetDescriptor, error) {
+	start := time.Now()
+	c.logger.Info("fluree describe-dataset", "owner", ownerHandle, "dataset", datasetName)
+	resp, err := c.inner.DescribeDataset(ctx, ownerHandle, datasetName)
+	c.logResult("describe-dataset", start, err)
+	return resp, err
+}
+
+// DeleteDataset proxies to the delete-dataset endpoint.
+func (c *Client) DeleteDataset(ctx context.Context, ownerHandle, datasetName string) error {
+	start := time.Now()
+	c.logger.Info("fluree delete-dataset", "owner", ownerHandle, "dataset", datasetName)
+	err := c.inner.DeleteDataset(ctx, ownerHandle, datasetName)
+	c.logResult("delete-dataset", start, err)
+	return err
+}
+
 // Transact proxies to the Fluree client with request/response logging.
-func (c *Client) Transact(ctx context.Context, req fluree.TransactionRequest) (any, error) {
+func (c *Client) Transact(ctx context.Context, req fluree.TransactionRequest) (*fluree.TransactionReceipt, error) {
 	start := time.Now()
 	c.logger.Info(
 		"fluree transact",
@@ -67,8 +95,44 @@ func (c *Client) Transact(ctx context.Context, req fluree.TransactionRequest) (a
 	return resp, err
 }
 
+// TransactStream proxies a streamed insert to the Fluree client, logging
+// each committed batch.
+func (c *Client) TransactStream(ctx context.Context, req fluree.TransactionRequest, nodes fluree.NodeReader, opts fluree.StreamOptions) (*fluree.StreamResult, error) {
+	start := time.Now()
+	c.logger.Info("fluree transact-stream", "ledger", req.Ledger, "maxNodes", opts.Limits.MaxNodes, "maxBytes", opts.Limits.MaxBytes)
+	onBatch := opts.OnBatch
+	opts.OnBatch = func(batch fluree.BatchResult) error {
+		c.logger.Info("fluree transact-stream batch", "batch", batch.Batch, "nodes", batch.Nodes, "bytes", batch.Bytes)
+		if onBatch != nil {
+			return onBatch(batch)
+		}
+		return nil
+	}
+	resp, err := c.inner.TransactStream(ctx, req, nodes, opts)
+	c.logResult("transact-stream", start, err)
+	return resp, err
+}
+
+// Query proxies an FQL query to the Fluree client.
+func (c *Client) Query(ctx context.Context, req fluree.QueryRequest, out any) error {
+	start := time.Now()
+	c.logger.Info("fluree query", "ledger", req.Ledger)
+	err := c.inner.Query(ctx, req, out)
+	c.logResult("query", start, err)
+	return err
+}
+
+// QuerySPARQL proxies a SPARQL query to the Fluree client.
+func (c *Client) QuerySPARQL(ctx context.Context, ledger, query string) (*fluree.SPARQLResults, error) {
+	start := time.Now()
+	c.logger.Info("fluree query-sparql", "ledger", ledger)
+	resp, err := c.inner.QuerySPARQL(ctx, ledger, query)
+	c.logResult("query-sparql", start, err)
+	return resp, err
+}
+
 // GeneratePrompt proxies to the generate-prompt endpoint and logs request metadata.
-func (c *Client) GeneratePrompt(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (any, error) {
+func (c *Client) GeneratePrompt(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (*fluree.PromptResponse, error) {
 	start := time.Now()
 	c.logger.Info("fluree generate-prompt", "owner", ownerHandle, "datasets", req.Datasets)
 	resp, err := c.inner.GeneratePrompt(ctx, ownerHandle, req)
@@ -77,7 +141,7 @@ func (c *Client) GeneratePrompt(ctx context.Context, ownerHandle string, req flu
 }
 
 // GenerateSPARQL proxies to the generate-sparql endpoint.
-func (c *Client) GenerateSPARQL(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (any, error) {
+func (c *Client) GenerateSPARQL(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (*fluree.SPARQLGeneration, error) {
 	start := time.Now()
 	c.logger.Info("fluree generate-sparql", "owner", ownerHandle, "datasets", req.Datasets)
 	resp, err := c.inner.GenerateSPARQL(ctx, ownerHandle, req)
@@ -86,7 +150,7 @@ func (c *Client) GenerateSPARQL(ctx context.Context, ownerHandle string, req flu
 }
 
 // GenerateAnswer proxies to the generate-answer endpoint.
-func (c *Client) GenerateAnswer(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (any, error) {
+func (c *Client) GenerateAnswer(ctx context.Context, ownerHandle string, req fluree.PromptRequest) (*fluree.Answer, error) {
 	start := time.Now()
 	c.logger.Info("fluree generate-answer", "owner", ownerHandle, "datasets", req.Datasets)
 	resp, err := c.inner.GenerateAnswer(ctx, ownerHandle, req)
@@ -64,9 +64,8 @@ func TestGeneratePromptLogsAndSetsHeaders(t *testing.T) {
 		t.Fatalf("unexpected datasets payload: %#v", payload["datasets"])
 	}
 
-	decoded := resp.(map[string]any)
-	if decoded["prompt"].(string) != "SELECT *" {
-		t.Fatalf("unexpected response payload: %#v", decoded)
+	if resp.Prompt != "SELECT *" {
+		t.Fatalf("unexpected response payload: %#v", resp)
 	}
 }
 